@@ -4,8 +4,10 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -14,108 +16,180 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/rohan03122001/quizzing/internal/config"
 	"github.com/rohan03122001/quizzing/internal/handlers"
+	"github.com/rohan03122001/quizzing/internal/logging"
+	"github.com/rohan03122001/quizzing/internal/metrics"
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+	"github.com/rohan03122001/quizzing/internal/ratelimit"
 	"github.com/rohan03122001/quizzing/internal/repository"
 	"github.com/rohan03122001/quizzing/internal/service"
+	"github.com/rohan03122001/quizzing/internal/tracing"
 	"github.com/rohan03122001/quizzing/internal/websocket"
 )
 
 func main() {
-    // Set Gin mode based on environment
-    ginMode := os.Getenv("GIN_MODE")
-    if ginMode == "" {
-        ginMode = gin.DebugMode
+    // Settings come from config.yaml, the environment and flags
+    cfg, args, err := config.Load(os.Args[1:])
+    if errors.Is(err, flag.ErrHelp) {
+        return
     }
-    gin.SetMode(ginMode)
-
-    // Get database configuration from environment variables
-    dbConfig, err := config.GetDBConfig()
     if err != nil {
-        log.Fatalf("Failed to get database config: %v", err)
+        fatal("Failed to load configuration", "err", err)
+    }
+    gin.SetMode(cfg.Server.Mode)
+    if err := logging.Setup(os.Stderr, cfg.Log.Level, cfg.Log.Format); err != nil {
+        fatal("Failed to set up logging", "err", err)
+    }
+    shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+        Exporter:    cfg.Tracing.Exporter,
+        Endpoint:    cfg.Tracing.Endpoint,
+        SampleRatio: cfg.Tracing.SampleRatio,
+        Stdout:      os.Stdout,
+    })
+    if err != nil {
+        fatal("Failed to set up tracing", "err", err)
     }
 
+    // Subcommands run against the database and exit
+    if len(args) > 0 {
+        switch args[0] {
+        case "questions":
+            err = runQuestionsCommand(cfg, args[1:])
+        case "migrate":
+            err = runMigrateCommand(cfg, args[1:])
+        default:
+            err = fmt.Errorf("unknown command %q, expected questions or migrate", args[0])
+        }
+        if err != nil {
+            fatal("Command failed", "command", args[0], "err", err)
+        }
+        return
+    }
+
+    // Metrics are served from their own registry on /metrics
+    registry := prometheus.NewRegistry()
+    registry.MustRegister(
+        collectors.NewGoCollector(),
+        collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+    )
+    appMetrics := metrics.New(registry)
+
     // Initialize database
-    db, err := repository.NewDatabase(&repository.DBConfig{
-        Host:     dbConfig.Host,
-        Port:     strconv.Itoa(dbConfig.Port),
-        User:     dbConfig.User,
-        Password: dbConfig.Password,
-        DBName:   dbConfig.DBName,
-        SSLMode:  "disable",
-    })
+    db, err := openDatabase(cfg)
     if err != nil {
-        log.Fatalf("Failed to initialize database: %v", err)
+        fatal("Failed to initialize database", "err", err)
+    }
+    if err := db.SetMetrics(appMetrics); err != nil {
+        fatal("Failed to initialize database", "err", err)
+    }
+    if err := db.SetTracing(tracing.Tracer()); err != nil {
+        fatal("Failed to initialize database", "err", err)
     }
 
     // Initialize WebSocket hub
     hub := websocket.NewHub()
+    hub.SetReconnectWindow(cfg.Hub.ReconnectWindow)
+    hub.SetMetrics(appMetrics)
     go hub.Run()
 
     // Initialize repositories
     roomRepo := repository.NewRoomRepository(db)
     questionRepo := repository.NewQuestionRepository(db)
     roundRepo := repository.NewGameRoundRepository(db)
+    historyRepo := repository.NewHistoryRepository(db)
+    accountRepo := repository.NewAccountRepository(db)
+    leaderboardRepo := repository.NewLeaderboardRepository(db)
+    packRepo := repository.NewPackRepository(db)
+    appMetrics.WatchRooms(roomRepo.CountByStatus)
 
     // Initialize services
     roomService := service.NewRoomService(roomRepo, hub)
+    roomService.SetDefaults(models.GameSettings{
+        MaxPlayers:       cfg.Game.MaxPlayers,
+        RoundTime:        cfg.Game.RoundTime,
+        MaxRounds:        cfg.Game.MaxRounds,
+        IntermissionTime: cfg.Game.IntermissionTime,
+    })
     
     // Set room service on hub for activity updates
     hub.SetRoomService(roomService)
     
-    gameService := service.NewGameService(roomRepo, questionRepo, roundRepo, hub)
-    cleanupService := service.NewCleanupService(roomRepo, hub)
+    gameService := service.NewGameService(roomRepo, questionRepo, roundRepo, historyRepo, leaderboardRepo, hub)
+    gameService.SetMetrics(appMetrics)
+    historyService := service.NewHistoryService(historyRepo)
+    accountService := service.NewAccountService(accountRepo)
+    leaderboardService := service.NewLeaderboardService(leaderboardRepo)
+    questionService := service.NewQuestionService(questionRepo, historyRepo)
+    packService := service.NewPackService(packRepo, roomRepo)
+
+    // Accounts listed in server.admin_usernames can manage the question bank
+    for _, username := range cfg.Server.AdminUsernames {
+        if err := accountService.GrantAdmin(username); err != nil {
+            slog.Warn("Could not grant admin", "username", username, "err", err)
+        }
+    }
+
+    cleanupService := service.NewCleanupService(roomRepo, hub, cfg.Cleanup.Interval, cfg.Cleanup.InactiveTimeout)
+    cleanupService.SetMetrics(appMetrics)
     cleanupService.StartCleanupRoutine()
 
     // Initialize handlers
     httpHandler := handlers.NewHTTPHandler(roomService)
-    gameHandler := handlers.NewGameHandler(gameService, roomService, hub)
-    wsHandler := handlers.NewWebSocketHandler(hub, gameHandler)
+    gameHandler := handlers.NewGameHandler(gameService, roomService, accountService, hub)
+    gameHandler.SetRateLimits(connectionLimits(cfg.RateLimit))
+    wsHandler := handlers.NewWebSocketHandler(hub, gameHandler, accountService, cfg.CORS.AllowedOrigins)
+    sseHandler := handlers.NewSSEHandler(hub, gameHandler, accountService)
+    historyHandler := handlers.NewHistoryHandler(historyService)
+    authHandler := handlers.NewAuthHandler(accountService)
+    authHandler.LogMagicLinks(cfg.Auth.LogMagicLinks)
+    leaderboardHandler := handlers.NewLeaderboardHandler(leaderboardService)
+    adminHandler := handlers.NewAdminHandler(questionService, accountService)
+    packHandler := handlers.NewPackHandler(packService, accountService)
+    metricsHandler := handlers.NewMetricsHandler(registry)
+    healthHandler := handlers.NewHealthHandler(db.Ping, roomService.Draining)
 
     // Setup Gin router
-    router := gin.Default()
+    router := gin.New()
+    // Client IPs, which the rate limits go by, only come from the
+    // X-Forwarded-For of known proxies
+    if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+        fatal("Invalid trusted proxies", "err", err)
+    }
+    router.Use(gin.Recovery(), handlers.Tracing(), handlers.RequestLogger())
+    router.Use(handlers.CORS(cfg.CORS.AllowedOrigins))
+    router.Use(
+        handlers.RateLimit(ratelimit.NewKeyed(rateOf(cfg.RateLimit.Requests))),
+        handlers.RateLimit(ratelimit.NewKeyed(rateOf(cfg.RateLimit.Connections)), "GET /ws", "GET /sse"),
+        handlers.RateLimit(ratelimit.NewKeyed(rateOf(cfg.RateLimit.CreateRoom)), "POST /api/rooms"),
+    )
 
     // Register routes
     httpHandler.RegisterRoutes(router)
     wsHandler.RegisterRoutes(router)
-
-    // Enhanced CORS middleware
-    router.Use(func(c *gin.Context) {
-        // Get allowed origins from environment or use default
-        allowedOrigin := os.Getenv("ALLOWED_ORIGIN")
-        if allowedOrigin == "" {
-            allowedOrigin = "*" // Default to allow all in development
-        }
-        
-        c.Writer.Header().Set("Access-Control-Allow-Origin", allowedOrigin)
-        c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS, PUT, DELETE")
-        c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-        c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-        
-        if c.Request.Method == "OPTIONS" {
-            c.AbortWithStatus(204)
-            return
-        }
-        c.Next()
-    })
-
-    // Get port from environment variable or use default
-    port := os.Getenv("PORT")
-    if port == "" {
-        port = "8080" // Default port
-    }
+    sseHandler.RegisterRoutes(router)
+    historyHandler.RegisterRoutes(router)
+    authHandler.RegisterRoutes(router)
+    leaderboardHandler.RegisterRoutes(router)
+    adminHandler.RegisterRoutes(router)
+    packHandler.RegisterRoutes(router)
+    metricsHandler.RegisterRoutes(router)
+    healthHandler.RegisterRoutes(router)
 
     // Create server
     srv := &http.Server{
-        Addr:    fmt.Sprintf(":%s", port),
+        Addr:    fmt.Sprintf(":%d", cfg.Server.Port),
         Handler: router,
     }
 
     // Start server in goroutine
     go func() {
-        log.Printf("Server starting on port %s", port)
+        slog.Info("Server starting", "port", cfg.Server.Port)
         if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-            log.Fatalf("Failed to start server: %v", err)
+            fatal("Failed to start server", "err", err)
         }
     }()
 
@@ -123,15 +197,95 @@ func main() {
     quit := make(chan os.Signal, 1)
     signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
     <-quit
-    log.Println("Shutting down server...")
+    slog.Info("Shutting down server")
 
-    // Give 5 seconds for graceful shutdown
-    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    // Stop taking new games and let the running ones finish. A second
+    // signal skips the wait.
+    roomService.Drain()
+    deadline := time.Now().Add(cfg.Server.DrainTimeout)
+    hub.BroadcastToAll(websocket.GameEvent{
+        Type: protocol.EventServerShutdown,
+        Data: protocol.ServerShutdownData{
+            Message:    "The server is restarting. Games underway can finish; new games can't start.",
+            Deadline:   deadline.UnixMilli(),
+            ServerTime: time.Now().UnixMilli(),
+        },
+    })
+
+    drainCtx, cancelDrain := context.WithDeadline(context.Background(), deadline)
+    go func() {
+        <-quit
+        cancelDrain()
+    }()
+    if err := gameService.WaitForGames(drainCtx); err != nil {
+        gameService.Checkpoint(context.Background())
+    }
+    cancelDrain()
+    hub.DisconnectAll()
+
+    // Give open requests time to finish
+    ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
     defer cancel()
 
     if err := srv.Shutdown(ctx); err != nil {
-        log.Fatal("Server forced to shutdown:", err)
+        fatal("Server forced to shutdown", "err", err)
+    }
+
+    // Send the spans still buffered
+    if err := shutdownTracing(ctx); err != nil {
+        slog.Warn("Failed to flush traces", "err", err)
     }
 
-    log.Println("Server exited")
-}
\ No newline at end of file
+    slog.Info("Server exited")
+}
+
+// fatal logs an error and exits
+func fatal(msg string, args ...interface{}) {
+    slog.Error(msg, args...)
+    os.Exit(1)
+}
+
+// connectionLimits are the configured limits on each connection's messages
+func connectionLimits(cfg config.RateLimit) ratelimit.Limits {
+    events := make(map[string]ratelimit.Rate, len(cfg.Events))
+    for event, r := range cfg.Events {
+        events[event] = rateOf(r)
+    }
+    return ratelimit.Limits{
+        Messages:   rateOf(cfg.Messages),
+        Events:     events,
+        MaxStrikes: cfg.MaxStrikes,
+    }
+}
+
+func rateOf(r config.Rate) ratelimit.Rate {
+    return ratelimit.Rate{PerSecond: r.Rate, Burst: r.Burst}
+}
+
+// openDatabase connects to the configured database, and checks the schema
+// is up to date
+func openDatabase(cfg *config.Config) (*repository.Database, error) {
+    db, err := connectDatabase(cfg)
+    if err != nil {
+        return nil, err
+    }
+    if err := db.CheckSchema(); err != nil {
+        db.Close()
+        return nil, err
+    }
+    return db, nil
+}
+
+// connectDatabase connects to the configured database
+func connectDatabase(cfg *config.Config) (*repository.Database, error) {
+    return repository.NewDatabase(&repository.DBConfig{
+        Driver:   cfg.Database.Driver,
+        Host:     cfg.Database.Host,
+        Port:     strconv.Itoa(cfg.Database.Port),
+        User:     cfg.Database.User,
+        Password: cfg.Database.Password,
+        DBName:   cfg.Database.DBName,
+        SSLMode:  cfg.Database.SSLMode,
+        Path:     cfg.Database.Path,
+    })
+}
@@ -0,0 +1,108 @@
+// cmd/api/migrate.go
+
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/rohan03122001/quizzing/internal/config"
+	"github.com/rohan03122001/quizzing/internal/repository"
+)
+
+const migrateUsage = `usage:
+  api migrate up
+  api migrate down [-steps n]
+  api migrate status
+
+up applies every pending migration. down reverts the latest one, or the
+latest n. The server won't start until every migration is applied.`
+
+// runMigrateCommand applies, reverts or lists the schema migrations
+func runMigrateCommand(cfg *config.Config, args []string) error {
+    if len(args) == 0 {
+        return fmt.Errorf("missing subcommand\n%s", migrateUsage)
+    }
+
+    switch args[0] {
+    case "up":
+        return migrateUp(cfg)
+    case "down":
+        return migrateDown(cfg, args[1:])
+    case "status":
+        return migrationStatus(cfg)
+    }
+    return fmt.Errorf("unknown subcommand %q\n%s", args[0], migrateUsage)
+}
+
+func migrateUp(cfg *config.Config) error {
+    db, err := connectDatabase(cfg)
+    if err != nil {
+        return err
+    }
+    defer db.Close()
+
+    applied, err := db.MigrateUp()
+    printMigrations("Applied", applied)
+    if err != nil {
+        return err
+    }
+    if len(applied) == 0 {
+        fmt.Println("The database is up to date")
+    }
+    return nil
+}
+
+func migrateDown(cfg *config.Config, args []string) error {
+    flags := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+    steps := flags.Int("steps", 1, "how many migrations to revert")
+    if err := flags.Parse(args); err != nil {
+        return err
+    }
+    if *steps < 1 {
+        return fmt.Errorf("-steps must be at least 1")
+    }
+
+    db, err := connectDatabase(cfg)
+    if err != nil {
+        return err
+    }
+    defer db.Close()
+
+    reverted, err := db.MigrateDown(*steps)
+    printMigrations("Reverted", reverted)
+    if err != nil {
+        return err
+    }
+    if len(reverted) == 0 {
+        fmt.Println("No migrations to revert")
+    }
+    return nil
+}
+
+func migrationStatus(cfg *config.Config) error {
+    db, err := connectDatabase(cfg)
+    if err != nil {
+        return err
+    }
+    defer db.Close()
+
+    states, err := db.Migrations()
+    if err != nil {
+        return err
+    }
+    for _, state := range states {
+        status := "pending"
+        if state.AppliedAt != nil {
+            status = "applied " + state.AppliedAt.Format("2006-01-02 15:04:05")
+        }
+        fmt.Printf("%04d  %-20s %s\n", state.Version, state.Name, status)
+    }
+    return nil
+}
+
+func printMigrations(verb string, migrations []repository.Migration) {
+    for _, migration := range migrations {
+        fmt.Printf("%s %04d %s\n", verb, migration.Version, migration.Name)
+    }
+}
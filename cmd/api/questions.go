@@ -0,0 +1,143 @@
+// cmd/api/questions.go
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rohan03122001/quizzing/internal/config"
+	"github.com/rohan03122001/quizzing/internal/repository"
+	"github.com/rohan03122001/quizzing/internal/service"
+)
+
+const questionsUsage = `usage:
+  api questions import [-format csv|json|sql] [-apply] <file>
+  api questions export [-format csv|json] [-category name] [-o file]
+  api questions difficulty
+
+Imports are a dry run unless -apply is given. The format defaults to the
+file's extension. difficulty recomputes every played question's derived
+difficulty from archived games.`
+
+// runQuestionsCommand imports or exports the question bank
+func runQuestionsCommand(cfg *config.Config, args []string) error {
+    if len(args) == 0 {
+        return fmt.Errorf("missing subcommand\n%s", questionsUsage)
+    }
+
+    switch args[0] {
+    case "import":
+        return importQuestions(cfg, args[1:])
+    case "export":
+        return exportQuestions(cfg, args[1:])
+    case "difficulty":
+        return refreshDifficulty(cfg)
+    }
+    return fmt.Errorf("unknown subcommand %q\n%s", args[0], questionsUsage)
+}
+
+func importQuestions(cfg *config.Config, args []string) error {
+    flags := flag.NewFlagSet("questions import", flag.ContinueOnError)
+    format := flags.String("format", "", "csv, json or sql (default: from the file extension)")
+    apply := flags.Bool("apply", false, "add the questions; without it, only report what would happen")
+    if err := flags.Parse(args); err != nil {
+        return err
+    }
+    if flags.NArg() != 1 {
+        return fmt.Errorf("expected one file\n%s", questionsUsage)
+    }
+
+    path := flags.Arg(0)
+    if *format == "" {
+        *format = service.FormatFromFilename(path)
+    }
+
+    file, err := os.Open(path)
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+
+    questionService, err := newQuestionService(cfg)
+    if err != nil {
+        return err
+    }
+
+    result, err := questionService.Import(*format, file, !*apply)
+    if err != nil {
+        return err
+    }
+
+    for _, rowErr := range result.Errors {
+        fmt.Fprintf(os.Stderr, "%s:%d: %s\n", path, rowErr.Row, rowErr.Error)
+    }
+    for _, duplicate := range result.Duplicates {
+        fmt.Fprintf(os.Stderr, "%s:%d: skipped, %s\n", path, duplicate.Row, duplicate.Error)
+    }
+
+    switch {
+    case len(result.Errors) > 0:
+        return fmt.Errorf("%d of %d rows have errors, nothing was imported", len(result.Errors), result.Rows)
+    case result.Applied:
+        fmt.Printf("Imported %d questions (%d duplicates skipped)\n", result.Added, len(result.Duplicates))
+    default:
+        fmt.Printf("Dry run: %d new questions, %d duplicates. Run with -apply to import.\n",
+            result.Added, len(result.Duplicates))
+    }
+    return nil
+}
+
+func exportQuestions(cfg *config.Config, args []string) error {
+    flags := flag.NewFlagSet("questions export", flag.ContinueOnError)
+    format := flags.String("format", service.FormatCSV, "csv or json")
+    category := flags.String("category", "", "only export this category")
+    output := flags.String("o", "", "write to this file instead of stdout")
+    if err := flags.Parse(args); err != nil {
+        return err
+    }
+
+    questionService, err := newQuestionService(cfg)
+    if err != nil {
+        return err
+    }
+
+    var w io.Writer = os.Stdout
+    if *output != "" {
+        file, err := os.Create(*output)
+        if err != nil {
+            return err
+        }
+        defer file.Close()
+        w = file
+    }
+
+    return questionService.Export(*format, w, repository.QuestionFilter{Category: *category})
+}
+
+func refreshDifficulty(cfg *config.Config) error {
+    questionService, err := newQuestionService(cfg)
+    if err != nil {
+        return err
+    }
+
+    count, err := questionService.RefreshDerivedDifficulties()
+    if err != nil {
+        return err
+    }
+    fmt.Printf("Updated the derived difficulty of %d questions\n", count)
+    return nil
+}
+
+func newQuestionService(cfg *config.Config) (*service.QuestionService, error) {
+    db, err := openDatabase(cfg)
+    if err != nil {
+        return nil, err
+    }
+    return service.NewQuestionService(
+        repository.NewQuestionRepository(db),
+        repository.NewHistoryRepository(db),
+    ), nil
+}
@@ -1,82 +1,82 @@
--- Now insert the formatted questions with gen_random_uuid() for the id
+-- Fixed ids and CURRENT_TIMESTAMP keep this loadable on Postgres and SQLite alike
 INSERT INTO questions (id, content, answer, category, created_at) VALUES
-(gen_random_uuid(), 'Which Indian actress starred in ''Quantico'' and ''Baywatch''?', 'Priyanka Chopra', 'Celebrities', NOW()),
-(gen_random_uuid(), 'Which Indian YouTuber is known for ''BB Ki Vines''?', 'Bhuvan Bam', 'Internet', NOW()),
-(gen_random_uuid(), 'Which song by Tony Kakkar is often joked about for repetitive lyrics?', 'Coca Cola Tu', 'Music', NOW()),
-(gen_random_uuid(), 'Who composed the Oscar-winning song ''Jai Ho''?', 'A. R. Rahman', 'Music', NOW()),
-(gen_random_uuid(), 'Who is known for the dialogue ''Pushpa, I hate tears''?', 'Rajesh Khanna', 'Celebrities', NOW()),
-(gen_random_uuid(), 'Which TV serial revolves around the Maheshwari family in Udaipur?', 'Yeh Rishta Kya Kehlata Hai', 'TV', NOW()),
-(gen_random_uuid(), 'Which Indian festival celebrates the triumph of good over evil and burning of Ravana?', 'Dussehra', 'Culture', NOW()),
-(gen_random_uuid(), 'Which Punjabi singer had hits like ''Lamberghini'' and ''Brown Munde''?', 'AP Dhillon', 'Music', NOW()),
-(gen_random_uuid(), 'Which traditional attire is worn by men in Kerala?', 'Mundu', 'Culture', NOW()),
-(gen_random_uuid(), 'Who is known as the ''Little Master''?', 'Sachin Tendulkar', 'Sports', NOW()),
-(gen_random_uuid(), 'Which Indian actor is associated with the ''Vimal Elaichi'' meme trend?', 'Ajay Devgn', 'Memes', NOW()),
-(gen_random_uuid(), 'Which Indian influencer is famous for ''Sasta Vicky Kaushal'' memes?', 'Hasnain Khan', 'Internet', NOW()),
-(gen_random_uuid(), 'Which actor played Bhallaladeva in ''Baahubali''?', 'Rana Daggubati', 'Bollywood', NOW()),
-(gen_random_uuid(), 'Which actor starred in the Hollywood film ''Life of Pi''?', 'Irrfan Khan', 'Celebrities', NOW()),
-(gen_random_uuid(), 'Which superhero show aired in the 90s and starred Mukesh Khanna?', 'Shaktimaan', 'TV', NOW()),
-(gen_random_uuid(), 'Which actor is nicknamed ''Greek God of Bollywood''?', 'Hrithik Roshan', 'Celebrities', NOW()),
-(gen_random_uuid(), 'From which show is the viral line ''Rasode mein kaun tha''?', 'Saath Nibhaana Saathiya', 'Memes', NOW()),
-(gen_random_uuid(), 'Who composed ''Why This Kolaveri Di''?', 'Anirudh Ravichander', 'Music', NOW()),
-(gen_random_uuid(), 'Which cricketer is nicknamed ''The Hitman''?', 'Rohit Sharma', 'Sports', NOW()),
-(gen_random_uuid(), 'Which footballer is captain of the Indian national team?', 'Sunil Chhetri', 'Sports', NOW()),
-(gen_random_uuid(), 'Who is the daughter of Sridevi and Boney Kapoor?', 'Janhvi Kapoor', 'Celebrities', NOW()),
-(gen_random_uuid(), 'Which TV anchor is known for saying ''The nation wants to know''?', 'Arnab Goswami', 'TV', NOW()),
-(gen_random_uuid(), 'Which movie features the iconic line ''Mogambo khush hua''?', 'Mr. India', 'Bollywood', NOW()),
-(gen_random_uuid(), 'Which Indian tennis player partnered with Leander Paes in doubles?', 'Mahesh Bhupathi', 'Sports', NOW()),
-(gen_random_uuid(), 'What is the name of India''s national song?', 'Vande Mataram', 'Culture', NOW()),
-(gen_random_uuid(), 'Which Indian actress is married to Virat Kohli?', 'Anushka Sharma', 'Celebrities', NOW()),
-(gen_random_uuid(), 'Which film won Best Picture at the Oscars and featured Indian actor Dev Patel?', 'Slumdog Millionaire', 'Bollywood', NOW()),
-(gen_random_uuid(), '''Kya karun main itni sundar hoon toh'' is associated with which influencer?', 'TikTok Girl - Garima Chaurasia', 'Memes', NOW()),
-(gen_random_uuid(), 'Who played ''Piku'' in the movie of the same name?', 'Deepika Padukone', 'Celebrities', NOW()),
-(gen_random_uuid(), 'Who won the first season of Indian Idol?', 'Abhijeet Sawant', 'Music', NOW()),
-(gen_random_uuid(), 'What viral phrase did Vipin Sahu shout while paragliding?', 'Land kara de', 'Memes', NOW()),
-(gen_random_uuid(), 'Who was India''s first female Olympic medalist?', 'Karnam Malleswari', 'Sports', NOW()),
-(gen_random_uuid(), '''Thala for a reason'' refers to which cricketer?', 'MS Dhoni', 'Memes', NOW()),
-(gen_random_uuid(), 'Which app did CarryMinati roast in a viral video?', 'TikTok', 'Internet', NOW()),
-(gen_random_uuid(), 'Who played the role of Alauddin Khilji in ''Padmaavat''?', 'Ranveer Singh', 'Bollywood', NOW()),
-(gen_random_uuid(), 'Which is India"s harvest festival in Punjab?', 'Lohri', 'Culture', NOW()),
-(gen_random_uuid(), 'In ''Kabir Singh'', what is the profession of the main character?', 'Doctor', 'Bollywood', NOW()),
-(gen_random_uuid(), 'Who is the founder of ''The Viral Fever'' (TVF)?', 'Arunabh Kumar', 'Internet', NOW()),
-(gen_random_uuid(), 'Which Hindi serial had a character called ''Komolika''?', 'Kasautii Zindagii Kay', 'TV', NOW()),
-(gen_random_uuid(), '''Hello friends, chai pi lo'' became a viral catchphrase from which platform?', 'WhatsApp', 'Memes', NOW()),
-(gen_random_uuid(), 'Which Indian actor voiced Mufasa in the Hindi version of ''The Lion King''?', 'Shah Rukh Khan', 'Celebrities', NOW()),
-(gen_random_uuid(), 'Which quiz show aired on Doordarshan and was popular in the 90s?', 'Bournvita Quiz Contest', 'TV', NOW()),
-(gen_random_uuid(), 'Who captained India to win the 2011 Cricket World Cup?', 'MS Dhoni', 'Sports', NOW()),
-(gen_random_uuid(), 'Which Indian won a gold medal at the 2020 Tokyo Olympics in javelin throw?', 'Neeraj Chopra', 'Sports', NOW()),
-(gen_random_uuid(), 'Who played the role of Sardar Udham in the 2021 film?', 'Vicky Kaushal', 'Celebrities', NOW()),
-(gen_random_uuid(), 'Which platform is known for ''ScoopWhoop'' and ''FilterCopy'' content?', 'YouTube', 'Internet', NOW()),
-(gen_random_uuid(), 'Which Indian classical dance originates from Tamil Nadu?', 'Bharatanatyam', 'Culture', NOW()),
-(gen_random_uuid(), 'Which TV show features the character Jethalal?', 'Taarak Mehta Ka Ooltah Chashmah', 'TV', NOW()),
-(gen_random_uuid(), 'What is the primary language spoken in West Bengal?', 'Bengali', 'Culture', NOW()),
-(gen_random_uuid(), 'Which educational YouTuber runs ''Physics Wallah''?', 'Alakh Pandey', 'Internet', NOW()),
-(gen_random_uuid(), 'Which 2018 movie popularized the character ''Murad'', a street rapper?', 'Gully Boy', 'Bollywood', NOW()),
-(gen_random_uuid(), 'In which festival do people smear colors on each other?', 'Holi', 'Culture', NOW()),
-(gen_random_uuid(), 'Which Bollywood scene turned meme involves ''Maa kasam, main dhoondh ke launga''?', 'Phir Hera Pheri', 'Memes', NOW()),
-(gen_random_uuid(), 'Which rapper is associated with the track ''Machayenge''?', 'Emiway Bantai', 'Music', NOW()),
-(gen_random_uuid(), 'Who hosted ''Kaun Banega Crorepati''?', 'Amitabh Bachchan', 'TV', NOW()),
-(gen_random_uuid(), 'Which Indian stand-up comedian''s joke ''Sakht Launda'' went viral?', 'Zakir Khan', 'Memes', NOW()),
-(gen_random_uuid(), 'Which city is known as the Pink City?', 'Jaipur', 'Culture', NOW()),
-(gen_random_uuid(), 'Which Indian streamer runs the channel ''Total Gaming''?', 'Ajjubhai', 'Internet', NOW()),
-(gen_random_uuid(), 'What is the name of the alien in the movie ''PK''?', 'PK', 'Bollywood', NOW()),
-(gen_random_uuid(), 'Who is known as King Khan in Bollywood?', 'Shah Rukh Khan', 'Celebrities', NOW()),
-(gen_random_uuid(), 'Which Indian bowler is known for his ''Boom Boom'' yorkers?', 'Jasprit Bumrah', 'Sports', NOW()),
-(gen_random_uuid(), 'Which comedy group created ''A Day with…'' sketches?', 'All India Bakchod', 'Internet', NOW()),
-(gen_random_uuid(), 'Who is known as the Nightingale of India?', 'Lata Mangeshkar', 'Music', NOW()),
-(gen_random_uuid(), 'What is the title of Badshah''s viral track with Jacqueline Fernandez?', 'Paani Paani', 'Music', NOW()),
-(gen_random_uuid(), 'Which movie launched Deepika Padukone''s Bollywood career?', 'Om Shanti Om', 'Bollywood', NOW()),
-(gen_random_uuid(), 'Which artist is known for the song ''Mere Gully Mein''?', 'Divine', 'Music', NOW()),
-(gen_random_uuid(), 'Which Indian platform became famous for meme sharing during 2020 lockdown?', 'Instagram', 'Internet', NOW()),
-(gen_random_uuid(), 'Which Indian boxer has a biopic titled after her?', 'Mary Kom', 'Sports', NOW()),
-(gen_random_uuid(), 'Which meme features a remix of a saree shop salesperson saying ''Just looking like a wow''?', 'Instagram Reel', 'Memes', NOW()),
-(gen_random_uuid(), 'What is the name of the ''screaming guy'' meme from ''Bigg Boss''?', 'Deepak Kalal', 'Memes', NOW()),
-(gen_random_uuid(), 'In which show is the line ''Daya, darwaza tod do'' used?', 'CID', 'TV', NOW()),
-(gen_random_uuid(), 'Which song by Dhanush became India"s biggest viral song of 2011?', 'Why This Kolaveri Di', 'Music', NOW()),
-(gen_random_uuid(), 'Who created the viral ''Pawri Ho Rahi Hai'' remix?', 'Yashraj Mukhate', 'Internet', NOW()),
-(gen_random_uuid(), 'In ''Dangal'', which sport is central to the story?', 'Wrestling', 'Bollywood', NOW()),
-(gen_random_uuid(), 'Which Indian badminton player has won a silver Olympic medal?', 'PV Sindhu', 'Sports', NOW()),
-(gen_random_uuid(), 'What"s the name of the talent hunt show judged by Karan Johar and Malaika Arora?', 'India''s Got Talent', 'TV', NOW()),
-(gen_random_uuid(), 'Which traditional dish is made during Eid in India?', 'Biryani', 'Culture', NOW()),
-(gen_random_uuid(), 'In ''3 Idiots'', what is Rancho''s real name?', 'Phunsukh Wangdu', 'Bollywood', NOW()),
-(gen_random_uuid(), 'Which Indian reality show was adapted from ''Big Brother''?', 'Bigg Boss', 'TV', NOW()),
-(gen_random_uuid(), 'Which state is famous for Bihu dance?', 'Assam', 'Culture', NOW());
\ No newline at end of file
+('0840c2fa-0d49-457c-8440-338f19b70c98', 'Which Indian actress starred in ''Quantico'' and ''Baywatch''?', 'Priyanka Chopra', 'Celebrities', CURRENT_TIMESTAMP),
+('819cdcba-207c-4cdd-a556-5db7579af09b', 'Which Indian YouTuber is known for ''BB Ki Vines''?', 'Bhuvan Bam', 'Internet', CURRENT_TIMESTAMP),
+('66bd88a7-092d-4875-bcb2-ccb27d217ae5', 'Which song by Tony Kakkar is often joked about for repetitive lyrics?', 'Coca Cola Tu', 'Music', CURRENT_TIMESTAMP),
+('7d3901c4-15ea-4462-9af0-0193a9661016', 'Who composed the Oscar-winning song ''Jai Ho''?', 'A. R. Rahman', 'Music', CURRENT_TIMESTAMP),
+('e724fab0-be96-486b-9146-eaa646023e9b', 'Who is known for the dialogue ''Pushpa, I hate tears''?', 'Rajesh Khanna', 'Celebrities', CURRENT_TIMESTAMP),
+('053cb431-98c3-4a10-8b53-587aa2321b04', 'Which TV serial revolves around the Maheshwari family in Udaipur?', 'Yeh Rishta Kya Kehlata Hai', 'TV', CURRENT_TIMESTAMP),
+('c11c878b-f420-404b-a98d-8225350bbc6b', 'Which Indian festival celebrates the triumph of good over evil and burning of Ravana?', 'Dussehra', 'Culture', CURRENT_TIMESTAMP),
+('63200bf0-0eef-4c96-8939-d81da317c342', 'Which Punjabi singer had hits like ''Lamberghini'' and ''Brown Munde''?', 'AP Dhillon', 'Music', CURRENT_TIMESTAMP),
+('3b9d5abc-fd96-4e42-9a3b-ef3c72471acc', 'Which traditional attire is worn by men in Kerala?', 'Mundu', 'Culture', CURRENT_TIMESTAMP),
+('b6408c3d-f7d8-4061-aff4-1b202706a78d', 'Who is known as the ''Little Master''?', 'Sachin Tendulkar', 'Sports', CURRENT_TIMESTAMP),
+('27134299-3f1a-4d44-9066-47e9738d3bb0', 'Which Indian actor is associated with the ''Vimal Elaichi'' meme trend?', 'Ajay Devgn', 'Memes', CURRENT_TIMESTAMP),
+('0967c526-160e-4755-b85f-1afa87d94949', 'Which Indian influencer is famous for ''Sasta Vicky Kaushal'' memes?', 'Hasnain Khan', 'Internet', CURRENT_TIMESTAMP),
+('b5a4e82e-2234-445c-96ed-c10112c81806', 'Which actor played Bhallaladeva in ''Baahubali''?', 'Rana Daggubati', 'Bollywood', CURRENT_TIMESTAMP),
+('edb41432-c302-41b0-a64c-5d1f7884e33c', 'Which actor starred in the Hollywood film ''Life of Pi''?', 'Irrfan Khan', 'Celebrities', CURRENT_TIMESTAMP),
+('f8d05ab1-b6e9-4ccb-b780-30a888f7e912', 'Which superhero show aired in the 90s and starred Mukesh Khanna?', 'Shaktimaan', 'TV', CURRENT_TIMESTAMP),
+('b5cb02c1-44b4-462f-9501-5ebca6c6dce4', 'Which actor is nicknamed ''Greek God of Bollywood''?', 'Hrithik Roshan', 'Celebrities', CURRENT_TIMESTAMP),
+('8686a12f-7617-4999-9450-4461e7c53ec3', 'From which show is the viral line ''Rasode mein kaun tha''?', 'Saath Nibhaana Saathiya', 'Memes', CURRENT_TIMESTAMP),
+('2e6a79d9-e115-47d9-b7ed-dc73459fc656', 'Who composed ''Why This Kolaveri Di''?', 'Anirudh Ravichander', 'Music', CURRENT_TIMESTAMP),
+('9681c7f5-8476-4c42-b9cb-a5a6149d00b0', 'Which cricketer is nicknamed ''The Hitman''?', 'Rohit Sharma', 'Sports', CURRENT_TIMESTAMP),
+('7ae7e395-172d-446f-b90f-69d71350078d', 'Which footballer is captain of the Indian national team?', 'Sunil Chhetri', 'Sports', CURRENT_TIMESTAMP),
+('a1403fef-573d-4a60-b5f9-4d4777c1b74f', 'Who is the daughter of Sridevi and Boney Kapoor?', 'Janhvi Kapoor', 'Celebrities', CURRENT_TIMESTAMP),
+('68cee1b5-52cd-48f0-84dd-991471f1bb8a', 'Which TV anchor is known for saying ''The nation wants to know''?', 'Arnab Goswami', 'TV', CURRENT_TIMESTAMP),
+('0b628703-fa13-4824-8aa0-072ee03e0041', 'Which movie features the iconic line ''Mogambo khush hua''?', 'Mr. India', 'Bollywood', CURRENT_TIMESTAMP),
+('f590bddf-748a-403b-b077-9052aa497ff2', 'Which Indian tennis player partnered with Leander Paes in doubles?', 'Mahesh Bhupathi', 'Sports', CURRENT_TIMESTAMP),
+('273731d9-1a8f-4c82-86b9-9a15af4c1786', 'What is the name of India''s national song?', 'Vande Mataram', 'Culture', CURRENT_TIMESTAMP),
+('ff6f549e-9744-4101-b34d-d4d046e96b52', 'Which Indian actress is married to Virat Kohli?', 'Anushka Sharma', 'Celebrities', CURRENT_TIMESTAMP),
+('c1e15751-61d4-45b9-abe7-17978e81151a', 'Which film won Best Picture at the Oscars and featured Indian actor Dev Patel?', 'Slumdog Millionaire', 'Bollywood', CURRENT_TIMESTAMP),
+('14b45219-1f17-4f7f-87b3-d736236ad756', '''Kya karun main itni sundar hoon toh'' is associated with which influencer?', 'TikTok Girl - Garima Chaurasia', 'Memes', CURRENT_TIMESTAMP),
+('55d35fa5-23fa-4237-945a-353d2694304e', 'Who played ''Piku'' in the movie of the same name?', 'Deepika Padukone', 'Celebrities', CURRENT_TIMESTAMP),
+('c81c5b10-4fb6-4404-a58c-5cc8db1cde86', 'Who won the first season of Indian Idol?', 'Abhijeet Sawant', 'Music', CURRENT_TIMESTAMP),
+('9040cb10-77a2-4e4f-ae4d-13521d063811', 'What viral phrase did Vipin Sahu shout while paragliding?', 'Land kara de', 'Memes', CURRENT_TIMESTAMP),
+('10d9512c-ba4b-440b-a11a-9247a039c06a', 'Who was India''s first female Olympic medalist?', 'Karnam Malleswari', 'Sports', CURRENT_TIMESTAMP),
+('d9cce346-6d54-473e-94db-60d863db974b', '''Thala for a reason'' refers to which cricketer?', 'MS Dhoni', 'Memes', CURRENT_TIMESTAMP),
+('f1cb379c-e93f-4eb7-8277-03dcdbf3be51', 'Which app did CarryMinati roast in a viral video?', 'TikTok', 'Internet', CURRENT_TIMESTAMP),
+('5d7dfa1c-a4bc-4754-9782-81bcec1a11e8', 'Who played the role of Alauddin Khilji in ''Padmaavat''?', 'Ranveer Singh', 'Bollywood', CURRENT_TIMESTAMP),
+('3c52308f-0e1f-445c-86e5-fd893377e25f', 'Which is India"s harvest festival in Punjab?', 'Lohri', 'Culture', CURRENT_TIMESTAMP),
+('2ad0c660-047b-4c11-bad0-d83324935bcb', 'In ''Kabir Singh'', what is the profession of the main character?', 'Doctor', 'Bollywood', CURRENT_TIMESTAMP),
+('d2f6c17a-d495-4b0e-b09e-724072da64ce', 'Who is the founder of ''The Viral Fever'' (TVF)?', 'Arunabh Kumar', 'Internet', CURRENT_TIMESTAMP),
+('5b9d250f-e20e-4f74-b6ed-7579a23033ac', 'Which Hindi serial had a character called ''Komolika''?', 'Kasautii Zindagii Kay', 'TV', CURRENT_TIMESTAMP),
+('5836df9e-7bb7-4a2c-bddf-2dd9abcd9f58', '''Hello friends, chai pi lo'' became a viral catchphrase from which platform?', 'WhatsApp', 'Memes', CURRENT_TIMESTAMP),
+('8a4a06e2-581f-4acb-bb33-4d053fff0439', 'Which Indian actor voiced Mufasa in the Hindi version of ''The Lion King''?', 'Shah Rukh Khan', 'Celebrities', CURRENT_TIMESTAMP),
+('c4d6e957-365f-4fdf-a2d3-ec8820b58fb5', 'Which quiz show aired on Doordarshan and was popular in the 90s?', 'Bournvita Quiz Contest', 'TV', CURRENT_TIMESTAMP),
+('1b3cb101-b83b-469c-9034-22a0a0487ec4', 'Who captained India to win the 2011 Cricket World Cup?', 'MS Dhoni', 'Sports', CURRENT_TIMESTAMP),
+('ee02ce91-ae9c-41a0-bb80-f2163124efe5', 'Which Indian won a gold medal at the 2020 Tokyo Olympics in javelin throw?', 'Neeraj Chopra', 'Sports', CURRENT_TIMESTAMP),
+('4506970f-dd91-4fc3-a649-7ff17bc96b7f', 'Who played the role of Sardar Udham in the 2021 film?', 'Vicky Kaushal', 'Celebrities', CURRENT_TIMESTAMP),
+('a9cdf9ad-7592-4849-9102-edd90fe5882b', 'Which platform is known for ''ScoopWhoop'' and ''FilterCopy'' content?', 'YouTube', 'Internet', CURRENT_TIMESTAMP),
+('710326ea-a144-4d5c-9197-2d716e2617f9', 'Which Indian classical dance originates from Tamil Nadu?', 'Bharatanatyam', 'Culture', CURRENT_TIMESTAMP),
+('93299493-15ac-4ed3-a121-e353a50be4d8', 'Which TV show features the character Jethalal?', 'Taarak Mehta Ka Ooltah Chashmah', 'TV', CURRENT_TIMESTAMP),
+('438f45ad-8c1c-4b92-971b-4f5acefa3b3e', 'What is the primary language spoken in West Bengal?', 'Bengali', 'Culture', CURRENT_TIMESTAMP),
+('c381454e-9d30-41e6-aa5c-1df2914c4ddf', 'Which educational YouTuber runs ''Physics Wallah''?', 'Alakh Pandey', 'Internet', CURRENT_TIMESTAMP),
+('2153ec1b-5da5-43c2-9550-a56acb9c6b4b', 'Which 2018 movie popularized the character ''Murad'', a street rapper?', 'Gully Boy', 'Bollywood', CURRENT_TIMESTAMP),
+('59efe1fb-af75-43f8-8e45-f9f51e681fc6', 'In which festival do people smear colors on each other?', 'Holi', 'Culture', CURRENT_TIMESTAMP),
+('58331b8d-88ba-47c9-b95e-38456978645c', 'Which Bollywood scene turned meme involves ''Maa kasam, main dhoondh ke launga''?', 'Phir Hera Pheri', 'Memes', CURRENT_TIMESTAMP),
+('1d2a050d-0d39-4d82-ac1c-b99c9f9251d0', 'Which rapper is associated with the track ''Machayenge''?', 'Emiway Bantai', 'Music', CURRENT_TIMESTAMP),
+('5f34b5de-0284-402b-abd9-e4c00eb6670a', 'Who hosted ''Kaun Banega Crorepati''?', 'Amitabh Bachchan', 'TV', CURRENT_TIMESTAMP),
+('f802cbc7-9650-45c7-9a7e-be99849d7551', 'Which Indian stand-up comedian''s joke ''Sakht Launda'' went viral?', 'Zakir Khan', 'Memes', CURRENT_TIMESTAMP),
+('cc7ef2d0-3303-4c25-9c54-2b67e5e78629', 'Which city is known as the Pink City?', 'Jaipur', 'Culture', CURRENT_TIMESTAMP),
+('6c43c718-1dc9-4bb9-8739-322864bb1056', 'Which Indian streamer runs the channel ''Total Gaming''?', 'Ajjubhai', 'Internet', CURRENT_TIMESTAMP),
+('315c0fe1-f9d1-44e8-a89a-b4af4745e547', 'What is the name of the alien in the movie ''PK''?', 'PK', 'Bollywood', CURRENT_TIMESTAMP),
+('f806de0d-4d59-44d6-9ff8-15e7ee018ff6', 'Who is known as King Khan in Bollywood?', 'Shah Rukh Khan', 'Celebrities', CURRENT_TIMESTAMP),
+('7a7ae88b-6e85-44e7-9432-c2ee3785c70a', 'Which Indian bowler is known for his ''Boom Boom'' yorkers?', 'Jasprit Bumrah', 'Sports', CURRENT_TIMESTAMP),
+('8f05c004-77f0-4e99-b1fa-940e275914c4', 'Which comedy group created ''A Day with…'' sketches?', 'All India Bakchod', 'Internet', CURRENT_TIMESTAMP),
+('fd456158-be19-4c0f-ac22-6ba808e41835', 'Who is known as the Nightingale of India?', 'Lata Mangeshkar', 'Music', CURRENT_TIMESTAMP),
+('f400c534-123f-4f09-b062-61a1f8f6df32', 'What is the title of Badshah''s viral track with Jacqueline Fernandez?', 'Paani Paani', 'Music', CURRENT_TIMESTAMP),
+('604e34c3-a617-4e1e-bcfc-5602136d4d8b', 'Which movie launched Deepika Padukone''s Bollywood career?', 'Om Shanti Om', 'Bollywood', CURRENT_TIMESTAMP),
+('86bb6ca9-822f-4a46-beaf-b38ea3738624', 'Which artist is known for the song ''Mere Gully Mein''?', 'Divine', 'Music', CURRENT_TIMESTAMP),
+('ab04a7e6-069a-4b6f-ae77-2eb5f92a81f3', 'Which Indian platform became famous for meme sharing during 2020 lockdown?', 'Instagram', 'Internet', CURRENT_TIMESTAMP),
+('d3170857-c695-41ef-a018-d880d74c1064', 'Which Indian boxer has a biopic titled after her?', 'Mary Kom', 'Sports', CURRENT_TIMESTAMP),
+('2fe6963e-096c-466a-87c0-a598c8db8574', 'Which meme features a remix of a saree shop salesperson saying ''Just looking like a wow''?', 'Instagram Reel', 'Memes', CURRENT_TIMESTAMP),
+('90a1137a-cf04-48a3-8505-380a32f4a66c', 'What is the name of the ''screaming guy'' meme from ''Bigg Boss''?', 'Deepak Kalal', 'Memes', CURRENT_TIMESTAMP),
+('7e92e08c-6a1a-466e-a90c-fd6f6ee90641', 'In which show is the line ''Daya, darwaza tod do'' used?', 'CID', 'TV', CURRENT_TIMESTAMP),
+('a3a888d6-d51e-4659-be92-2069d706e1bc', 'Which song by Dhanush became India"s biggest viral song of 2011?', 'Why This Kolaveri Di', 'Music', CURRENT_TIMESTAMP),
+('782aa4c5-9115-4b0d-b6be-410872152cac', 'Who created the viral ''Pawri Ho Rahi Hai'' remix?', 'Yashraj Mukhate', 'Internet', CURRENT_TIMESTAMP),
+('9dec6053-1ef3-45fe-9ac0-bf1df8d53f62', 'In ''Dangal'', which sport is central to the story?', 'Wrestling', 'Bollywood', CURRENT_TIMESTAMP),
+('a90ddfc9-55de-4c27-ac4a-5a701c7f775b', 'Which Indian badminton player has won a silver Olympic medal?', 'PV Sindhu', 'Sports', CURRENT_TIMESTAMP),
+('860b4d80-648c-47e1-829a-36132fafed7f', 'What"s the name of the talent hunt show judged by Karan Johar and Malaika Arora?', 'India''s Got Talent', 'TV', CURRENT_TIMESTAMP),
+('18dfd5aa-a64c-4a96-9b87-d3396ce03bf9', 'Which traditional dish is made during Eid in India?', 'Biryani', 'Culture', CURRENT_TIMESTAMP),
+('02a649f3-a129-4ff9-ab32-111602a1812d', 'In ''3 Idiots'', what is Rancho''s real name?', 'Phunsukh Wangdu', 'Bollywood', CURRENT_TIMESTAMP),
+('a8a15d9b-8d29-4bfd-8a7b-d57e9b76d9d2', 'Which Indian reality show was adapted from ''Big Brother''?', 'Bigg Boss', 'TV', CURRENT_TIMESTAMP),
+('bf0f99e1-c5e7-48dd-bf5b-9e7add0c930d', 'Which state is famous for Bihu dance?', 'Assam', 'Culture', CURRENT_TIMESTAMP);
\ No newline at end of file
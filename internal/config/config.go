@@ -1,34 +1,459 @@
+// internal/config/config.go
+
 package config
 
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rohan03122001/quizzing/internal/logging"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+	"github.com/rohan03122001/quizzing/internal/tracing"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is read when no config file is named and it exists
+const DefaultFile = "config.yaml"
+
+// Config is every setting of the server. Load fills it from the defaults,
+// then a YAML file, then environment variables, then flags.
 type Config struct {
-	Server   *Server
-	Database *Database
+	Server    Server    `yaml:"server"`
+	Database  Database  `yaml:"database"`
+	Hub       Hub       `yaml:"hub"`
+	Cleanup   Cleanup   `yaml:"cleanup"`
+	Game      Game      `yaml:"game"`
+	CORS      CORS      `yaml:"cors"`
+	Log       Log       `yaml:"log"`
+	Tracing   Tracing   `yaml:"tracing"`
+	RateLimit RateLimit `yaml:"rate_limit"`
+	Auth      Auth      `yaml:"auth"`
 }
 
 type Server struct {
-	Address string
+	Port            int           `yaml:"port"`
+	Mode            string        `yaml:"mode"`             // Gin mode: debug, release or test
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"` // Time given to open requests on shutdown
+	DrainTimeout    time.Duration `yaml:"drain_timeout"`    // Time given to running games on shutdown before they're checkpointed
+	AdminUsernames  []string      `yaml:"admin_usernames"`  // Accounts that can manage the question bank
+	TrustedProxies  []string      `yaml:"trusted_proxies"`  // Proxy IPs or CIDRs whose X-Forwarded-For is believed; none by default
 }
 
 type Database struct {
-	Host     string
-	Port     int
-	User     string
-	Password string
-	DBName   string
+	Driver   string `yaml:"driver"` // "postgres" or "sqlite"
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	DBName   string `yaml:"dbname"`
+	SSLMode  string `yaml:"sslmode"`
+	Path     string `yaml:"path"` // SQLite database file
+}
+
+type Hub struct {
+	ReconnectWindow time.Duration `yaml:"reconnect_window"` // How long a disconnected player can come back
+}
+
+type Cleanup struct {
+	Interval        time.Duration `yaml:"interval"`         // How often to look for inactive rooms
+	InactiveTimeout time.Duration `yaml:"inactive_timeout"` // Idle time before a room is cleaned up
+}
+
+// Game holds the settings of new rooms when the host doesn't pick them
+type Game struct {
+	MaxPlayers       int `yaml:"max_players"`
+	RoundTime        int `yaml:"round_time"`        // Seconds
+	MaxRounds        int `yaml:"max_rounds"`
+	IntermissionTime int `yaml:"intermission_time"` // Seconds
+}
+
+type CORS struct {
+	AllowedOrigins []string `yaml:"allowed_origins"` // "*" allows any
+}
+
+type Log struct {
+	Level  string `yaml:"level"`  // debug, info, warn or error; warn in release mode and info otherwise when unset
+	Format string `yaml:"format"` // text or json
+}
+
+type Tracing struct {
+	Exporter    string  `yaml:"exporter"`     // none, stdout or otlp
+	Endpoint    string  `yaml:"endpoint"`     // OTLP/HTTP collector URL; empty uses OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318
+	SampleRatio float64 `yaml:"sample_ratio"` // Share of traces kept, 0 to 1
+}
+
+type Auth struct {
+	LogMagicLinks bool `yaml:"log_magic_links"` // Log magic-link tokens, as emails aren't sent yet; development only
+}
+
+// RateLimit holds the token bucket limits that keep clients from flooding
+// the server
+type RateLimit struct {
+	Messages    Rate            `yaml:"messages"`    // WebSocket and SSE messages per connection
+	Events      map[string]Rate `yaml:"events"`      // Messages per connection of one event type, on top of messages
+	MaxStrikes  int             `yaml:"max_strikes"` // Limited messages a minute before the client is dropped, 0 never drops
+	Connections Rate            `yaml:"connections"` // New WebSocket and SSE connections per IP
+	Requests    Rate            `yaml:"requests"`    // HTTP requests per IP
+	CreateRoom  Rate            `yaml:"create_room"` // Rooms created per IP
+}
+
+// Rate is a token bucket: requests take a token and tokens come back at
+// rate a second, up to burst
+type Rate struct {
+	Rate  float64 `yaml:"rate"` // 0 for no limit
+	Burst int     `yaml:"burst"`
 }
 
-func Load() (*Config, error) {
-	cfg := &Config{
-		Server:   &Server{},
-		Database: &Database{},
+// Default returns the settings used when nothing overrides them
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			Port:            8080,
+			Mode:            "debug",
+			ShutdownTimeout: 5 * time.Second,
+			DrainTimeout:    2 * time.Minute,
+		},
+		Database: Database{
+			Driver:   "postgres",
+			Host:     "localhost",
+			Port:     5434,
+			User:     "postgres",
+			Password: "postgres",
+			DBName:   "quiz_app",
+			SSLMode:  "disable",
+			Path:     "quiz.db",
+		},
+		Hub: Hub{
+			ReconnectWindow: 10 * time.Minute,
+		},
+		Cleanup: Cleanup{
+			Interval:        time.Minute,
+			InactiveTimeout: 10 * time.Minute,
+		},
+		Game: Game{
+			MaxPlayers:       10,
+			RoundTime:        30,
+			MaxRounds:        5,
+			IntermissionTime: 5,
+		},
+		CORS: CORS{
+			AllowedOrigins: []string{"*"},
+		},
+		Log: Log{
+			Format: logging.FormatText,
+		},
+		Tracing: Tracing{
+			Exporter:    tracing.ExporterNone,
+			SampleRatio: 1,
+		},
+		RateLimit: RateLimit{
+			Messages: Rate{Rate: 10, Burst: 20},
+			Events: map[string]Rate{
+				protocol.EventSubmitAnswer: {Rate: 2, Burst: 5},
+				protocol.EventJoinRoom:     {Rate: 1, Burst: 5},
+				protocol.EventReconnect:    {Rate: 1, Burst: 5},
+			},
+			MaxStrikes:  30,
+			Connections: Rate{Rate: 2, Burst: 30},
+			Requests:    Rate{Rate: 20, Burst: 50},
+			CreateRoom:  Rate{Rate: 0.1, Burst: 5},
+		},
 	}
+}
+
+// binding ties a setting to its environment variable and flag
+type binding struct {
+	env   string
+	flag  string
+	usage string
+	value interface{} // *string, *int, *float64, *bool, *time.Duration or *[]string
+}
+
+func (c *Config) bindings() []binding {
+	return []binding{
+		{"PORT", "port", "HTTP port", &c.Server.Port},
+		{"GIN_MODE", "mode", "debug, release or test", &c.Server.Mode},
+		{"SHUTDOWN_TIMEOUT", "shutdown-timeout", "time given to open requests on shutdown", &c.Server.ShutdownTimeout},
+		{"DRAIN_TIMEOUT", "drain-timeout", "time given to running games on shutdown, 0 to checkpoint them right away", &c.Server.DrainTimeout},
+		{"ADMIN_USERNAMES", "admin-usernames", "comma-separated accounts that can manage the question bank", &c.Server.AdminUsernames},
+		{"TRUSTED_PROXIES", "trusted-proxies", "comma-separated proxy IPs or CIDRs whose X-Forwarded-For is believed", &c.Server.TrustedProxies},
+
+		{"DB_DRIVER", "db-driver", "postgres or sqlite", &c.Database.Driver},
+		{"DB_HOST", "db-host", "Postgres host", &c.Database.Host},
+		{"DB_PORT", "db-port", "Postgres port", &c.Database.Port},
+		{"DB_USER", "db-user", "Postgres user", &c.Database.User},
+		{"DB_PASSWORD", "db-password", "Postgres password", &c.Database.Password},
+		{"DB_NAME", "db-name", "Postgres database", &c.Database.DBName},
+		{"DB_SSLMODE", "db-sslmode", "Postgres sslmode", &c.Database.SSLMode},
+		{"DB_PATH", "db-path", "SQLite database file", &c.Database.Path},
+
+		{"HUB_RECONNECT_WINDOW", "reconnect-window", "how long a disconnected player can come back", &c.Hub.ReconnectWindow},
+
+		{"CLEANUP_INTERVAL", "cleanup-interval", "how often to look for inactive rooms", &c.Cleanup.Interval},
+		{"CLEANUP_INACTIVE_TIMEOUT", "cleanup-inactive-timeout", "idle time before a room is cleaned up", &c.Cleanup.InactiveTimeout},
+
+		{"GAME_MAX_PLAYERS", "game-max-players", "default players per room", &c.Game.MaxPlayers},
+		{"GAME_ROUND_TIME", "game-round-time", "default seconds per round", &c.Game.RoundTime},
+		{"GAME_MAX_ROUNDS", "game-max-rounds", "default rounds per game", &c.Game.MaxRounds},
+		{"GAME_INTERMISSION_TIME", "game-intermission-time", "default seconds between rounds", &c.Game.IntermissionTime},
+
+		{"ALLOWED_ORIGINS", "allowed-origins", "comma-separated origins allowed by CORS and WebSockets, or *", &c.CORS.AllowedOrigins},
+
+		{"LOG_LEVEL", "log-level", "debug, info, warn or error", &c.Log.Level},
+		{"LOG_FORMAT", "log-format", "text or json", &c.Log.Format},
 
-	cfg.Server.Address = ":8080"
-	cfg.Database.Host = "localhost"
-	cfg.Database.Port = 5434
-	cfg.Database.User = "postgres"
-	cfg.Database.Password = "postgres"
-	cfg.Database.DBName = "quiz_app"
+		{"TRACING_EXPORTER", "tracing-exporter", "none, stdout or otlp", &c.Tracing.Exporter},
+		{"TRACING_ENDPOINT", "tracing-endpoint", "OTLP/HTTP collector URL", &c.Tracing.Endpoint},
+		{"TRACING_SAMPLE_RATIO", "tracing-sample-ratio", "share of traces kept, 0 to 1", &c.Tracing.SampleRatio},
+
+		{"RATE_LIMIT_MESSAGES_RATE", "rate-limit-messages-rate", "messages a second per connection, 0 for no limit", &c.RateLimit.Messages.Rate},
+		{"RATE_LIMIT_MESSAGES_BURST", "rate-limit-messages-burst", "messages per connection sent at once", &c.RateLimit.Messages.Burst},
+		{"RATE_LIMIT_MAX_STRIKES", "rate-limit-max-strikes", "limited messages a minute before a client is dropped, 0 never drops", &c.RateLimit.MaxStrikes},
+		{"RATE_LIMIT_CONNECTIONS_RATE", "rate-limit-connections-rate", "new connections a second per IP, 0 for no limit", &c.RateLimit.Connections.Rate},
+		{"RATE_LIMIT_CONNECTIONS_BURST", "rate-limit-connections-burst", "new connections per IP opened at once", &c.RateLimit.Connections.Burst},
+		{"RATE_LIMIT_REQUESTS_RATE", "rate-limit-requests-rate", "HTTP requests a second per IP, 0 for no limit", &c.RateLimit.Requests.Rate},
+		{"RATE_LIMIT_REQUESTS_BURST", "rate-limit-requests-burst", "HTTP requests per IP sent at once", &c.RateLimit.Requests.Burst},
+		{"RATE_LIMIT_CREATE_ROOM_RATE", "rate-limit-create-room-rate", "rooms created a second per IP, 0 for no limit", &c.RateLimit.CreateRoom.Rate},
+		{"RATE_LIMIT_CREATE_ROOM_BURST", "rate-limit-create-room-burst", "rooms per IP created at once", &c.RateLimit.CreateRoom.Burst},
+
+		{"AUTH_LOG_MAGIC_LINKS", "log-magic-links", "log magic-link tokens, for development only", &c.Auth.LogMagicLinks},
+	}
+}
+
+// Load reads the configuration for a command line. The file is the one
+// named by -config or CONFIG_FILE, or config.yaml if there is one. It
+// returns the arguments left after the flags, such as a subcommand.
+func Load(args []string) (*Config, []string, error) {
+	cfg := Default()
+	bindings := cfg.bindings()
+
+	flags := flag.NewFlagSet("api", flag.ContinueOnError)
+	file := flags.String("config", os.Getenv("CONFIG_FILE"), "YAML configuration file")
+	byFlag := make(map[string]binding, len(bindings))
+	for _, b := range bindings {
+		flags.String(b.flag, "", fmt.Sprintf("%s (%s)", b.usage, b.env))
+		byFlag[b.flag] = b
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	path := *file
+	if path == "" {
+		if _, err := os.Stat(DefaultFile); err == nil {
+			path = DefaultFile
+		}
+	}
+	if path != "" {
+		if err := cfg.readFile(path); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, b := range bindings {
+		if value, ok := os.LookupEnv(b.env); ok {
+			if err := set(b.value, value); err != nil {
+				return nil, nil, fmt.Errorf("invalid %s: %w", b.env, err)
+			}
+		}
+	}
+
+	var flagErr error
+	flags.Visit(func(f *flag.Flag) {
+		b, ok := byFlag[f.Name]
+		if !ok || flagErr != nil {
+			return
+		}
+		if err := set(b.value, f.Value.String()); err != nil {
+			flagErr = fmt.Errorf("invalid -%s: %w", f.Name, err)
+		}
+	})
+	if flagErr != nil {
+		return nil, nil, flagErr
+	}
+
+	// Production is quiet unless asked otherwise
+	if cfg.Log.Level == "" {
+		cfg.Log.Level = "info"
+		if cfg.Server.Mode == "release" {
+			cfg.Log.Level = "warn"
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return cfg, flags.Args(), nil
+}
+
+// readFile overlays a YAML file on the configuration. Unknown keys are
+// errors, so typos don't go unnoticed.
+func (c *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("reading config %s: %w", path, err)
+	}
+	return nil
+}
+
+// set parses a string into a setting
+func set(value interface{}, s string) error {
+	s = strings.TrimSpace(s)
+	switch v := value.(type) {
+	case *string:
+		*v = s
+	case *int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("%q isn't a number", s)
+		}
+		*v = n
+	case *float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("%q isn't a number", s)
+		}
+		*v = f
+	case *bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("%q isn't true or false", s)
+		}
+		*v = b
+	case *time.Duration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("%q isn't a duration like 30s or 10m", s)
+		}
+		*v = d
+	case *[]string:
+		var list []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		*v = list
+	default:
+		return fmt.Errorf("unsupported setting type %T", value)
+	}
+	return nil
+}
+
+// Validate reports every invalid setting at once
+func (c *Config) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port < 65536, "server.port must be 1-65535, got %d", c.Server.Port)
+	check(c.Server.Mode == "debug" || c.Server.Mode == "release" || c.Server.Mode == "test",
+		"server.mode must be debug, release or test, got %q", c.Server.Mode)
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+	check(c.Server.DrainTimeout >= 0, "server.drain_timeout can't be negative")
+	for _, proxy := range c.Server.TrustedProxies {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		check(net.ParseIP(proxy) != nil || cidrErr == nil,
+			"server.trusted_proxies: %q isn't an IP or CIDR like 10.0.0.0/8", proxy)
+	}
+
+	switch c.Database.Driver {
+	case "postgres":
+		check(c.Database.Host != "", "database.host is required for postgres")
+		check(c.Database.Port > 0 && c.Database.Port < 65536, "database.port must be 1-65535, got %d", c.Database.Port)
+		check(c.Database.User != "", "database.user is required for postgres")
+		check(c.Database.DBName != "", "database.dbname is required for postgres")
+		check(sslModes[c.Database.SSLMode], "database.sslmode must be one of disable, allow, prefer, require, verify-ca or verify-full, got %q", c.Database.SSLMode)
+	case "sqlite":
+		check(c.Database.Path != "", "database.path is required for sqlite")
+	default:
+		check(false, "database.driver must be postgres or sqlite, got %q", c.Database.Driver)
+	}
+
+	check(c.Hub.ReconnectWindow > 0, "hub.reconnect_window must be positive")
+	check(c.Cleanup.Interval > 0, "cleanup.interval must be positive")
+	check(c.Cleanup.InactiveTimeout > 0, "cleanup.inactive_timeout must be positive")
+
+	check(c.Game.MaxPlayers >= 2, "game.max_players must be at least 2, got %d", c.Game.MaxPlayers)
+	check(c.Game.RoundTime > 0, "game.round_time must be positive, got %d", c.Game.RoundTime)
+	check(c.Game.MaxRounds > 0, "game.max_rounds must be positive, got %d", c.Game.MaxRounds)
+	check(c.Game.IntermissionTime > 0, "game.intermission_time must be positive, got %d", c.Game.IntermissionTime)
+
+	check(len(c.CORS.AllowedOrigins) > 0, "cors.allowed_origins needs at least one origin, or *")
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		parsed, err := url.Parse(origin)
+		check(err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != "" && parsed.Path == "",
+			"cors.allowed_origins: %q isn't an origin like https://quiz.example.com", origin)
+	}
+
+	if _, err := logging.ParseLevel(c.Log.Level); c.Log.Level != "" && err != nil {
+		check(false, "log.level must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	check(c.Log.Format == logging.FormatText || c.Log.Format == logging.FormatJSON,
+		"log.format must be text or json, got %q", c.Log.Format)
+
+	switch c.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP:
+	default:
+		check(false, "tracing.exporter must be none, stdout or otlp, got %q", c.Tracing.Exporter)
+	}
+	if c.Tracing.Endpoint != "" {
+		parsed, err := url.Parse(c.Tracing.Endpoint)
+		check(err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != "",
+			"tracing.endpoint: %q isn't a URL like http://collector:4318", c.Tracing.Endpoint)
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1,
+		"tracing.sample_ratio must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+
+	checkRate := func(name string, r Rate) {
+		check(r.Rate >= 0, "rate_limit.%s.rate can't be negative", name)
+		check(r.Rate == 0 || r.Burst >= 1, "rate_limit.%s.burst must be at least 1, got %d", name, r.Burst)
+	}
+	checkRate("messages", c.RateLimit.Messages)
+	checkRate("connections", c.RateLimit.Connections)
+	checkRate("requests", c.RateLimit.Requests)
+	checkRate("create_room", c.RateLimit.CreateRoom)
+	for event, r := range c.RateLimit.Events {
+		check(clientEvents[event], "rate_limit.events: %q isn't a client event", event)
+		checkRate("events."+event, r)
+	}
+	check(c.RateLimit.MaxStrikes >= 0, "rate_limit.max_strikes can't be negative")
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+var clientEvents = func() map[string]bool {
+	events := make(map[string]bool, len(protocol.ClientEvents))
+	for _, spec := range protocol.ClientEvents {
+		events[spec.Type] = true
+	}
+	return events
+}()
 
-	return cfg, nil
+var sslModes = map[string]bool{
+	"disable": true, "allow": true, "prefer": true, "require": true, "verify-ca": true, "verify-full": true,
 }
@@ -0,0 +1,152 @@
+// internal/config/config_test.go
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+    t.Helper()
+    path := filepath.Join(t.TempDir(), "config.yaml")
+    if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+        t.Fatal(err)
+    }
+    return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+    path := writeConfig(t, `
+server:
+  port: 9000
+  mode: release
+database:
+  host: db.internal
+  sslmode: require
+cleanup:
+  interval: 30s
+cors:
+  allowed_origins: ["https://quiz.example.com"]
+`)
+    t.Setenv("PORT", "9100")
+    t.Setenv("DB_HOST", "db.env")
+    t.Setenv("ALLOWED_ORIGINS", "https://a.example.com, https://b.example.com")
+
+    cfg, args, err := Load([]string{"-config", path, "-db-host", "db.flag", "migrate", "up"})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if cfg.Server.Mode != "release" || cfg.Database.SSLMode != "require" || cfg.Cleanup.Interval != 30*time.Second {
+        t.Errorf("file settings weren't applied: %+v", cfg)
+    }
+    if cfg.Server.Port != 9100 {
+        t.Errorf("got port %d, want the environment's 9100", cfg.Server.Port)
+    }
+    if cfg.Database.Host != "db.flag" {
+        t.Errorf("got host %q, want the flag's db.flag", cfg.Database.Host)
+    }
+    if want := []string{"https://a.example.com", "https://b.example.com"}; !reflect.DeepEqual(cfg.CORS.AllowedOrigins, want) {
+        t.Errorf("got origins %v, want %v", cfg.CORS.AllowedOrigins, want)
+    }
+    if cfg.Game != Default().Game {
+        t.Errorf("got game settings %+v, want the defaults", cfg.Game)
+    }
+    if !reflect.DeepEqual(args, []string{"migrate", "up"}) {
+        t.Errorf("got args %v, want the subcommand", args)
+    }
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+    path := writeConfig(t, `
+server:
+  port: 70000
+database:
+  driver: mysql
+game:
+  max_players: 1
+cors:
+  allowed_origins: ["quiz.example.com"]
+`)
+
+    _, _, err := Load([]string{"-config", path})
+    if err == nil {
+        t.Fatal("expected an error")
+    }
+    for _, want := range []string{"server.port", "database.driver", "game.max_players", "cors.allowed_origins"} {
+        if !strings.Contains(err.Error(), want) {
+            t.Errorf("error doesn't mention %s: %v", want, err)
+        }
+    }
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+    path := writeConfig(t, "server:\n  prot: 9000\n")
+    if _, _, err := Load([]string{"-config", path}); err == nil || !strings.Contains(err.Error(), "prot") {
+        t.Errorf("got %v, want an error about the unknown key", err)
+    }
+}
+
+func TestLoadRejectsBadEnvironment(t *testing.T) {
+    t.Setenv("CLEANUP_INTERVAL", "often")
+    if _, _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "CLEANUP_INTERVAL") {
+        t.Errorf("got %v, want an error naming CLEANUP_INTERVAL", err)
+    }
+}
+
+func TestLogLevelFollowsMode(t *testing.T) {
+    for mode, want := range map[string]string{"debug": "info", "release": "warn"} {
+        cfg, _, err := Load([]string{"-mode", mode})
+        if err != nil {
+            t.Fatal(err)
+        }
+        if cfg.Log.Level != want {
+            t.Errorf("got log level %q in %s mode, want %q", cfg.Log.Level, mode, want)
+        }
+    }
+
+    cfg, _, err := Load([]string{"-mode", "release", "-log-level", "debug"})
+    if err != nil || cfg.Log.Level != "debug" {
+        t.Errorf("got %v, %v, want the flag's level", cfg, err)
+    }
+    if _, _, err := Load([]string{"-log-level", "loud"}); err == nil || !strings.Contains(err.Error(), "log.level") {
+        t.Errorf("got %v, want an error about log.level", err)
+    }
+}
+
+func TestRateLimitEvents(t *testing.T) {
+    path := writeConfig(t, `
+rate_limit:
+  events:
+    submit_answer: {rate: 5, burst: 10}
+`)
+    cfg, _, err := Load([]string{"-config", path})
+    if err != nil {
+        t.Fatal(err)
+    }
+    // Events not in the file keep their defaults
+    if got := cfg.RateLimit.Events["submit_answer"]; got != (Rate{Rate: 5, Burst: 10}) {
+        t.Errorf("got submit_answer limit %+v, want the file's", got)
+    }
+    if got := cfg.RateLimit.Events["join_room"]; got != Default().RateLimit.Events["join_room"] {
+        t.Errorf("got join_room limit %+v, want the default", got)
+    }
+
+    path = writeConfig(t, `
+rate_limit:
+  messages: {rate: 1, burst: 0}
+  events:
+    submit_anwser: {rate: 1, burst: 1}
+`)
+    _, _, err = Load([]string{"-config", path})
+    for _, want := range []string{"rate_limit.messages.burst", "submit_anwser"} {
+        if err == nil || !strings.Contains(err.Error(), want) {
+            t.Errorf("got %v, want an error about %s", err, want)
+        }
+    }
+}
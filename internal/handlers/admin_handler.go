@@ -0,0 +1,281 @@
+// internal/handlers/admin_handler.go
+
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rohan03122001/quizzing/internal/repository"
+	"github.com/rohan03122001/quizzing/internal/service"
+)
+
+// Largest import file accepted over HTTP
+const maxImportSize = 10 << 20
+
+// AdminHandler serves the question bank API. Every route needs an admin
+// account's session token.
+type AdminHandler struct {
+    questionService *service.QuestionService
+    accountService  *service.AccountService
+}
+
+func NewAdminHandler(questionService *service.QuestionService, accountService *service.AccountService) *AdminHandler {
+    return &AdminHandler{
+        questionService: questionService,
+        accountService:  accountService,
+    }
+}
+
+// RequireAdmin rejects requests without an admin session
+func (h *AdminHandler) RequireAdmin(c *gin.Context) {
+    account, err := h.accountService.RequireAdmin(accessToken(c))
+    if err != nil {
+        respondError(c, err)
+        c.Abort()
+        return
+    }
+    c.Set("account", account)
+    c.Next()
+}
+
+// ListQuestions searches the question bank
+func (h *AdminHandler) ListQuestions(c *gin.Context) {
+    page, pageSize := pagination(c)
+
+    filter := repository.QuestionFilter{
+        Query:    c.Query("q"),
+        Category: c.Query("category"),
+    }
+    if difficulty := c.Query("difficulty"); difficulty != "" {
+        level, err := strconv.Atoi(difficulty)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "difficulty must be a number"})
+            return
+        }
+        filter.Difficulty = level
+    }
+
+    questions, total, err := h.questionService.Search(filter, pageSize, (page-1)*pageSize)
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "questions": questions,
+        "page":      page,
+        "page_size": pageSize,
+        "total":     total,
+    })
+}
+
+// GetQuestion returns one question with its answer
+func (h *AdminHandler) GetQuestion(c *gin.Context) {
+    question, err := h.questionService.Get(c.Param("id"))
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.JSON(http.StatusOK, question)
+}
+
+// CreateQuestion adds a question to the bank
+func (h *AdminHandler) CreateQuestion(c *gin.Context) {
+    var input service.QuestionInput
+    if err := c.ShouldBindJSON(&input); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+        return
+    }
+
+    question, err := h.questionService.Create(input)
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.JSON(http.StatusCreated, question)
+}
+
+// UpdateQuestion replaces a question
+func (h *AdminHandler) UpdateQuestion(c *gin.Context) {
+    var input service.QuestionInput
+    if err := c.ShouldBindJSON(&input); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+        return
+    }
+
+    question, err := h.questionService.Update(c.Param("id"), input)
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.JSON(http.StatusOK, question)
+}
+
+// DeleteQuestion removes a question from the bank
+func (h *AdminHandler) DeleteQuestion(c *gin.Context) {
+    if err := h.questionService.Delete(c.Param("id")); err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.Status(http.StatusNoContent)
+}
+
+// ImportQuestions adds questions from an uploaded CSV, JSON or SQL file.
+// It's a dry run unless dry_run=false.
+func (h *AdminHandler) ImportQuestions(c *gin.Context) {
+    c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+    body := io.Reader(c.Request.Body)
+    format := c.Query("format")
+    if file, header, err := c.Request.FormFile("file"); err == nil {
+        defer file.Close()
+        body = file
+        if format == "" {
+            format = service.FormatFromFilename(header.Filename)
+        }
+    }
+    if format == "" {
+        format = formatFromContentType(c.ContentType())
+    }
+
+    dryRun := c.Query("dry_run") != "false"
+    result, err := h.questionService.Import(format, body, dryRun)
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.JSON(http.StatusOK, result)
+}
+
+// ExportQuestions downloads the question bank as CSV or JSON
+func (h *AdminHandler) ExportQuestions(c *gin.Context) {
+    format := c.DefaultQuery("format", service.FormatCSV)
+    if format != service.FormatCSV && format != service.FormatJSON {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+        return
+    }
+
+    filter := repository.QuestionFilter{Category: c.Query("category")}
+
+    c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="questions.%s"`, format))
+    if format == service.FormatCSV {
+        c.Header("Content-Type", "text/csv; charset=utf-8")
+    } else {
+        c.Header("Content-Type", "application/json; charset=utf-8")
+    }
+
+    if err := h.questionService.Export(format, c.Writer, filter); err != nil {
+        slog.Error("Error exporting questions", "format", format, "err", err)
+    }
+}
+
+// QuestionReport shows how questions have fared in past games. With
+// flagged=true only questions that look broken are listed.
+func (h *AdminHandler) QuestionReport(c *gin.Context) {
+    page, pageSize := pagination(c)
+
+    report, err := h.questionService.Report(c.Query("category"), c.Query("flagged") == "true")
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    total := len(report)
+    start := (page - 1) * pageSize
+    if start > total {
+        start = total
+    }
+    end := start + pageSize
+    if end > total {
+        end = total
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "questions": report[start:end],
+        "page":      page,
+        "page_size": pageSize,
+        "total":     total,
+    })
+}
+
+// ListCategories returns the categories in use and their question counts
+func (h *AdminHandler) ListCategories(c *gin.Context) {
+    categories, err := h.questionService.Categories()
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"categories": categories})
+}
+
+// BanAccount bans an account and revokes its sessions
+func (h *AdminHandler) BanAccount(c *gin.Context) {
+    var req struct {
+        Reason string `json:"reason"`
+    }
+    // The body is optional
+    if c.Request.ContentLength != 0 {
+        if err := c.ShouldBindJSON(&req); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+    }
+
+    if err := h.accountService.Ban(c.Param("id"), req.Reason); err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.Status(http.StatusNoContent)
+}
+
+// UnbanAccount lifts an account's ban
+func (h *AdminHandler) UnbanAccount(c *gin.Context) {
+    if err := h.accountService.Unban(c.Param("id")); err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.Status(http.StatusNoContent)
+}
+
+func (h *AdminHandler) RegisterRoutes(r *gin.Engine) {
+    admin := r.Group("/api/admin", h.RequireAdmin)
+    {
+        admin.GET("/questions", h.ListQuestions)
+        admin.POST("/questions", h.CreateQuestion)
+        admin.POST("/questions/import", h.ImportQuestions)
+        admin.GET("/questions/export", h.ExportQuestions)
+        admin.GET("/questions/report", h.QuestionReport)
+        admin.GET("/questions/:id", h.GetQuestion)
+        admin.PUT("/questions/:id", h.UpdateQuestion)
+        admin.DELETE("/questions/:id", h.DeleteQuestion)
+        admin.GET("/categories", h.ListCategories)
+        admin.POST("/accounts/:id/ban", h.BanAccount)
+        admin.DELETE("/accounts/:id/ban", h.UnbanAccount)
+    }
+}
+
+// formatFromContentType guesses an import format from a request's content type
+func formatFromContentType(contentType string) string {
+    switch contentType {
+    case "text/csv":
+        return service.FormatCSV
+    case "application/json":
+        return service.FormatJSON
+    case "application/sql", "text/x-sql":
+        return service.FormatSQL
+    }
+    return ""
+}
@@ -0,0 +1,74 @@
+// internal/handlers/admin_handler_test.go
+
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBanAccountRoutes(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    accounts := openTestAccounts(t)
+    admin, err := accounts.Register("admin", "correct horse", "")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := accounts.GrantAdmin("admin"); err != nil {
+        t.Fatal(err)
+    }
+    alice, err := accounts.Register("alice", "correct horse", "")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    router := gin.New()
+    NewAuthHandler(accounts).RegisterRoutes(router)
+    NewAdminHandler(nil, accounts).RegisterRoutes(router)
+
+    send := func(method, path, token, body string) *httptest.ResponseRecorder {
+        req := httptest.NewRequest(method, path, strings.NewReader(body))
+        if token != "" {
+            req.Header.Set("Authorization", "Bearer "+token)
+        }
+        rec := httptest.NewRecorder()
+        router.ServeHTTP(rec, req)
+        return rec
+    }
+    login := `{"username":"alice","password":"correct horse"}`
+    banPath := "/api/admin/accounts/" + alice.Account.ID.String() + "/ban"
+
+    // Only admins can ban
+    if rec := send(http.MethodPost, banPath, alice.Token, ""); rec.Code != http.StatusForbidden {
+        t.Fatalf("non-admin ban: got %d, want 403", rec.Code)
+    }
+
+    if rec := send(http.MethodPost, banPath, admin.Token, `{"reason":"spamming"}`); rec.Code != http.StatusNoContent {
+        t.Fatalf("ban: got %d, want 204: %s", rec.Code, rec.Body)
+    }
+    if rec := send(http.MethodGet, "/api/auth/me", alice.Token, ""); rec.Code != http.StatusUnauthorized {
+        t.Errorf("token issued before the ban: got %d, want 401", rec.Code)
+    }
+    if rec := send(http.MethodPost, "/api/auth/login", "", login); rec.Code != http.StatusForbidden {
+        t.Errorf("banned login: got %d, want 403", rec.Code)
+    }
+
+    if rec := send(http.MethodDelete, banPath, admin.Token, ""); rec.Code != http.StatusNoContent {
+        t.Fatalf("unban: got %d, want 204: %s", rec.Code, rec.Body)
+    }
+    if rec := send(http.MethodPost, "/api/auth/login", "", login); rec.Code != http.StatusOK {
+        t.Errorf("login after unban: got %d, want 200", rec.Code)
+    }
+
+    // Unknown and malformed IDs are not found
+    for _, id := range []string{"00000000-0000-0000-0000-000000000000", "not-a-uuid"} {
+        if rec := send(http.MethodPost, "/api/admin/accounts/"+id+"/ban", admin.Token, ""); rec.Code != http.StatusNotFound {
+            t.Errorf("ban %s: got %d, want 404", id, rec.Code)
+        }
+    }
+}
@@ -0,0 +1,194 @@
+// internal/handlers/auth_handler.go
+
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+	"github.com/rohan03122001/quizzing/internal/service"
+)
+
+type AuthHandler struct {
+    accountService *service.AccountService
+    logMagicLinks  bool // Log magic-link tokens, for development without email
+}
+
+func NewAuthHandler(accountService *service.AccountService) *AuthHandler {
+    return &AuthHandler{
+        accountService: accountService,
+    }
+}
+
+// LogMagicLinks logs the token of each magic link issued, so they can be
+// used in development. Never enable it in production: anyone reading the
+// logs can sign in as the account.
+func (h *AuthHandler) LogMagicLinks(enabled bool) {
+    h.logMagicLinks = enabled
+}
+
+type RegisterRequest struct {
+    Username string `json:"username"`
+    Password string `json:"password"`
+    Email    string `json:"email"`
+}
+
+type LoginRequest struct {
+    Username string `json:"username"`
+    Password string `json:"password"`
+}
+
+type MagicLinkRequest struct {
+    Email string `json:"email"`
+}
+
+type RedeemMagicLinkRequest struct {
+    Token string `json:"token"`
+}
+
+// Register creates an account and signs it in
+func (h *AuthHandler) Register(c *gin.Context) {
+    var req RegisterRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+        return
+    }
+
+    session, err := h.accountService.Register(req.Username, req.Password, req.Email)
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.JSON(http.StatusCreated, session)
+}
+
+// Login signs in with a username and password
+func (h *AuthHandler) Login(c *gin.Context) {
+    var req LoginRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+        return
+    }
+
+    session, err := h.accountService.Login(req.Username, req.Password)
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.JSON(http.StatusOK, session)
+}
+
+// RequestMagicLink issues a sign-in link token. It's meant to be emailed,
+// so it's never in the response; until emails are sent it is only logged,
+// and only when LogMagicLinks is on.
+func (h *AuthHandler) RequestMagicLink(c *gin.Context) {
+    var req MagicLinkRequest
+    if err := c.ShouldBindJSON(&req); err != nil || req.Email == "" {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+        return
+    }
+
+    token, found, err := h.accountService.RequestMagicLink(req.Email)
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    if found && h.logMagicLinks {
+        slog.Warn("Magic link issued; log_magic_links is on, don't use it in production",
+            "email", req.Email, "token", token)
+    }
+
+    // Same answer whether or not the email is registered
+    c.JSON(http.StatusAccepted, gin.H{"message": "if that email is registered, a sign-in link has been issued"})
+}
+
+// RedeemMagicLink exchanges a magic-link token for a session
+func (h *AuthHandler) RedeemMagicLink(c *gin.Context) {
+    var req RedeemMagicLinkRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+        return
+    }
+
+    session, err := h.accountService.RedeemMagicLink(req.Token)
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.JSON(http.StatusOK, session)
+}
+
+// Me returns the signed-in account
+func (h *AuthHandler) Me(c *gin.Context) {
+    account, err := h.accountService.Authenticate(accessToken(c))
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.JSON(http.StatusOK, account)
+}
+
+// Logout revokes the session token
+func (h *AuthHandler) Logout(c *gin.Context) {
+    if err := h.accountService.Logout(accessToken(c)); err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) RegisterRoutes(r *gin.Engine) {
+    auth := r.Group("/api/auth")
+    {
+        auth.POST("/register", h.Register)
+        auth.POST("/login", h.Login)
+        auth.POST("/magic-link", h.RequestMagicLink)
+        auth.POST("/magic-link/redeem", h.RedeemMagicLink)
+        auth.GET("/me", h.Me)
+        auth.POST("/logout", h.Logout)
+    }
+}
+
+// accessToken reads an account session token from the Authorization header
+// or, for WebSocket and EventSource clients that can't set headers, the
+// access_token query parameter
+func accessToken(c *gin.Context) string {
+    if header := c.GetHeader("Authorization"); strings.HasPrefix(header, "Bearer ") {
+        return strings.TrimPrefix(header, "Bearer ")
+    }
+    return c.Query("access_token")
+}
+
+// respondError writes a service error with a matching HTTP status
+func respondError(c *gin.Context, err error) {
+    code := service.ErrorCodeOf(err)
+
+    status := http.StatusBadRequest
+    switch code {
+    case protocol.CodeInternal:
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+        return
+    case protocol.CodeInvalidCredentials, protocol.CodeUnauthorized:
+        status = http.StatusUnauthorized
+    case protocol.CodeAccountBanned, protocol.CodeForbidden, protocol.CodeNotHost:
+        status = http.StatusForbidden
+    case protocol.CodeUsernameTaken, protocol.CodeEmailTaken, protocol.CodeDuplicateQuestion:
+        status = http.StatusConflict
+    case protocol.CodeGameNotFound, protocol.CodeRoomNotFound, protocol.CodeQuestionNotFound,
+        protocol.CodePackNotFound, protocol.CodeAccountNotFound:
+        status = http.StatusNotFound
+    case protocol.CodeServerDraining:
+        status = http.StatusServiceUnavailable
+    }
+
+    c.JSON(status, gin.H{"error": err.Error(), "code": code})
+}
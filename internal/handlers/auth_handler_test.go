@@ -0,0 +1,64 @@
+// internal/handlers/auth_handler_test.go
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rohan03122001/quizzing/internal/repository"
+	"github.com/rohan03122001/quizzing/internal/service"
+)
+
+func TestMagicLinkResponseHasNoToken(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    accounts := openTestAccounts(t)
+    if _, err := accounts.Register("alice", "", "alice@example.com"); err != nil {
+        t.Fatal(err)
+    }
+
+    handler := NewAuthHandler(accounts)
+    router := gin.New()
+    handler.RegisterRoutes(router)
+
+    // Neither a registered email nor dev logging puts the token in the reply
+    for _, logged := range []bool{false, true} {
+        handler.LogMagicLinks(logged)
+        for _, email := range []string{"alice@example.com", "nobody@example.com"} {
+            rec := httptest.NewRecorder()
+            body := strings.NewReader(`{"email":"` + email + `"}`)
+            router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/auth/magic-link", body))
+
+            if rec.Code != http.StatusAccepted {
+                t.Fatalf("%s: got %d, want 202", email, rec.Code)
+            }
+            var reply map[string]interface{}
+            if err := json.Unmarshal(rec.Body.Bytes(), &reply); err != nil {
+                t.Fatal(err)
+            }
+            if _, ok := reply["token"]; ok {
+                t.Errorf("%s: the reply has the token: %s", email, rec.Body)
+            }
+        }
+    }
+}
+
+// openTestAccounts returns an account service on an empty in-memory
+// SQLite database
+func openTestAccounts(t *testing.T) *service.AccountService {
+    t.Helper()
+    db, err := repository.NewDatabase(&repository.DBConfig{Driver: repository.DriverSQLite, Path: ":memory:"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { db.Close() })
+    if _, err := db.MigrateUp(); err != nil {
+        t.Fatal(err)
+    }
+    return service.NewAccountService(repository.NewAccountRepository(db))
+}
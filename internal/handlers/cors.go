@@ -0,0 +1,60 @@
+// internal/handlers/cors.go
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Origins are the browser origins allowed to call the API and open
+// WebSockets. "*" allows any.
+type Origins []string
+
+// Allows reports whether a request's Origin header is allowed. Requests
+// without one don't come from a browser page and are always allowed.
+func (o Origins) Allows(origin string) bool {
+    if origin == "" {
+        return true
+    }
+    for _, allowed := range o {
+        if allowed == "*" || allowed == origin {
+            return true
+        }
+    }
+    return false
+}
+
+func (o Origins) any() bool {
+    for _, allowed := range o {
+        if allowed == "*" {
+            return true
+        }
+    }
+    return false
+}
+
+// CORS sets the CORS headers for allowed origins and answers preflight
+// requests. It has to be added before the routes.
+func CORS(origins Origins) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        origin := c.GetHeader("Origin")
+        switch {
+        case origins.any():
+            c.Header("Access-Control-Allow-Origin", "*")
+        case origin != "" && origins.Allows(origin):
+            c.Header("Access-Control-Allow-Origin", origin)
+            c.Header("Access-Control-Allow-Credentials", "true")
+        }
+        c.Header("Vary", "Origin")
+        c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+        c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+
+        if c.Request.Method == http.MethodOptions {
+            c.AbortWithStatus(http.StatusNoContent)
+            return
+        }
+        c.Next()
+    }
+}
@@ -3,110 +3,162 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+	"github.com/rohan03122001/quizzing/internal/ratelimit"
 	"github.com/rohan03122001/quizzing/internal/service"
+	"github.com/rohan03122001/quizzing/internal/tracing"
 	"github.com/rohan03122001/quizzing/internal/websocket"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// Message types
-const (
-    EventJoinRoom     = "join_room"
-    EventLeaveRoom    = "leave_room"
-    EventStartGame    = "start_game"
-    EventSubmitAnswer = "submit_answer"
-    EventPlayAgain    = "play_again"
-    EventReconnect    = "reconnect"
-)
-
-// Request structures
-type JoinRoomData struct {
-    RoomCode string `json:"room_code"`
-    Username string `json:"username"`
-}
-
-type SubmitAnswerData struct {
-    Answer string `json:"answer"`
-}
-
-type GameSettings struct {
-    MaxRounds  int `json:"max_rounds"`
-    RoundTime  int `json:"round_time"`
-}
-
-type ReconnectData struct {
-    RoomCode  string `json:"room_code"`
-    PlayerID  string `json:"player_id"`
-    Username  string `json:"username"`
-}
-
 type GameHandler struct {
-    gameService *service.GameService
-    roomService *service.RoomService
-    hub         *websocket.Hub
+    gameService    *service.GameService
+    roomService    *service.RoomService
+    accountService *service.AccountService
+    hub            *websocket.Hub
+    limits         ratelimit.Limits // Per connection, none when zero
 }
 
 func NewGameHandler(
     gameService *service.GameService,
     roomService *service.RoomService,
+    accountService *service.AccountService,
     hub *websocket.Hub,
 ) *GameHandler {
     return &GameHandler{
-        gameService: gameService,
-        roomService: roomService,
-        hub:         hub,
+        gameService:    gameService,
+        roomService:    roomService,
+        accountService: accountService,
+        hub:            hub,
     }
 }
 
+// SetRateLimits sets the limits on each connection's messages. They apply
+// to clients connecting afterwards.
+func (h *GameHandler) SetRateLimits(limits ratelimit.Limits) {
+    h.limits = limits
+}
+
+// limit gives a new client its own message limits
+func (h *GameHandler) limit(client *websocket.Client) {
+    client.Limits = h.limits.NewConn()
+}
+
 // HandleMessage processes incoming WebSocket messages
 func (h *GameHandler) HandleMessage(client *websocket.Client, message []byte) error {
     var event struct {
-        Type string          `json:"type"`
-        Data json.RawMessage `json:"data"`
+        Type      string          `json:"type"`
+        RequestID string          `json:"request_id"`
+        Data      json.RawMessage `json:"data"`
     }
 
-    if err := json.Unmarshal(message, &event); err != nil {
-        return h.sendError(client, "Invalid message format")
+    err := json.Unmarshal(message, &event)
+
+    // Garbage counts against the limits too
+    if ok, retryAfter, abusive := client.Limits.Allow(event.Type); !ok {
+        return h.rejectFlood(client, event.Type, event.RequestID, retryAfter, abusive)
+    }
+
+    if err != nil {
+        return h.sendError(client, "", "", errInvalidMessage("Invalid message format"))
     }
 
-    log.Printf("Received message type: %s from client: %s", event.Type, client.ID)
+    client.Log().Debug("Received message", "event", event.Type)
 
-    switch event.Type {
-    case EventJoinRoom:
-        return h.handleJoinRoom(client, event.Data)
-    case EventStartGame:
-        return h.handleStartGame(client)
-    case EventSubmitAnswer:
-        return h.handleSubmitAnswer(client, event.Data)
-    case EventPlayAgain:
-        return h.handlePlayAgain(client, event.Data)
-    case EventReconnect:
-        return h.handleReconnect(client, event.Data)
+    // Each message starts its own trace
+    ctx, span := tracing.Tracer().Start(context.Background(), "ws "+event.Type,
+        trace.WithNewRoot(),
+        trace.WithSpanKind(trace.SpanKindServer),
+        trace.WithAttributes(tracing.EventKey.String(event.Type), attribute.String("request_id", event.RequestID)),
+    )
+    defer span.End()
+
+    err = h.dispatch(ctx, client, event.Type, event.Data)
+
+    // Joining or reconnecting sets these, so record them afterwards
+    span.SetAttributes(tracing.RoomKey.String(client.RoomID), tracing.PlayerKey.String(client.ID))
+    if err != nil {
+        code := service.ErrorCodeOf(err)
+        span.SetAttributes(tracing.ErrCodeKey.String(string(code)))
+        if code == protocol.CodeInternal {
+            span.RecordError(err)
+            span.SetStatus(codes.Error, err.Error())
+        }
+        return h.sendError(client, event.Type, event.RequestID, err)
+    }
+
+    return h.sendAck(client, event.Type, event.RequestID)
+}
+
+// dispatch routes a message to its handler
+func (h *GameHandler) dispatch(ctx context.Context, client *websocket.Client, eventType string, data json.RawMessage) error {
+    switch eventType {
+    case protocol.EventJoinRoom:
+        return h.handleJoinRoom(ctx, client, data)
+    case protocol.EventReconnect:
+        return h.handleReconnect(ctx, client, data)
+    case protocol.EventPing:
+        return h.handlePing(client, data)
+    }
+
+    // Everything else needs the client to be in a room
+    if client.RoomID == "" {
+        switch eventType {
+        case protocol.EventStartGame, protocol.EventSubmitAnswer, protocol.EventPlayAgain,
+            protocol.EventPauseGame, protocol.EventResumeGame, protocol.EventSkipQuestion,
+            protocol.EventReady:
+            return service.NewError(protocol.CodeNotInRoom, "join a room first")
+        }
+    }
+
+    switch eventType {
+    case protocol.EventStartGame:
+        return h.handleStartGame(ctx, client)
+    case protocol.EventSubmitAnswer:
+        return h.handleSubmitAnswer(ctx, client, data)
+    case protocol.EventPlayAgain:
+        return h.handlePlayAgain(ctx, client, data)
+    case protocol.EventPauseGame:
+        return h.handlePauseGame(ctx, client)
+    case protocol.EventResumeGame:
+        return h.handleResumeGame(ctx, client)
+    case protocol.EventSkipQuestion:
+        return h.handleSkipQuestion(ctx, client)
+    case protocol.EventReady:
+        return h.gameService.MarkReady(client.RoomID, client.ID)
     default:
-        return h.sendError(client, "Unknown event type")
+        return service.NewError(protocol.CodeUnknownEvent, "Unknown event type")
     }
 }
 
 // internal/handlers/game_handler.go
 
-func (h *GameHandler) handleJoinRoom(client *websocket.Client, data json.RawMessage) error {
-    var joinData JoinRoomData
+func (h *GameHandler) handleJoinRoom(ctx context.Context, client *websocket.Client, data json.RawMessage) error {
+    var joinData protocol.JoinRoomData
     if err := json.Unmarshal(data, &joinData); err != nil {
-        return h.sendError(client, "Invalid join data format")
+        return errInvalidMessage("Invalid join data format")
     }
 
     // Join room
-    room, err := h.roomService.JoinRoom(joinData.RoomCode, client.ID)
+    room, err := h.roomService.JoinRoom(ctx, joinData.RoomCode, client.ID)
     if err != nil {
-        return h.sendError(client, err.Error())
+        return err
     }
 
-    // Update client info
+    // Update client info. Signed-in players keep their account name unless
+    // they picked another one.
     client.RoomID = room.Code  // Changed from ID to Code
-    client.Username = joinData.Username
+    if joinData.Username != "" || client.AccountID == "" {
+        client.Username = joinData.Username
+    }
 
     // Register client with hub
     h.hub.Register <- client
@@ -116,113 +168,77 @@ func (h *GameHandler) handleJoinRoom(client *websocket.Client, data json.RawMess
 
     // Notify room about new player
     h.hub.BroadcastToRoom(room.Code, websocket.GameEvent{
-        Type: "player_joined",
-        Data: map[string]interface{}{
-            "player_id": client.ID,
-            "username": joinData.Username,
-            "total_players": len(players) + 1,
+        Type: protocol.EventPlayerJoined,
+        Data: protocol.PlayerJoinedData{
+            PlayerID:     client.ID,
+            Username:     client.Username,
+            TotalPlayers: len(players) + 1,
         },
     })
 
     // Send room state to new player
     return h.hub.SendToClient(client, websocket.GameEvent{
-        Type: "room_joined",
-        Data: map[string]interface{}{
-            "room_code": room.Code,
-            "players": players,
-            "settings": map[string]interface{}{
-                "max_players": room.MaxPlayers,
-                "round_time": room.RoundTime,
-                "max_rounds": room.MaxRounds,
-            },
+        Type: protocol.EventRoomJoined,
+        Data: protocol.RoomJoinedData{
+            RoomCode: room.Code,
+            HostID:   room.HostID,
+            Players:  players,
+            Settings: service.SettingsOf(room),
         },
     })
 }
 
-func (h *GameHandler) handleStartGame(client *websocket.Client) error {
-    log.Printf("Start game request from client %s in room %s", client.ID, client.RoomID)
+func (h *GameHandler) handleStartGame(ctx context.Context, client *websocket.Client) error {
+    client.Log().Info("Start game requested", "event", protocol.EventStartGame)
 
-    // Start the game using room code
-    if err := h.roomService.StartGame(client.RoomID); err != nil {
-        return h.sendError(client, err.Error())
+    if err := h.roomService.RequireHost(ctx, client.RoomID, client.ID); err != nil {
+        return err
     }
 
-    // Start first round
-    question, err := h.gameService.StartRound(client.RoomID)
-    if err != nil {
-        return h.sendError(client, err.Error())
+    // Start the game using room code
+    if err := h.roomService.StartGame(ctx, client.RoomID); err != nil {
+        return err
     }
 
-    // Broadcast question to room
-    h.hub.BroadcastToRoom(client.RoomID, websocket.GameEvent{
-        Type: "round_started",
-        Data: map[string]interface{}{
-            "question": question,
-            "round_number": 1,
-        },
-    })
+    // Start first round, which broadcasts the question to the room
+    if _, err := h.gameService.StartRound(ctx, client.RoomID); err != nil {
+        return err
+    }
 
     return nil
 }
 
-func (h *GameHandler) handleSubmitAnswer(client *websocket.Client, data json.RawMessage) error {
-    var answerData SubmitAnswerData
+func (h *GameHandler) handleSubmitAnswer(ctx context.Context, client *websocket.Client, data json.RawMessage) error {
+    var answerData protocol.SubmitAnswerData
     if err := json.Unmarshal(data, &answerData); err != nil {
-        return h.sendError(client, "Invalid answer format")
+        return errInvalidMessage("Invalid answer format")
     }
 
     // Process answer
-    result, err := h.gameService.ProcessAnswer(client.RoomID, client.ID, answerData.Answer)
+    result, err := h.gameService.ProcessAnswer(ctx, client.RoomID, client.ID, answerData.Answer)
     if err != nil {
-        return h.sendError(client, err.Error())
+        return err
     }
 
-    // Send result to the player
-    err = h.hub.SendToClient(client, websocket.GameEvent{
-        Type: "answer_result",
+    // Send result to the player. The service ends the round once everyone
+    // has answered.
+    return h.hub.SendToClient(client, websocket.GameEvent{
+        Type: protocol.EventAnswerResult,
         Data: result,
     })
-    if err != nil {
-        return err
-    }
+}
 
-    // Check if round should end
-    if h.gameService.ShouldEndRound(client.RoomID) {
-        return h.endRound(client.RoomID)
+func (h *GameHandler) handlePlayAgain(ctx context.Context, client *websocket.Client, data json.RawMessage) error {
+    var settings protocol.PlayAgainData
+    if err := json.Unmarshal(data, &settings); err != nil {
+        return errInvalidMessage("Invalid settings format")
     }
 
-    return nil
-}
-
-func (h *GameHandler) endRound(roomID string) error {
-    if err := h.gameService.EndRound(roomID); err != nil {
+    if err := h.roomService.RequireHost(ctx, client.RoomID, client.ID); err != nil {
         return err
     }
-
-    // Start next round after delay
-    go func() {
-        time.Sleep(5 * time.Second)
-        question, err := h.gameService.StartRound(roomID)
-        if err != nil {
-            log.Printf("Error starting next round: %v", err)
-            return
-        }
-
-        h.hub.BroadcastToRoom(roomID, websocket.GameEvent{
-            Type: "round_started",
-            Data: map[string]interface{}{
-                "question": question,
-            },
-        })
-    }()
-
-    return nil
-}
-
-func (h *GameHandler) handlePlayAgain(client *websocket.Client, data json.RawMessage) error {
-    var settings GameSettings
-    if err := json.Unmarshal(data, &settings); err != nil {
-        return h.sendError(client, "Invalid settings format")
+    if h.roomService.Draining() {
+        return service.ErrServerDraining
     }
 
     // Validate settings
@@ -234,26 +250,80 @@ func (h *GameHandler) handlePlayAgain(client *websocket.Client, data json.RawMes
     }
 
     // Restart game
-    if err := h.gameService.RestartGame(client.RoomID, &models.GameSettings{
-        MaxRounds: settings.MaxRounds,
-        RoundTime: settings.RoundTime,
+    if err := h.gameService.RestartGame(ctx, client.RoomID, &models.GameSettings{
+        MaxRounds:        settings.MaxRounds,
+        RoundTime:        settings.RoundTime,
+        TimerUpdates:     settings.TimerUpdates,
+        IntermissionTime: settings.IntermissionTime,
+        ReadyCheck:       settings.ReadyCheck,
+        DifficultyRamp:   settings.DifficultyRamp,
     }); err != nil {
-        return h.sendError(client, err.Error())
+        return err
     }
 
     return nil
 }
 
-func (h *GameHandler) handleReconnect(client *websocket.Client, data json.RawMessage) error {
-    var reconnectData ReconnectData
+func (h *GameHandler) handlePauseGame(ctx context.Context, client *websocket.Client) error {
+    if err := h.roomService.RequireHost(ctx, client.RoomID, client.ID); err != nil {
+        return err
+    }
+    return h.gameService.PauseGame(ctx, client.RoomID, client.ID)
+}
+
+func (h *GameHandler) handleResumeGame(ctx context.Context, client *websocket.Client) error {
+    if err := h.roomService.RequireHost(ctx, client.RoomID, client.ID); err != nil {
+        return err
+    }
+    return h.gameService.ResumeGame(ctx, client.RoomID)
+}
+
+func (h *GameHandler) handleSkipQuestion(ctx context.Context, client *websocket.Client) error {
+    if err := h.roomService.RequireHost(ctx, client.RoomID, client.ID); err != nil {
+        return err
+    }
+    return h.gameService.SkipQuestion(ctx, client.RoomID)
+}
+
+// handlePing answers a clock-sync ping so the client can estimate its
+// offset from the server clock
+func (h *GameHandler) handlePing(client *websocket.Client, data json.RawMessage) error {
+    var ping protocol.PingData
+    if len(data) > 0 {
+        if err := json.Unmarshal(data, &ping); err != nil {
+            return errInvalidMessage("Invalid ping format")
+        }
+    }
+
+    return h.hub.SendToClient(client, websocket.GameEvent{
+        Type: protocol.EventPong,
+        Data: protocol.PongData{
+            ClientTime: ping.ClientTime,
+            ServerTime: time.Now().UnixMilli(),
+        },
+    })
+}
+
+func (h *GameHandler) handleReconnect(ctx context.Context, client *websocket.Client, data json.RawMessage) error {
+    var reconnectData protocol.ReconnectData
     if err := json.Unmarshal(data, &reconnectData); err != nil {
-        return h.sendError(client, "Invalid reconnect data format")
+        return errInvalidMessage("Invalid reconnect data format")
+    }
+
+    // Signed-in players can only be themselves, and an account's player
+    // can only be resumed by signing in as it
+    if client.AccountID != "" {
+        if reconnectData.PlayerID != client.AccountID {
+            return service.ErrInvalidPlayer
+        }
+    } else if h.accountService.IsAccount(reconnectData.PlayerID) {
+        return service.ErrAuthRequired
     }
 
     // Verify room exists and is active
     room, err := h.roomService.GetRoom(reconnectData.RoomCode)
     if err != nil {
-        return h.sendError(client, "Room not found")
+        return service.ErrRoomNotFound
     }
     
     // Check if this player ID was in this room
@@ -261,11 +331,11 @@ func (h *GameHandler) handleReconnect(client *websocket.Client, data json.RawMes
     
     if !wasInRoom {
         // As a fallback, check game history
-        playerAnswers, err := h.gameService.GetPlayerAnswers(room.Code, reconnectData.PlayerID)
+        playerAnswers, err := h.gameService.GetPlayerAnswers(ctx, room.Code, reconnectData.PlayerID)
         if err != nil || len(playerAnswers) == 0 {
-            log.Printf("Rejected reconnection attempt with invalid player ID: %s to room %s", 
-                reconnectData.PlayerID, reconnectData.RoomCode)
-            return h.sendError(client, "Invalid player ID or player was not in this room")
+            slog.Info("Rejected reconnection with an unknown player ID",
+                "room", reconnectData.RoomCode, "player", reconnectData.PlayerID, "event", protocol.EventReconnect)
+            return service.ErrInvalidPlayer
         }
     }
     
@@ -283,34 +353,86 @@ func (h *GameHandler) handleReconnect(client *websocket.Client, data json.RawMes
     h.hub.Register <- client
 
     // Get game state
-    gameState, err := h.gameService.GetGameState(room.Code, client.ID)
+    gameState, err := h.gameService.GetGameState(ctx, room.Code, client.ID)
     if err != nil {
-        return h.sendError(client, err.Error())
+        return err
     }
 
     // Notify other players about the reconnection
     h.hub.BroadcastToRoom(room.Code, websocket.GameEvent{
-        Type: "player_reconnected",
-        Data: map[string]interface{}{
-            "player_id": client.ID,
-            "username": client.Username,
+        Type: protocol.EventPlayerReconnected,
+        Data: protocol.PlayerData{
+            PlayerID: client.ID,
+            Username: client.Username,
         },
     })
 
-    log.Printf("Player %s (%s) reconnected to room %s", client.ID, client.Username, room.Code)
+    client.Log().Info("Player reconnected", "username", client.Username, "event", protocol.EventReconnect)
 
     // Send current game state to reconnected player
     return h.hub.SendToClient(client, websocket.GameEvent{
-        Type: "reconnected",
+        Type: protocol.EventReconnected,
         Data: gameState,
     })
 }
 
-func (h *GameHandler) sendError(client *websocket.Client, message string) error {
+// sendError replies with a coded error, echoing the request ID if there was one
+func (h *GameHandler) sendError(client *websocket.Client, eventType string, requestID string, err error) error {
+    code := service.ErrorCodeOf(err)
+    message := err.Error()
+    if code == protocol.CodeInternal {
+        // Don't leak internal details to clients
+        client.Log().Error("Internal error", "event", eventType, "err", err)
+        message = "internal server error"
+    }
+
+    return h.hub.SendToClient(client, websocket.GameEvent{
+        Type:      protocol.EventError,
+        RequestID: requestID,
+        Data: protocol.ErrorData{
+            Code:    code,
+            Message: message,
+        },
+    })
+}
+
+// rejectFlood answers a message over the client's rate limits with how long
+// to wait. A client that keeps at it is dropped.
+func (h *GameHandler) rejectFlood(client *websocket.Client, eventType string, requestID string, retryAfter time.Duration, abusive bool) error {
+    if abusive {
+        client.Log().Warn("Dropping client for flooding", "event", eventType)
+        client.Kick("rate limit exceeded")
+        return nil
+    }
+
+    client.Log().Debug("Rate limited message", "event", eventType, "retry_after", retryAfter)
     return h.hub.SendToClient(client, websocket.GameEvent{
-        Type: "error",
-        Data: map[string]string{
-            "message": message,
+        Type:      protocol.EventError,
+        RequestID: requestID,
+        Data: protocol.ErrorData{
+            Code:       protocol.CodeRateLimited,
+            Message:    "too many messages, slow down",
+            RetryAfter: retryAfter.Milliseconds(),
         },
     })
-}
\ No newline at end of file
+}
+
+// sendAck confirms a message was handled. Only sent when the client asked
+// for it by including a request ID.
+func (h *GameHandler) sendAck(client *websocket.Client, eventType string, requestID string) error {
+    if requestID == "" {
+        return nil
+    }
+
+    return h.hub.SendToClient(client, websocket.GameEvent{
+        Type:      protocol.EventAck,
+        RequestID: requestID,
+        Data: protocol.AckData{
+            Event: eventType,
+        },
+    })
+}
+
+func errInvalidMessage(message string) error {
+    return service.NewError(protocol.CodeInvalidMessage, message)
+}
@@ -0,0 +1,56 @@
+// internal/handlers/health_handler.go
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// How long the readiness check waits for the database
+const readyTimeout = 2 * time.Second
+
+// HealthHandler serves the liveness and readiness probes
+type HealthHandler struct {
+    ping     func(ctx context.Context) error // checks the database
+    draining func() bool
+}
+
+func NewHealthHandler(ping func(ctx context.Context) error, draining func() bool) *HealthHandler {
+    return &HealthHandler{
+        ping:     ping,
+        draining: draining,
+    }
+}
+
+// Healthz reports that the process is up
+func (h *HealthHandler) Healthz(c *gin.Context) {
+    c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz reports whether the server should get new players. It fails while
+// draining for shutdown or when the database can't be reached.
+func (h *HealthHandler) Readyz(c *gin.Context) {
+    if h.draining() {
+        c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+        return
+    }
+
+    ctx, cancel := context.WithTimeout(c.Request.Context(), readyTimeout)
+    defer cancel()
+    if err := h.ping(ctx); err != nil {
+        c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "error": "database unreachable"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// RegisterRoutes sets up the probe routes
+func (h *HealthHandler) RegisterRoutes(r *gin.Engine) {
+    r.GET("/healthz", h.Healthz)
+    r.GET("/readyz", h.Readyz)
+}
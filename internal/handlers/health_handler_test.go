@@ -0,0 +1,49 @@
+// internal/handlers/health_handler_test.go
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHealthProbes(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    var dbErr error
+    draining := false
+    router := gin.New()
+    NewHealthHandler(
+        func(ctx context.Context) error { return dbErr },
+        func() bool { return draining },
+    ).RegisterRoutes(router)
+
+    get := func(path string) int {
+        rec := httptest.NewRecorder()
+        router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+        return rec.Code
+    }
+
+    if code := get("/readyz"); code != http.StatusOK {
+        t.Errorf("ready: got %d, want 200", code)
+    }
+
+    dbErr = errors.New("connection refused")
+    if code := get("/readyz"); code != http.StatusServiceUnavailable {
+        t.Errorf("database down: got %d, want 503", code)
+    }
+    if code := get("/healthz"); code != http.StatusOK {
+        t.Errorf("liveness with the database down: got %d, want 200", code)
+    }
+
+    dbErr = nil
+    draining = true
+    if code := get("/readyz"); code != http.StatusServiceUnavailable {
+        t.Errorf("draining: got %d, want 503", code)
+    }
+}
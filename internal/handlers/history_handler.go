@@ -0,0 +1,92 @@
+// internal/handlers/history_handler.go
+
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rohan03122001/quizzing/internal/service"
+)
+
+const (
+    defaultPageSize = 20
+    maxPageSize     = 100
+)
+
+type HistoryHandler struct {
+    historyService *service.HistoryService
+}
+
+func NewHistoryHandler(historyService *service.HistoryService) *HistoryHandler {
+    return &HistoryHandler{
+        historyService: historyService,
+    }
+}
+
+// ListRoomGames returns the games played in a room, newest first
+func (h *HistoryHandler) ListRoomGames(c *gin.Context) {
+    page, pageSize := pagination(c)
+
+    games, total, err := h.historyService.ListRoomGames(c.Param("code"), pageSize, (page-1)*pageSize)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list games"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "games":     games,
+        "page":      page,
+        "page_size": pageSize,
+        "total":     total,
+    })
+}
+
+// GetGame returns one game's full result
+func (h *HistoryHandler) GetGame(c *gin.Context) {
+    game, err := h.historyService.GetGame(c.Param("id"))
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.JSON(http.StatusOK, game)
+}
+
+// GetPlayerStats returns a player's stats across their past games
+func (h *HistoryHandler) GetPlayerStats(c *gin.Context) {
+    stats, err := h.historyService.GetPlayerStats(c.Param("id"))
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.JSON(http.StatusOK, stats)
+}
+
+func (h *HistoryHandler) RegisterRoutes(r *gin.Engine) {
+    api := r.Group("/api")
+    {
+        api.GET("/rooms/:code/games", h.ListRoomGames)
+        api.GET("/games/:id", h.GetGame)
+        api.GET("/players/:id/stats", h.GetPlayerStats)
+    }
+}
+
+// pagination reads the page and page_size query parameters
+func pagination(c *gin.Context) (page int, pageSize int) {
+    page, err := strconv.Atoi(c.Query("page"))
+    if err != nil || page < 1 {
+        page = 1
+    }
+
+    pageSize, err = strconv.Atoi(c.Query("page_size"))
+    if err != nil || pageSize < 1 {
+        pageSize = defaultPageSize
+    }
+    if pageSize > maxPageSize {
+        pageSize = maxPageSize
+    }
+    return page, pageSize
+}
@@ -3,9 +3,11 @@
 package handlers
 
 import (
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rohan03122001/quizzing/internal/models"
 	"github.com/rohan03122001/quizzing/internal/service"
 )
 
@@ -19,11 +21,37 @@ func NewHTTPHandler(roomService *service.RoomService) *HTTPHandler {
     }
 }
 
+// CreateRoomRequest holds optional settings for a new room
+type CreateRoomRequest struct {
+    MaxPlayers       int   `json:"max_players"`
+    RoundTime        int   `json:"round_time"`
+    MaxRounds        int   `json:"max_rounds"`
+    TimerUpdates     *bool `json:"timer_updates"`
+    IntermissionTime int   `json:"intermission_time"`
+    ReadyCheck       *bool `json:"ready_check"`
+    DifficultyRamp   *bool `json:"difficulty_ramp"`
+}
+
 // CreateRoom handles room creation
 func (h *HTTPHandler) CreateRoom(c *gin.Context) {
-    room, err := h.roomService.CreateRoom()
+    // The body is optional; an empty one gets the default settings
+    var req CreateRoomRequest
+    if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+        return
+    }
+
+    room, err := h.roomService.CreateRoom(c.Request.Context(), &models.GameSettings{
+        MaxPlayers:       req.MaxPlayers,
+        MaxRounds:        req.MaxRounds,
+        RoundTime:        req.RoundTime,
+        TimerUpdates:     req.TimerUpdates,
+        IntermissionTime: req.IntermissionTime,
+        ReadyCheck:       req.ReadyCheck,
+        DifficultyRamp:   req.DifficultyRamp,
+    })
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        respondError(c, err)
         return
     }
 
@@ -88,18 +116,6 @@ func (h *HTTPHandler) ValidateRoom(c *gin.Context) {
 }
 
 func (h *HTTPHandler) RegisterRoutes(r *gin.Engine) {
-    // CORS middleware
-    r.Use(func(c *gin.Context) {
-        c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-        c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-        c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-        if c.Request.Method == "OPTIONS" {
-            c.AbortWithStatus(204)
-            return
-        }
-        c.Next()
-    })
-
     api := r.Group("/api")
     {
         api.POST("/rooms", h.CreateRoom)
@@ -0,0 +1,78 @@
+// internal/handlers/leaderboard_handler.go
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rohan03122001/quizzing/internal/service"
+)
+
+type LeaderboardHandler struct {
+    leaderboardService *service.LeaderboardService
+}
+
+func NewLeaderboardHandler(leaderboardService *service.LeaderboardService) *LeaderboardHandler {
+    return &LeaderboardHandler{
+        leaderboardService: leaderboardService,
+    }
+}
+
+// AllTime returns the all-time leaderboard
+func (h *LeaderboardHandler) AllTime(c *gin.Context) {
+    page, pageSize := pagination(c)
+    entries, total, err := h.leaderboardService.AllTime(pageSize, (page-1)*pageSize)
+    respondLeaderboard(c, "all_time", entries, total, page, pageSize, err)
+}
+
+// Weekly returns a week's leaderboard, the current week unless ?week= is given
+func (h *LeaderboardHandler) Weekly(c *gin.Context) {
+    page, pageSize := pagination(c)
+    entries, total, err := h.leaderboardService.Weekly(c.Query("week"), pageSize, (page-1)*pageSize)
+    respondLeaderboard(c, "weekly", entries, total, page, pageSize, err)
+}
+
+// Category returns a category's leaderboard
+func (h *LeaderboardHandler) Category(c *gin.Context) {
+    page, pageSize := pagination(c)
+    entries, total, err := h.leaderboardService.Category(c.Param("category"), pageSize, (page-1)*pageSize)
+    respondLeaderboard(c, "category", entries, total, page, pageSize, err)
+}
+
+// Categories lists the categories that have a leaderboard
+func (h *LeaderboardHandler) Categories(c *gin.Context) {
+    categories, err := h.leaderboardService.Categories()
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"categories": categories})
+}
+
+func (h *LeaderboardHandler) RegisterRoutes(r *gin.Engine) {
+    leaderboards := r.Group("/api/leaderboards")
+    {
+        leaderboards.GET("", h.AllTime)
+        leaderboards.GET("/all-time", h.AllTime)
+        leaderboards.GET("/weekly", h.Weekly)
+        leaderboards.GET("/categories", h.Categories)
+        leaderboards.GET("/categories/:category", h.Category)
+    }
+}
+
+func respondLeaderboard(c *gin.Context, board string, entries []service.RankedEntry, total int64, page int, pageSize int, err error) {
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "board":     board,
+        "entries":   entries,
+        "page":      page,
+        "page_size": pageSize,
+        "total":     total,
+    })
+}
@@ -0,0 +1,25 @@
+// internal/handlers/metrics_handler.go
+
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsHandler serves the Prometheus metrics of a registry
+type MetricsHandler struct {
+    gatherer prometheus.Gatherer
+}
+
+func NewMetricsHandler(gatherer prometheus.Gatherer) *MetricsHandler {
+    return &MetricsHandler{
+        gatherer: gatherer,
+    }
+}
+
+// RegisterRoutes sets up the metrics route
+func (h *MetricsHandler) RegisterRoutes(r *gin.Engine) {
+    r.GET("/metrics", gin.WrapH(promhttp.HandlerFor(h.gatherer, promhttp.HandlerOpts{})))
+}
@@ -0,0 +1,194 @@
+// internal/handlers/pack_handler.go
+
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/service"
+)
+
+// Largest pack file accepted
+const maxPackSize = 2 << 20
+
+// PackHandler serves hosts' private question packs. Every route needs an
+// account's session token.
+type PackHandler struct {
+    packService    *service.PackService
+    accountService *service.AccountService
+}
+
+func NewPackHandler(packService *service.PackService, accountService *service.AccountService) *PackHandler {
+    return &PackHandler{
+        packService:    packService,
+        accountService: accountService,
+    }
+}
+
+// RequireAccount rejects requests without a signed-in account
+func (h *PackHandler) RequireAccount(c *gin.Context) {
+    account, err := h.accountService.Authenticate(accessToken(c))
+    if err != nil {
+        respondError(c, err)
+        c.Abort()
+        return
+    }
+    c.Set("account", account)
+    c.Next()
+}
+
+// packFile is an uploaded pack: a multipart form with a file field, or the
+// file as the request body with the details in the query
+type packFile struct {
+    format      string
+    name        string
+    description string
+    body        io.Reader
+}
+
+func readPackFile(c *gin.Context) (*packFile, func()) {
+    c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxPackSize)
+
+    upload := &packFile{
+        format:      c.Query("format"),
+        name:        c.Query("name"),
+        description: c.Query("description"),
+        body:        c.Request.Body,
+    }
+    closeFile := func() {}
+
+    if file, header, err := c.Request.FormFile("file"); err == nil {
+        closeFile = func() { file.Close() }
+        upload.body = file
+        upload.name = c.DefaultPostForm("name", upload.name)
+        upload.description = c.DefaultPostForm("description", upload.description)
+        if upload.format == "" {
+            upload.format = service.FormatFromFilename(header.Filename)
+        }
+    }
+    if upload.format == "" {
+        upload.format = formatFromContentType(c.ContentType())
+    }
+    return upload, closeFile
+}
+
+// respondUpload answers an upload, listing the bad rows if there were any
+func respondUpload(c *gin.Context, result *service.PackUpload, status int) {
+    if len(result.Errors) > 0 {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":  "some rows are invalid, nothing was saved",
+            "rows":   result.Rows,
+            "errors": result.Errors,
+        })
+        return
+    }
+    c.JSON(status, result)
+}
+
+// CreatePack uploads a new pack from a CSV or JSON file
+func (h *PackHandler) CreatePack(c *gin.Context) {
+    upload, closeFile := readPackFile(c)
+    defer closeFile()
+
+    account := c.MustGet("account").(*models.Account)
+    result, err := h.packService.Create(account, upload.name, upload.description, upload.format, upload.body)
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    respondUpload(c, result, http.StatusCreated)
+}
+
+// ListPacks returns the signed-in account's packs
+func (h *PackHandler) ListPacks(c *gin.Context) {
+    packs, err := h.packService.List(c.MustGet("account").(*models.Account))
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"packs": packs})
+}
+
+// GetPack returns one of the account's packs with its questions
+func (h *PackHandler) GetPack(c *gin.Context) {
+    pack, err := h.packService.Get(c.MustGet("account").(*models.Account), c.Param("id"))
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.JSON(http.StatusOK, pack)
+}
+
+// ReplacePack replaces a pack's questions with those of a new file
+func (h *PackHandler) ReplacePack(c *gin.Context) {
+    upload, closeFile := readPackFile(c)
+    defer closeFile()
+
+    account := c.MustGet("account").(*models.Account)
+    result, err := h.packService.Replace(account, c.Param("id"), upload.name, upload.description, upload.format, upload.body)
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    respondUpload(c, result, http.StatusOK)
+}
+
+// DeletePack removes one of the account's packs
+func (h *PackHandler) DeletePack(c *gin.Context) {
+    if err := h.packService.Delete(c.MustGet("account").(*models.Account), c.Param("id")); err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.Status(http.StatusNoContent)
+}
+
+// UseInRoomRequest picks a room's pack; null goes back to the shared bank
+type UseInRoomRequest struct {
+    PackID *string `json:"pack_id"`
+}
+
+// UseInRoom sets the pack a room draws its questions from
+func (h *PackHandler) UseInRoom(c *gin.Context) {
+    var req UseInRoomRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+        return
+    }
+
+    packID := ""
+    if req.PackID != nil {
+        packID = *req.PackID
+    }
+
+    account := c.MustGet("account").(*models.Account)
+    room, err := h.packService.UseInRoom(account, c.Param("code"), packID)
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "room_code": room.Code,
+        "settings":  service.SettingsOf(room),
+    })
+}
+
+func (h *PackHandler) RegisterRoutes(r *gin.Engine) {
+    packs := r.Group("/api/packs", h.RequireAccount)
+    {
+        packs.GET("", h.ListPacks)
+        packs.POST("", h.CreatePack)
+        packs.GET("/:id", h.GetPack)
+        packs.PUT("/:id", h.ReplacePack)
+        packs.DELETE("/:id", h.DeletePack)
+    }
+    r.PUT("/api/rooms/:code/pack", h.RequireAccount, h.UseInRoom)
+}
@@ -0,0 +1,55 @@
+// internal/handlers/rate_limit.go
+
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+	"github.com/rohan03122001/quizzing/internal/ratelimit"
+)
+
+// Routes that never count against the request limit, so probes and
+// scrapes keep working under load. Messages posted to an event stream
+// have the connection's own message limits instead.
+var unlimitedRoutes = map[string]bool{
+    "GET /healthz":                  true,
+    "GET /readyz":                   true,
+    "GET /metrics":                  true,
+    "POST /sse/:client_id/messages": true,
+}
+
+// RateLimit limits each client IP's requests to the given routes, written
+// "METHOD /path" as they're registered. With no routes it limits every
+// request except health probes, metrics scrapes and event stream
+// messages. Limited requests get a 429 with a Retry-After header.
+func RateLimit(limiter *ratelimit.Keyed, routes ...string) gin.HandlerFunc {
+    only := make(map[string]bool, len(routes))
+    for _, route := range routes {
+        only[route] = true
+    }
+
+    return func(c *gin.Context) {
+        route := c.Request.Method + " " + c.FullPath()
+        if len(only) > 0 && !only[route] || unlimitedRoutes[route] {
+            c.Next()
+            return
+        }
+
+        ok, retryAfter := limiter.Allow(c.ClientIP())
+        if ok {
+            c.Next()
+            return
+        }
+
+        c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+        c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+            "error":       "too many requests, slow down",
+            "code":        protocol.CodeRateLimited,
+            "retry_after": retryAfter.Milliseconds(),
+        })
+    }
+}
@@ -0,0 +1,143 @@
+// internal/handlers/rate_limit_test.go
+
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+	"github.com/rohan03122001/quizzing/internal/ratelimit"
+	ws "github.com/rohan03122001/quizzing/internal/websocket"
+)
+
+func TestRateLimitedRequests(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    router := gin.New()
+    router.Use(
+        RateLimit(ratelimit.NewKeyed(ratelimit.Rate{PerSecond: 0.01, Burst: 3})),
+        RateLimit(ratelimit.NewKeyed(ratelimit.Rate{PerSecond: 0.01, Burst: 1}), "POST /api/rooms"),
+    )
+    ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+    router.GET("/api/rooms", ok)
+    router.POST("/api/rooms", ok)
+    router.GET("/healthz", ok)
+    router.POST("/sse/:client_id/messages", ok)
+
+    do := func(method, path string) *httptest.ResponseRecorder {
+        rec := httptest.NewRecorder()
+        router.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+        return rec
+    }
+
+    if rec := do(http.MethodPost, "/api/rooms"); rec.Code != http.StatusOK {
+        t.Fatalf("first room: got %d, want 200", rec.Code)
+    }
+    rec := do(http.MethodPost, "/api/rooms")
+    if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "100" {
+        t.Fatalf("second room: got %d with Retry-After %q, want 429 after 100s", rec.Code, rec.Header().Get("Retry-After"))
+    }
+
+    // Both requests counted against the overall limit, which the next one uses up
+    if rec := do(http.MethodGet, "/api/rooms"); rec.Code != http.StatusOK {
+        t.Fatalf("listing rooms: got %d, want 200", rec.Code)
+    }
+    if rec := do(http.MethodGet, "/api/rooms"); rec.Code != http.StatusTooManyRequests {
+        t.Errorf("over the overall limit: got %d, want 429", rec.Code)
+    }
+    if rec := do(http.MethodGet, "/healthz"); rec.Code != http.StatusOK {
+        t.Errorf("health probe: got %d, want it never limited", rec.Code)
+    }
+    if rec := do(http.MethodPost, "/sse/c1/messages"); rec.Code != http.StatusOK {
+        t.Errorf("event stream message: got %d, want it left to the connection's limits", rec.Code)
+    }
+}
+
+func TestSpoofedForwardedForIsIgnored(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    router := gin.New()
+    if err := router.SetTrustedProxies(nil); err != nil {
+        t.Fatal(err)
+    }
+    router.Use(RateLimit(ratelimit.NewKeyed(ratelimit.Rate{PerSecond: 0.01, Burst: 1}), "POST /api/rooms"))
+    router.POST("/api/rooms", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+    // Every request claims to come from somewhere new
+    for i, forwarded := range []string{"203.0.113.1", "203.0.113.2", "203.0.113.3"} {
+        req := httptest.NewRequest(http.MethodPost, "/api/rooms", nil)
+        req.RemoteAddr = "198.51.100.7:40000"
+        req.Header.Set("X-Forwarded-For", forwarded)
+        rec := httptest.NewRecorder()
+        router.ServeHTTP(rec, req)
+
+        want := http.StatusTooManyRequests
+        if i == 0 {
+            want = http.StatusCreated
+        }
+        if rec.Code != want {
+            t.Errorf("request %d as %s: got %d, want %d", i+1, forwarded, rec.Code, want)
+        }
+    }
+}
+
+func TestFloodingClientIsDropped(t *testing.T) {
+    hub := ws.NewHub()
+    go hub.Run()
+
+    handler := NewGameHandler(nil, nil, nil, hub)
+    handler.SetRateLimits(ratelimit.Limits{
+        Messages:   ratelimit.Rate{PerSecond: 0.01, Burst: 100},
+        Events:     map[string]ratelimit.Rate{protocol.EventPing: {PerSecond: 0.01, Burst: 2}},
+        MaxStrikes: 2,
+    })
+    client := ws.NewEventStreamClient(hub, "flooder")
+    handler.limit(client)
+    hub.Register <- client
+
+    next := func() *ws.GameEvent {
+        t.Helper()
+        select {
+        case event, ok := <-client.Events():
+            if !ok {
+                t.Fatal("client dropped too early")
+            }
+            return event
+        case <-time.After(time.Second):
+            t.Fatal("no reply")
+        }
+        return nil
+    }
+
+    for i := 0; i < 2; i++ {
+        handler.HandleMessage(client, []byte(`{"type":"ping"}`))
+        if event := next(); event.Type != protocol.EventPong {
+            t.Fatalf("ping %d: got %s, want pong", i+1, event.Type)
+        }
+    }
+
+    // Over the limit, with strikes left
+    for i := 0; i < 2; i++ {
+        handler.HandleMessage(client, []byte(`{"type":"ping","request_id":"r"}`))
+        event := next()
+        data, _ := event.Data.(protocol.ErrorData)
+        if event.Type != protocol.EventError || data.Code != protocol.CodeRateLimited || data.RetryAfter <= 0 || event.RequestID != "r" {
+            t.Fatalf("got %s %+v, want a RATE_LIMITED error with a retry", event.Type, event.Data)
+        }
+    }
+
+    // Out of strikes
+    handler.HandleMessage(client, []byte(`{"type":"ping"}`))
+    select {
+    case _, ok := <-client.Events():
+        if ok {
+            t.Error("the client got a reply instead of being dropped")
+        }
+    case <-time.After(time.Second):
+        t.Error("the client wasn't dropped")
+    }
+}
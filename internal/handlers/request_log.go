@@ -0,0 +1,36 @@
+// internal/handlers/request_log.go
+
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestLogger logs each HTTP request once it's served. Server errors are
+// logged as errors and everything else at info level.
+func RequestLogger() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        start := time.Now()
+        c.Next()
+
+        level := slog.LevelInfo
+        if c.Writer.Status() >= http.StatusInternalServerError {
+            level = slog.LevelError
+        }
+        attrs := []slog.Attr{
+            slog.String("method", c.Request.Method),
+            slog.String("path", c.Request.URL.Path),
+            slog.Int("status", c.Writer.Status()),
+            slog.Duration("elapsed", time.Since(start)),
+            slog.String("client_ip", c.ClientIP()),
+        }
+        if len(c.Errors) > 0 {
+            attrs = append(attrs, slog.String("err", c.Errors.String()))
+        }
+        slog.LogAttrs(c.Request.Context(), level, "Request", attrs...)
+    }
+}
@@ -0,0 +1,242 @@
+// internal/handlers/sse_handler.go
+
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+	"github.com/rohan03122001/quizzing/internal/service"
+	ws "github.com/rohan03122001/quizzing/internal/websocket"
+)
+
+const (
+    // Comment lines sent on idle streams so proxies don't time them out
+    sseKeepAlivePeriod = 25 * time.Second
+
+    // Maximum size of a POSTed message, same as the WebSocket read limit
+    sseMaxMessageSize = 512
+)
+
+// SSEHandler serves clients that can't use WebSockets. Server events are
+// streamed with Server-Sent Events and client messages are POSTed. Both go
+// through the same hub and GameHandler as WebSocket clients.
+type SSEHandler struct {
+    hub            *ws.Hub
+    gameHandler    *GameHandler
+    accountService *service.AccountService
+
+    mu       sync.RWMutex
+    sessions map[string]*sseSession // client ID -> open stream
+}
+
+type sseSession struct {
+    client *ws.Client
+    token  string
+
+    // Handles one message at a time, as a WebSocket's read pump does, and
+    // none once the stream has ended
+    mu     sync.Mutex
+    closed bool
+}
+
+func NewSSEHandler(hub *ws.Hub, gameHandler *GameHandler, accountService *service.AccountService) *SSEHandler {
+    return &SSEHandler{
+        hub:            hub,
+        gameHandler:    gameHandler,
+        accountService: accountService,
+        sessions:       make(map[string]*sseSession),
+    }
+}
+
+// HandleStream opens an event stream for a new client
+func (h *SSEHandler) HandleStream(c *gin.Context) {
+    version, err := protocol.Negotiate(c.Query("v"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":              err.Error(),
+            "supported_versions": protocol.SupportedVersions,
+        })
+        return
+    }
+
+    account, ok := authenticateConnection(c, h.accountService)
+    if !ok {
+        return
+    }
+
+    token, err := newSessionToken()
+    if err != nil {
+        slog.Error("Failed to create SSE session token", "err", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open stream"})
+        return
+    }
+
+    clientID := uuid.New().String()
+    if account != nil {
+        clientID = account.ID.String()
+    }
+
+    client := ws.NewEventStreamClient(h.hub, clientID)
+    client.ProtocolVersion = version
+    signIn(client, account)
+    h.gameHandler.limit(client)
+
+    session := &sseSession{client: client, token: token}
+    h.mu.Lock()
+    h.sessions[clientID] = session
+    h.mu.Unlock()
+
+    defer func() {
+        // Messages still being posted are refused from here on
+        session.mu.Lock()
+        session.closed = true
+        session.mu.Unlock()
+
+        // A newer stream for the same account may have replaced this one
+        h.mu.Lock()
+        if h.sessions[clientID] == session {
+            delete(h.sessions, clientID)
+        }
+        h.mu.Unlock()
+
+        client.Disconnect()
+        client.Log().Info("SSE client disconnected")
+    }()
+
+    h.hub.Register <- client
+
+    c.Writer.Header().Set("Content-Type", "text/event-stream")
+    c.Writer.Header().Set("Cache-Control", "no-cache")
+    c.Writer.Header().Set("Connection", "keep-alive")
+    c.Writer.Header().Set("X-Accel-Buffering", "no") // Disable nginx buffering
+    c.Status(http.StatusOK)
+
+    h.hub.SendToClient(client, ws.GameEvent{
+        Type: protocol.EventConnected,
+        Data: protocol.ConnectedData{
+            ClientID:          clientID,
+            ProtocolVersion:   version,
+            SupportedVersions: protocol.SupportedVersions,
+            SessionToken:      token,
+            AccountID:         client.AccountID,
+            Username:          client.Username,
+        },
+    })
+
+    client.Log().Info("SSE stream established", "protocol_version", version)
+
+    keepAlive := time.NewTicker(sseKeepAlivePeriod)
+    defer keepAlive.Stop()
+
+    for {
+        select {
+        case event, ok := <-client.Events():
+            if !ok {
+                // The hub dropped the client
+                return
+            }
+            if err := writeSSEEvent(c.Writer, event); err != nil {
+                client.Log().Debug("Error writing SSE event", "event", event.Type, "err", err)
+                return
+            }
+
+        case <-keepAlive.C:
+            if _, err := io.WriteString(c.Writer, ": keep-alive\n\n"); err != nil {
+                return
+            }
+            c.Writer.Flush()
+
+        case <-c.Request.Context().Done():
+            return
+        }
+    }
+}
+
+// HandleMessage accepts a client message for an open stream. The body is
+// the same envelope a WebSocket client would send; the reply (ack, error or
+// anything else) arrives on the stream.
+func (h *SSEHandler) HandleMessage(c *gin.Context) {
+    h.mu.RLock()
+    session, exists := h.sessions[c.Param("client_id")]
+    h.mu.RUnlock()
+
+    if !exists {
+        c.JSON(http.StatusNotFound, gin.H{"error": "stream not found"})
+        return
+    }
+
+    if !validSessionToken(c, session.token) {
+        c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid session token"})
+        return
+    }
+
+    body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, sseMaxMessageSize))
+    if err != nil {
+        c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "message too large"})
+        return
+    }
+
+    session.mu.Lock()
+    if session.closed {
+        session.mu.Unlock()
+        c.JSON(http.StatusNotFound, gin.H{"error": "stream not found"})
+        return
+    }
+    err = h.gameHandler.HandleMessage(session.client, body)
+    session.mu.Unlock()
+    if err != nil {
+        session.client.Log().Error("Error handling message", "err", err)
+    }
+
+    c.Status(http.StatusAccepted)
+}
+
+// RegisterRoutes registers the SSE endpoints
+func (h *SSEHandler) RegisterRoutes(r *gin.Engine) {
+    r.GET("/sse", h.HandleStream)
+    r.POST("/sse/:client_id/messages", h.HandleMessage)
+}
+
+func writeSSEEvent(w gin.ResponseWriter, event *ws.GameEvent) error {
+    data, err := json.Marshal(event)
+    if err != nil {
+        return err
+    }
+
+    if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+        return err
+    }
+    w.Flush()
+    return nil
+}
+
+// validSessionToken checks the token from the Authorization header or the
+// token query parameter
+func validSessionToken(c *gin.Context, want string) bool {
+    got := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+    if got == "" {
+        got = c.Query("token")
+    }
+    return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
+func newSessionToken() (string, error) {
+    b := make([]byte, 16)
+    if _, err := rand.Read(b); err != nil {
+        return "", err
+    }
+    return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,214 @@
+// internal/handlers/sse_handler_test.go
+
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+	ws "github.com/rohan03122001/quizzing/internal/websocket"
+)
+
+func TestSSEStreamAndPostedMessages(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    hub := ws.NewHub()
+    go hub.Run()
+
+    router := gin.New()
+    NewSSEHandler(hub, NewGameHandler(nil, nil, nil, hub), nil).RegisterRoutes(router)
+    server := httptest.NewServer(router)
+    defer server.Close()
+
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+
+    req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/sse?v=1", nil)
+    resp, err := http.DefaultClient.Do(req)
+    if err != nil {
+        t.Fatalf("opening stream: %v", err)
+    }
+    defer resp.Body.Close()
+
+    if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+        t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+    }
+
+    events := bufio.NewReader(resp.Body)
+
+    var connected struct {
+        Type string                 `json:"type"`
+        Data protocol.ConnectedData `json:"data"`
+    }
+    readSSEEvent(t, events, &connected)
+    if connected.Type != protocol.EventConnected || connected.Data.SessionToken == "" {
+        t.Fatalf("first event = %+v, want connected with a session token", connected)
+    }
+
+    messagesURL := server.URL + "/sse/" + connected.Data.ClientID + "/messages"
+    body := `{"type":"no_such_event","request_id":"r1"}`
+
+    // Without the session token the message is refused
+    post, _ := http.Post(messagesURL, "application/json", strings.NewReader(body))
+    post.Body.Close()
+    if post.StatusCode != http.StatusUnauthorized {
+        t.Fatalf("POST without token = %d, want 401", post.StatusCode)
+    }
+
+    req, _ = http.NewRequest(http.MethodPost, messagesURL, strings.NewReader(body))
+    req.Header.Set("Authorization", "Bearer "+connected.Data.SessionToken)
+    post, err = http.DefaultClient.Do(req)
+    if err != nil {
+        t.Fatalf("posting message: %v", err)
+    }
+    post.Body.Close()
+    if post.StatusCode != http.StatusAccepted {
+        t.Fatalf("POST = %d, want 202", post.StatusCode)
+    }
+
+    // The reply arrives on the stream, tagged with the request ID
+    var reply struct {
+        Type      string             `json:"type"`
+        RequestID string             `json:"request_id"`
+        Data      protocol.ErrorData `json:"data"`
+    }
+    readSSEEvent(t, events, &reply)
+    if reply.Type != protocol.EventError || reply.RequestID != "r1" || reply.Data.Code != protocol.CodeUnknownEvent {
+        t.Fatalf("reply = %+v, want UNKNOWN_EVENT error for r1", reply)
+    }
+}
+
+func readSSEEvent(t *testing.T, r *bufio.Reader, out interface{}) {
+    t.Helper()
+    for {
+        line, err := r.ReadString('\n')
+        if err != nil {
+            t.Fatalf("reading stream: %v", err)
+        }
+        if data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: "); ok {
+            if err := json.Unmarshal([]byte(data), out); err != nil {
+                t.Fatalf("decoding event %s: %v", data, err)
+            }
+            return
+        }
+    }
+}
+
+func TestSSEStreamsOfOneAccount(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    accounts := openTestAccounts(t)
+    session, err := accounts.Register("alice", "correct horse", "")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    hub := ws.NewHub()
+    go hub.Run()
+
+    router := gin.New()
+    NewSSEHandler(hub, NewGameHandler(nil, nil, accounts, hub), accounts).RegisterRoutes(router)
+    server := httptest.NewServer(router)
+    defer server.Close()
+
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+
+    open := func(ctx context.Context) protocol.ConnectedData {
+        t.Helper()
+        req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/sse?v=1&access_token="+session.Token, nil)
+        resp, err := http.DefaultClient.Do(req)
+        if err != nil {
+            t.Fatalf("opening stream: %v", err)
+        }
+        go func() {
+            <-ctx.Done()
+            resp.Body.Close()
+        }()
+
+        var connected struct {
+            Data protocol.ConnectedData `json:"data"`
+        }
+        readSSEEvent(t, bufio.NewReader(resp.Body), &connected)
+        return connected.Data
+    }
+
+    firstCtx, closeFirst := context.WithCancel(ctx)
+    first := open(firstCtx)
+    second := open(ctx)
+    if first.ClientID != second.ClientID {
+        t.Fatalf("streams got client IDs %s and %s, want the account's", first.ClientID, second.ClientID)
+    }
+
+    // Closing the old stream leaves the new one's session alone
+    closeFirst()
+    time.Sleep(100 * time.Millisecond)
+
+    req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/sse/"+second.ClientID+"/messages",
+        strings.NewReader(`{"type":"ping"}`))
+    req.Header.Set("Authorization", "Bearer "+second.SessionToken)
+    post, err := http.DefaultClient.Do(req)
+    if err != nil {
+        t.Fatal(err)
+    }
+    post.Body.Close()
+    if post.StatusCode != http.StatusAccepted {
+        t.Errorf("POST to the newer stream = %d, want 202", post.StatusCode)
+    }
+}
+
+func TestSSEMessageAfterHubDropsClient(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    hub := ws.NewHub()
+    go hub.Run()
+
+    router := gin.New()
+    NewSSEHandler(hub, NewGameHandler(nil, nil, nil, hub), nil).RegisterRoutes(router)
+    server := httptest.NewServer(router)
+    defer server.Close()
+
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+
+    req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/sse?v=1", nil)
+    resp, err := http.DefaultClient.Do(req)
+    if err != nil {
+        t.Fatalf("opening stream: %v", err)
+    }
+    defer resp.Body.Close()
+
+    events := bufio.NewReader(resp.Body)
+    var connected struct {
+        Data protocol.ConnectedData `json:"data"`
+    }
+    readSSEEvent(t, events, &connected)
+
+    // The hub drops the client, as it does one that floods or falls behind,
+    // and the stream ends
+    hub.DisconnectAll()
+    if _, err := io.Copy(io.Discard, events); err != nil {
+        t.Fatalf("reading the rest of the stream: %v", err)
+    }
+
+    req, _ = http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/sse/"+connected.Data.ClientID+"/messages",
+        strings.NewReader(`{"type":"ping","request_id":"r1"}`))
+    req.Header.Set("Authorization", "Bearer "+connected.Data.SessionToken)
+    post, err := http.DefaultClient.Do(req)
+    if err != nil {
+        t.Fatalf("posting after the stream ended: %v", err)
+    }
+    post.Body.Close()
+    if post.StatusCode != http.StatusNotFound {
+        t.Errorf("POST after the stream ended = %d, want 404", post.StatusCode)
+    }
+}
@@ -0,0 +1,62 @@
+// internal/handlers/tracing.go
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rohan03122001/quizzing/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Routes that stay open for a whole session. Their messages are traced one
+// by one instead.
+var untracedRoutes = map[string]bool{
+    "/ws":  true,
+    "/sse": true,
+}
+
+// Tracing starts a span for each HTTP request, continuing the caller's
+// trace if the request carries one
+func Tracing() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        route := c.FullPath()
+        if untracedRoutes[route] {
+            c.Next()
+            return
+        }
+        if route == "" {
+            route = "unmatched"
+        }
+
+        ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+        ctx, span := tracing.Tracer().Start(ctx, c.Request.Method+" "+route,
+            trace.WithSpanKind(trace.SpanKindServer),
+            trace.WithAttributes(
+                attribute.String("http.request.method", c.Request.Method),
+                attribute.String("http.route", route),
+                attribute.String("url.path", c.Request.URL.Path),
+                attribute.String("client.address", c.ClientIP()),
+            ),
+        )
+        defer span.End()
+
+        c.Request = c.Request.WithContext(ctx)
+        c.Next()
+
+        status := c.Writer.Status()
+        span.SetAttributes(attribute.Int("http.response.status_code", status))
+        if status >= http.StatusInternalServerError {
+            span.SetStatus(codes.Error, fmt.Sprintf("status %d", status))
+        }
+        if len(c.Errors) > 0 {
+            span.RecordError(c.Errors.Last())
+        }
+    }
+}
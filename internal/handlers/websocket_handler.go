@@ -3,50 +3,79 @@
 package handlers
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+	"github.com/rohan03122001/quizzing/internal/service"
 	ws "github.com/rohan03122001/quizzing/internal/websocket"
 )
 
-var upgrader = websocket.Upgrader{
-    ReadBufferSize:  1024,
-    WriteBufferSize: 1024,
-    CheckOrigin: func(r *http.Request) bool {
-        // TODO: In production, implement proper origin check
-        return true
-    },
-}
-
 type WebSocketHandler struct {
-    hub         *ws.Hub
-    gameHandler *GameHandler
+    hub            *ws.Hub
+    gameHandler    *GameHandler
+    accountService *service.AccountService
+    upgrader       websocket.Upgrader
 }
 
-func NewWebSocketHandler(hub *ws.Hub, gameHandler *GameHandler) *WebSocketHandler {
+// NewWebSocketHandler accepts connections from pages on the allowed origins
+func NewWebSocketHandler(hub *ws.Hub, gameHandler *GameHandler, accountService *service.AccountService, origins Origins) *WebSocketHandler {
     return &WebSocketHandler{
-        hub:         hub,
-        gameHandler: gameHandler,
+        hub:            hub,
+        gameHandler:    gameHandler,
+        accountService: accountService,
+        upgrader: websocket.Upgrader{
+            ReadBufferSize:  1024,
+            WriteBufferSize: 1024,
+            Subprotocols:    ws.Subprotocols(),
+            CheckOrigin: func(r *http.Request) bool {
+                return origins.Allows(r.Header.Get("Origin"))
+            },
+        },
     }
 }
 
 // HandleConnection handles new WebSocket connections
 func (h *WebSocketHandler) HandleConnection(c *gin.Context) {
+    // Negotiate protocol version before upgrading so a mismatch gets a plain HTTP error
+    version, err := protocol.Negotiate(c.Query("v"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":              err.Error(),
+            "supported_versions": protocol.SupportedVersions,
+        })
+        return
+    }
+
+    // Signed-in players are identified by their account, guests get a new ID
+    account, ok := authenticateConnection(c, h.accountService)
+    if !ok {
+        return
+    }
+
     // Upgrade HTTP connection to WebSocket
-    conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+    conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
     if err != nil {
-        log.Printf("Failed to upgrade connection: %v", err)
+        slog.Warn("Failed to upgrade connection", "err", err)
         return
     }
 
     // Generate unique client ID
     clientID := uuid.New().String()
+    if account != nil {
+        clientID = account.ID.String()
+    }
 
     // Create new client (initially without room)
     client := ws.NewClient(h.hub, conn, "", clientID)
+    client.ProtocolVersion = version
+    client.SetEncoding(ws.EncodingFor(conn.Subprotocol()))
+    signIn(client, account)
+    h.gameHandler.limit(client)
 
     // Set message handler
     client.SetMessageHandler(h.gameHandler.HandleMessage)
@@ -58,7 +87,46 @@ func (h *WebSocketHandler) HandleConnection(c *gin.Context) {
     go client.ReadPump()
     go client.WritePump()
 
-    log.Printf("New WebSocket connection established: Client %s", clientID)
+    // Tell the client who it is and which protocol version is in use
+    h.hub.SendToClient(client, ws.GameEvent{
+        Type: protocol.EventConnected,
+        Data: protocol.ConnectedData{
+            ClientID:          clientID,
+            ProtocolVersion:   version,
+            SupportedVersions: protocol.SupportedVersions,
+            AccountID:         client.AccountID,
+            Username:          client.Username,
+        },
+    })
+
+    client.Log().Info("WebSocket connection established",
+        "protocol_version", version, "encoding", ws.EncodingFor(conn.Subprotocol()).Name())
+}
+
+// authenticateConnection checks the access token of a connecting client, if
+// it sent one. A nil account means a guest; on failure the error response
+// has been written.
+func authenticateConnection(c *gin.Context, accountService *service.AccountService) (*models.Account, bool) {
+    token := accessToken(c)
+    if token == "" {
+        return nil, true
+    }
+
+    account, err := accountService.Authenticate(token)
+    if err != nil {
+        respondError(c, err)
+        return nil, false
+    }
+    return account, true
+}
+
+// signIn ties a client to its account
+func signIn(client *ws.Client, account *models.Account) {
+    if account == nil {
+        return
+    }
+    client.AccountID = account.ID.String()
+    client.Username = account.Username
 }
 
 // RegisterRoutes registers the WebSocket endpoint
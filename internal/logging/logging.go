@@ -0,0 +1,57 @@
+// internal/logging/logging.go
+
+// Package logging sets up the server's structured logs. Lines about a game
+// use the same keys everywhere: "room" for the room code, "player" for the
+// player ID and "event" for the protocol event type, so one room or player
+// can be followed through the logs.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Log formats
+const (
+    FormatText = "text"
+    FormatJSON = "json"
+)
+
+// ParseLevel reads a level name: debug, info, warn or error
+func ParseLevel(name string) (slog.Level, error) {
+    var level slog.Level
+    if err := level.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+        return 0, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", name)
+    }
+    return level, nil
+}
+
+// New returns a logger writing lines of the given format to w
+func New(w io.Writer, level slog.Level, format string) (*slog.Logger, error) {
+    options := &slog.HandlerOptions{Level: level}
+    switch format {
+    case FormatText:
+        return slog.New(slog.NewTextHandler(w, options)), nil
+    case FormatJSON:
+        return slog.New(slog.NewJSONHandler(w, options)), nil
+    default:
+        return nil, fmt.Errorf("unknown log format %q, expected text or json", format)
+    }
+}
+
+// Setup makes a logger the default for slog and for the standard log
+// package, which some libraries still use
+func Setup(w io.Writer, levelName string, format string) error {
+    level, err := ParseLevel(levelName)
+    if err != nil {
+        return err
+    }
+    logger, err := New(w, level, format)
+    if err != nil {
+        return err
+    }
+    slog.SetDefault(logger)
+    return nil
+}
@@ -0,0 +1,51 @@
+// internal/logging/logging_test.go
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+    for name, want := range map[string]slog.Level{
+        "debug": slog.LevelDebug,
+        "info":  slog.LevelInfo,
+        "WARN":  slog.LevelWarn,
+        "error": slog.LevelError,
+    } {
+        if got, err := ParseLevel(name); err != nil || got != want {
+            t.Errorf("ParseLevel(%q) = %v, %v, want %v", name, got, err, want)
+        }
+    }
+    if _, err := ParseLevel("loud"); err == nil {
+        t.Error("expected an error for an unknown level")
+    }
+}
+
+func TestJSONLogsCarryFields(t *testing.T) {
+    var buf bytes.Buffer
+    logger, err := New(&buf, slog.LevelInfo, FormatJSON)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    logger.Debug("Compared answer", "room", "ABC123")
+    logger.With("room", "ABC123", "player", "p1").Info("Received message", "event", "submit_answer")
+
+    var line map[string]interface{}
+    if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+        t.Fatalf("expected one JSON line, got %q: %v", buf.String(), err)
+    }
+    for key, want := range map[string]string{"level": "INFO", "room": "ABC123", "player": "p1", "event": "submit_answer"} {
+        if line[key] != want {
+            t.Errorf("got %s %v, want %s", key, line[key], want)
+        }
+    }
+
+    if _, err := New(&buf, slog.LevelInfo, "xml"); err == nil {
+        t.Error("expected an error for an unknown format")
+    }
+}
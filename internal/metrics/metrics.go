@@ -0,0 +1,175 @@
+// internal/metrics/metrics.go
+
+package metrics
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics holds the server's Prometheus collectors. Components are handed
+// one with their SetMetrics method; a nil *Metrics records nothing, so
+// tests and tools can leave them unset.
+type Metrics struct {
+    clients          prometheus.Gauge
+    broadcasts       *prometheus.CounterVec
+    dropped          *prometheus.CounterVec
+    answerLatency    *prometheus.HistogramVec
+    roundDuration    prometheus.Histogram
+    queryDuration    *prometheus.HistogramVec
+    cleanupDeletions *prometheus.CounterVec
+
+    registerer prometheus.Registerer
+}
+
+// New creates the collectors and registers them with reg
+func New(reg prometheus.Registerer) *Metrics {
+    m := &Metrics{
+        clients: prometheus.NewGauge(prometheus.GaugeOpts{
+            Name: "quiz_connected_clients",
+            Help: "Clients connected to the hub.",
+        }),
+        broadcasts: prometheus.NewCounterVec(prometheus.CounterOpts{
+            Name: "quiz_broadcasts_total",
+            Help: "Events broadcast to rooms, by event type.",
+        }, []string{"type"}),
+        dropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+            Name: "quiz_dropped_messages_total",
+            Help: "Broadcast events dropped because a client's buffer was full, by event type.",
+        }, []string{"type"}),
+        answerLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+            Name:    "quiz_answer_latency_seconds",
+            Help:    "Time from the start of a round to each answer, by whether it was correct.",
+            Buckets: []float64{1, 2, 3, 5, 7.5, 10, 15, 20, 30, 45, 60},
+        }, []string{"result"}),
+        roundDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+            Name:    "quiz_round_duration_seconds",
+            Help:    "Time from the start of a round to its end.",
+            Buckets: []float64{5, 10, 15, 20, 30, 45, 60, 90, 120},
+        }),
+        queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+            Name:    "quiz_db_query_duration_seconds",
+            Help:    "Database query latency, by operation and table.",
+            Buckets: prometheus.ExponentialBuckets(0.0005, 2, 14),
+        }, []string{"operation", "table"}),
+        cleanupDeletions: prometheus.NewCounterVec(prometheus.CounterOpts{
+            Name: "quiz_cleanup_rooms_total",
+            Help: "Inactive rooms cleaned up, by action: deleted or abandoned.",
+        }, []string{"action"}),
+        registerer: reg,
+    }
+
+    reg.MustRegister(
+        m.clients,
+        m.broadcasts,
+        m.dropped,
+        m.answerLatency,
+        m.roundDuration,
+        m.queryDuration,
+        m.cleanupDeletions,
+    )
+    return m
+}
+
+// WatchRooms reports the number of rooms in each status, counted with
+// count on every scrape
+func (m *Metrics) WatchRooms(count func() (map[string]int64, error)) {
+    m.registerer.MustRegister(&roomCollector{count: count})
+}
+
+// ClientConnected counts a client joining the hub
+func (m *Metrics) ClientConnected() {
+    if m != nil {
+        m.clients.Inc()
+    }
+}
+
+// ClientDisconnected counts a client leaving the hub
+func (m *Metrics) ClientDisconnected() {
+    if m != nil {
+        m.clients.Dec()
+    }
+}
+
+// Broadcast counts an event sent to a room
+func (m *Metrics) Broadcast(eventType string) {
+    if m != nil {
+        m.broadcasts.WithLabelValues(eventType).Inc()
+    }
+}
+
+// MessageDropped counts an event a client never got
+func (m *Metrics) MessageDropped(eventType string) {
+    if m != nil {
+        m.dropped.WithLabelValues(eventType).Inc()
+    }
+}
+
+// Answer records how long into the round an answer came
+func (m *Metrics) Answer(correct bool, latency time.Duration) {
+    if m == nil {
+        return
+    }
+    result := "incorrect"
+    if correct {
+        result = "correct"
+    }
+    m.answerLatency.WithLabelValues(result).Observe(latency.Seconds())
+}
+
+// RoundEnded records how long a round lasted
+func (m *Metrics) RoundEnded(duration time.Duration) {
+    if m != nil {
+        m.roundDuration.Observe(duration.Seconds())
+    }
+}
+
+// Query records how long a database operation took
+func (m *Metrics) Query(operation string, table string, duration time.Duration) {
+    if m != nil {
+        m.queryDuration.WithLabelValues(operation, table).Observe(duration.Seconds())
+    }
+}
+
+// RoomCleanedUp counts a room removed or abandoned by the cleanup routine
+func (m *Metrics) RoomCleanedUp(action string) {
+    if m != nil {
+        m.cleanupDeletions.WithLabelValues(action).Inc()
+    }
+}
+
+var roomsDesc = prometheus.NewDesc(
+    "quiz_rooms",
+    "Rooms in the database, by status.",
+    []string{"status"}, nil,
+)
+
+// roomCollector asks the database for room counts when scraped, so the
+// numbers can't drift from the stored state
+type roomCollector struct {
+    count func() (map[string]int64, error)
+}
+
+func (c *roomCollector) Describe(ch chan<- *prometheus.Desc) {
+    ch <- roomsDesc
+}
+
+func (c *roomCollector) Collect(ch chan<- prometheus.Metric) {
+    counts, err := c.count()
+    if err != nil {
+        slog.Error("Error counting rooms for metrics", "err", err)
+        ch <- prometheus.NewInvalidMetric(roomsDesc, err)
+        return
+    }
+    // Live rooms are always reported, so dashboards see zeros rather than gaps
+    for _, status := range []string{"waiting", "playing"} {
+        if _, ok := counts[status]; !ok {
+            ch <- prometheus.MustNewConstMetric(roomsDesc, prometheus.GaugeValue, 0, status)
+        }
+    }
+    for status, n := range counts {
+        ch <- prometheus.MustNewConstMetric(roomsDesc, prometheus.GaugeValue, float64(n), status)
+    }
+}
@@ -0,0 +1,109 @@
+// internal/metrics/metrics_test.go
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// gather returns the metrics of a family by their label values joined
+// with commas
+func gather(t *testing.T, reg *prometheus.Registry, name string) map[string]*dto.Metric {
+    t.Helper()
+    families, err := reg.Gather()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    found := map[string]*dto.Metric{}
+    for _, family := range families {
+        if family.GetName() != name {
+            continue
+        }
+        for _, metric := range family.GetMetric() {
+            key := ""
+            for i, label := range metric.GetLabel() {
+                if i > 0 {
+                    key += ","
+                }
+                key += label.GetValue()
+            }
+            found[key] = metric
+        }
+    }
+    return found
+}
+
+func TestMetrics(t *testing.T) {
+    reg := prometheus.NewRegistry()
+    m := New(reg)
+
+    m.ClientConnected()
+    m.ClientConnected()
+    m.ClientDisconnected()
+    m.Broadcast("round_start")
+    m.Broadcast("round_start")
+    m.MessageDropped("round_start")
+    m.Answer(true, 3*time.Second)
+    m.Answer(false, 40*time.Second)
+    m.RoundEnded(30 * time.Second)
+    m.Query("query", "rooms", 2*time.Millisecond)
+    m.RoomCleanedUp("deleted")
+
+    if got := gather(t, reg, "quiz_connected_clients")[""].GetGauge().GetValue(); got != 1 {
+        t.Errorf("got %v connected clients, want 1", got)
+    }
+    if got := gather(t, reg, "quiz_broadcasts_total")["round_start"].GetCounter().GetValue(); got != 2 {
+        t.Errorf("got %v broadcasts, want 2", got)
+    }
+    if got := gather(t, reg, "quiz_dropped_messages_total")["round_start"].GetCounter().GetValue(); got != 1 {
+        t.Errorf("got %v dropped messages, want 1", got)
+    }
+    answers := gather(t, reg, "quiz_answer_latency_seconds")
+    if answers["correct"].GetHistogram().GetSampleSum() != 3 || answers["incorrect"].GetHistogram().GetSampleCount() != 1 {
+        t.Errorf("got answer latencies %v", answers)
+    }
+    if got := gather(t, reg, "quiz_db_query_duration_seconds")["query,rooms"].GetHistogram().GetSampleCount(); got != 1 {
+        t.Errorf("got %d timed queries, want 1", got)
+    }
+    if got := gather(t, reg, "quiz_cleanup_rooms_total")["deleted"].GetCounter().GetValue(); got != 1 {
+        t.Errorf("got %v cleaned up rooms, want 1", got)
+    }
+}
+
+func TestNilMetricsRecordNothing(t *testing.T) {
+    var m *Metrics
+    m.ClientConnected()
+    m.Broadcast("chat")
+    m.Answer(true, time.Second)
+    m.Query("query", "rooms", time.Millisecond)
+    m.RoomCleanedUp("deleted")
+}
+
+func TestWatchRooms(t *testing.T) {
+    reg := prometheus.NewRegistry()
+    m := New(reg)
+
+    counts := map[string]int64{"playing": 2, "finished": 5}
+    var countErr error
+    m.WatchRooms(func() (map[string]int64, error) {
+        return counts, countErr
+    })
+
+    rooms := gather(t, reg, "quiz_rooms")
+    for status, want := range map[string]float64{"waiting": 0, "playing": 2, "finished": 5} {
+        if got := rooms[status].GetGauge().GetValue(); rooms[status] == nil || got != want {
+            t.Errorf("got %v %s rooms, want %v", got, status, want)
+        }
+    }
+
+    countErr = errors.New("database is down")
+    if _, err := reg.Gather(); err == nil {
+        t.Error("a failed count should fail the scrape")
+    }
+}
@@ -3,6 +3,9 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,24 +14,119 @@ import (
 
 // Room represents a game room
 type Room struct {
-    ID           uuid.UUID `gorm:"type:uuid;primary_key"`
-    Code         string    `gorm:"unique;not null"`      // 6-character room code (e.g., "ABC123")
-    Status       string    `gorm:"not null"`            // "waiting", "playing", "finished"
-    MaxPlayers   int       `gorm:"default:10"`          // Maximum players allowed
-    RoundTime    int       `gorm:"default:30"`          // Seconds per round
-    MaxRounds    int       `gorm:"default:2"`           // Number of rounds
-    CurrentRound int       `gorm:"default:0"`           // Current round number
-    CreatedAt    time.Time
-    EndedAt      *time.Time
-    LastActivity time.Time `gorm:"not null"` // Track last activity in room
+    ID               uuid.UUID `gorm:"type:uuid;primary_key"`
+    Code             string    `gorm:"unique;not null"`      // 6-character room code (e.g., "ABC123")
+    Status           string    `gorm:"not null"`            // "waiting", "playing", "finished"
+    MaxPlayers       int       `gorm:"default:10"`          // Maximum players allowed
+    RoundTime        int       `gorm:"default:30"`          // Seconds per round
+    MaxRounds        int       `gorm:"default:2"`           // Number of rounds
+    CurrentRound     int       `gorm:"default:0"`           // Current round number
+    TimerUpdates     bool      `gorm:"default:false"`       // Broadcast timer_update every second
+    IntermissionTime int       `gorm:"default:5"`           // Seconds between rounds
+    ReadyCheck       bool      `gorm:"default:false"`       // Start the next round once everyone is ready
+    DifficultyRamp   bool      `gorm:"default:false"`       // Easy questions first, hard ones in the final rounds
+    PackID           *uuid.UUID `gorm:"type:uuid;index"`    // Host's question pack, nil for the shared bank
+    HostID           string                                  // Player ID of the room host
+    CreatedAt        time.Time
+    EndedAt          *time.Time
+    CheckpointedAt   *time.Time // Set when a shutdown stopped the game between rounds
+    LastActivity     time.Time `gorm:"not null"` // Track last activity in room
+}
+
+// Account is a registered player. Its ID is used as the player ID when
+// the player connects signed in.
+type Account struct {
+    ID           uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+    Username     string     `gorm:"uniqueIndex;not null" json:"username"`
+    Email        *string    `gorm:"uniqueIndex" json:"email,omitempty"`
+    PasswordHash string     `json:"-"` // bcrypt; empty for magic-link only accounts
+    BannedAt     *time.Time `json:"banned_at,omitempty"`
+    BanReason    string     `json:"ban_reason,omitempty"`
+    CreatedAt    time.Time  `json:"created_at"`
+    LastLoginAt  *time.Time `json:"last_login_at,omitempty"`
+    IsAdmin      bool       `gorm:"default:false" json:"is_admin"` // Can manage the question bank
+}
+
+// AuthToken is a session or magic-link token. Only a hash of the token is stored.
+type AuthToken struct {
+    TokenHash string    `gorm:"primary_key"`
+    AccountID uuid.UUID `gorm:"type:uuid;index;not null"`
+    Kind      string    `gorm:"not null"` // "session", "magic_link"
+    ExpiresAt time.Time `gorm:"index;not null"`
+    CreatedAt time.Time
 }
 
 // Question represents a quiz question
 type Question struct {
-    ID         uuid.UUID `gorm:"type:uuid;primary_key"`
-    Content    string    `gorm:"not null"`           // Question text
-    Answer     string    `gorm:"not null"`           // Correct answer
-    CreatedAt  time.Time
+    ID                uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+    Content           string     `gorm:"not null" json:"content"`                   // Question text
+    Answer            string     `gorm:"not null" json:"answer"`                    // Correct answer
+    Aliases           StringList `gorm:"type:text" json:"aliases"`                  // Other accepted answers
+    Category          string     `gorm:"index" json:"category"`                     // e.g. "science", empty if uncategorized
+    Difficulty        int        `gorm:"index;default:0" json:"difficulty"`         // Set by editors: 1 easy, 2 medium, 3 hard, 0 unrated
+    DerivedDifficulty int        `gorm:"index;default:0" json:"derived_difficulty"` // From how often players got it right, 0 until enough answers
+    MediaURL          string     `json:"media_url,omitempty"`                       // Image, audio or video shown with the question
+    MediaType         string     `json:"media_type,omitempty"`                      // "image", "audio", "video"
+    PackID            *uuid.UUID `gorm:"type:uuid;index" json:"pack_id,omitempty"`  // Set for questions of a private pack
+    CreatedAt         time.Time  `json:"created_at"`
+    UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// QuestionPack is a host's private set of questions for their own rooms.
+// Its questions live in the questions table, but are never drawn for rooms
+// that don't use the pack.
+type QuestionPack struct {
+    ID            uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+    OwnerID       uuid.UUID  `gorm:"type:uuid;index;not null" json:"owner_id"` // Account that uploaded it
+    Name          string     `gorm:"not null" json:"name"`
+    Description   string     `json:"description,omitempty"`
+    QuestionCount int        `json:"question_count"`
+    CreatedAt     time.Time  `json:"created_at"`
+    UpdatedAt     time.Time  `json:"updated_at"`
+    Questions     []Question `gorm:"foreignKey:PackID" json:"questions,omitempty"`
+}
+
+// EffectiveDifficulty is the editors' rating if there is one, otherwise
+// the derived one
+func (q *Question) EffectiveDifficulty() int {
+    if q.Difficulty > 0 {
+        return q.Difficulty
+    }
+    return q.DerivedDifficulty
+}
+
+// StringList is a list of strings stored as a JSON array in a text column
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+    if len(l) == 0 {
+        return "[]", nil
+    }
+    data, err := json.Marshal([]string(l))
+    if err != nil {
+        return nil, err
+    }
+    return string(data), nil
+}
+
+func (l *StringList) Scan(value interface{}) error {
+    var data []byte
+    switch v := value.(type) {
+    case nil:
+        *l = nil
+        return nil
+    case string:
+        data = []byte(v)
+    case []byte:
+        data = v
+    default:
+        return fmt.Errorf("cannot scan %T into StringList", value)
+    }
+    if len(data) == 0 {
+        *l = nil
+        return nil
+    }
+    return json.Unmarshal(data, (*[]string)(l))
 }
 
 // GameRound represents a single round in a game
@@ -39,8 +137,9 @@ type GameRound struct {
     StartTime    time.Time
     EndTime      time.Time
     RoundNumber  int     `gorm:"not null"`
-    State        string  `gorm:"not null;default:'waiting'"` // "waiting", "active", "finished"
+    State        string  `gorm:"not null;default:'waiting'"` // "waiting", "active", "finished", "voided"
     AnswerCount  int     `gorm:"default:0"`                 // Number of answers received
+    PausedAt     *time.Time                                // Set while the host has the round paused
 }
 
 // PlayerAnswer represents a player's answer in a round
@@ -54,10 +153,106 @@ type PlayerAnswer struct {
     AnsweredAt  time.Time
 }
 
-// GameSettings represents game settings
+// GameSession is the archived record of a finished game. It outlives the
+// room and the rounds it was played with.
+type GameSession struct {
+    ID          uuid.UUID       `gorm:"type:uuid;primary_key" json:"id"`
+    RoomID      uuid.UUID       `gorm:"type:uuid;index" json:"room_id"`
+    RoomCode    string          `gorm:"index;not null" json:"room_code"`
+    StartedAt   time.Time       `json:"started_at"`
+    EndedAt     time.Time       `gorm:"index" json:"ended_at"`
+    TotalRounds int             `json:"total_rounds"` // Rounds that counted, not including skipped questions
+    PlayerCount int             `json:"player_count"`
+    Players     []SessionPlayer `gorm:"foreignKey:SessionID" json:"players,omitempty"`
+    Rounds      []SessionRound  `gorm:"foreignKey:SessionID" json:"rounds,omitempty"`
+}
+
+// SessionPlayer is a player's final standing in an archived game
+type SessionPlayer struct {
+    ID         uuid.UUID  `gorm:"type:uuid;primary_key" json:"-"`
+    SessionID  uuid.UUID  `gorm:"type:uuid;index;not null" json:"-"`
+    PlayerID   string     `gorm:"index;not null" json:"player_id"`
+    AccountID  *uuid.UUID `gorm:"type:uuid;index" json:"account_id,omitempty"` // Set if the player was signed in
+    Username   string     `json:"username"`
+    TotalScore int        `json:"total_score"`
+    Rank       int        `json:"rank"`
+}
+
+// SessionRound is a question asked in an archived game
+type SessionRound struct {
+    ID          uuid.UUID       `gorm:"type:uuid;primary_key" json:"-"`
+    SessionID   uuid.UUID       `gorm:"type:uuid;index;not null" json:"-"`
+    RoundNumber int             `gorm:"not null" json:"round_number"`
+    QuestionID  uuid.UUID       `gorm:"type:uuid" json:"question_id"`
+    Question    string          `json:"question"` // Copied so the record survives question edits
+    Answer      string          `json:"correct_answer"`
+    Category    string          `json:"category,omitempty"`
+    Voided      bool            `json:"voided"` // Skipped by the host, points didn't count
+    StartTime   time.Time       `json:"start_time"`
+    EndTime     time.Time       `json:"end_time"`
+    Answers     []SessionAnswer `gorm:"foreignKey:RoundID" json:"answers"`
+}
+
+// SessionAnswer is an answer given in an archived round
+type SessionAnswer struct {
+    ID          uuid.UUID `gorm:"type:uuid;primary_key" json:"-"`
+    RoundID     uuid.UUID `gorm:"type:uuid;index;not null" json:"-"`
+    PlayerID    string    `gorm:"index;not null" json:"player_id"`
+    Answer      string    `json:"answer"`
+    Score       int       `json:"score"`
+    AnswerOrder int       `json:"answer_order"`
+    AnsweredAt  time.Time `json:"answered_at"`
+}
+
+// LeaderboardEntry is an account's running total on one leaderboard. Board
+// is "all_time", "weekly:<ISO week>" (e.g. "weekly:2024-W03") or
+// "category:<name>". Entries are added to as games end.
+type LeaderboardEntry struct {
+    Board          string    `gorm:"primaryKey;index:idx_leaderboard_rank,priority:1" json:"-"`
+    AccountID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"account_id"`
+    Username       string    `json:"username"`
+    Score          int       `gorm:"index:idx_leaderboard_rank,priority:2,sort:desc" json:"score"`
+    GamesPlayed    int       `json:"games_played"`
+    Wins           int       `json:"wins"`
+    CorrectAnswers int       `json:"correct_answers"`
+    UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// GameSettings represents game settings. Zero values and nil pointers keep
+// the room's current setting.
 type GameSettings struct {
-    MaxRounds  int `json:"max_rounds"`
-    RoundTime  int `json:"round_time"`
+    MaxPlayers       int   `json:"max_players"`
+    MaxRounds        int   `json:"max_rounds"`
+    RoundTime        int   `json:"round_time"`
+    TimerUpdates     *bool `json:"timer_updates"`
+    IntermissionTime int   `json:"intermission_time"`
+    ReadyCheck       *bool `json:"ready_check"`
+    DifficultyRamp   *bool `json:"difficulty_ramp"`
+}
+
+// Apply copies the set fields onto a room
+func (s *GameSettings) Apply(room *Room) {
+    if s.MaxPlayers > 0 {
+        room.MaxPlayers = s.MaxPlayers
+    }
+    if s.MaxRounds > 0 {
+        room.MaxRounds = s.MaxRounds
+    }
+    if s.RoundTime > 0 {
+        room.RoundTime = s.RoundTime
+    }
+    if s.TimerUpdates != nil {
+        room.TimerUpdates = *s.TimerUpdates
+    }
+    if s.IntermissionTime > 0 {
+        room.IntermissionTime = s.IntermissionTime
+    }
+    if s.ReadyCheck != nil {
+        room.ReadyCheck = *s.ReadyCheck
+    }
+    if s.DifficultyRamp != nil {
+        room.DifficultyRamp = *s.DifficultyRamp
+    }
 }
 
 // BeforeCreate hooks to generate UUIDs
@@ -72,6 +267,20 @@ func (r *Room) BeforeCreate(tx *gorm.DB) error {
     return nil
 }
 
+func (p *QuestionPack) BeforeCreate(tx *gorm.DB) error {
+    if p.ID == uuid.Nil {
+        p.ID = uuid.New()
+    }
+    return nil
+}
+
+func (a *Account) BeforeCreate(tx *gorm.DB) error {
+    if a.ID == uuid.Nil {
+        a.ID = uuid.New()
+    }
+    return nil
+}
+
 func (q *Question) BeforeCreate(tx *gorm.DB) error {
     if q.ID == uuid.Nil {
         q.ID = uuid.New()
@@ -91,4 +300,32 @@ func (pa *PlayerAnswer) BeforeCreate(tx *gorm.DB) error {
         pa.ID = uuid.New()
     }
     return nil
+}
+
+func (gs *GameSession) BeforeCreate(tx *gorm.DB) error {
+    if gs.ID == uuid.Nil {
+        gs.ID = uuid.New()
+    }
+    return nil
+}
+
+func (sp *SessionPlayer) BeforeCreate(tx *gorm.DB) error {
+    if sp.ID == uuid.Nil {
+        sp.ID = uuid.New()
+    }
+    return nil
+}
+
+func (sr *SessionRound) BeforeCreate(tx *gorm.DB) error {
+    if sr.ID == uuid.Nil {
+        sr.ID = uuid.New()
+    }
+    return nil
+}
+
+func (sa *SessionAnswer) BeforeCreate(tx *gorm.DB) error {
+    if sa.ID == uuid.Nil {
+        sa.ID = uuid.New()
+    }
+    return nil
 }
\ No newline at end of file
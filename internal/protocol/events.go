@@ -0,0 +1,299 @@
+// internal/protocol/events.go
+
+package protocol
+
+import "time"
+
+// EventSpec ties an event type to the shape of its data. Data is nil for
+// events that carry no payload.
+type EventSpec struct {
+    Type string
+    Data interface{}
+}
+
+// ClientEvents are the events a client may send
+var ClientEvents = []EventSpec{
+    {EventJoinRoom, JoinRoomData{}},
+    {EventStartGame, nil},
+    {EventSubmitAnswer, SubmitAnswerData{}},
+    {EventPlayAgain, PlayAgainData{}},
+    {EventReconnect, ReconnectData{}},
+    {EventPing, PingData{}},
+    {EventPauseGame, nil},
+    {EventResumeGame, nil},
+    {EventSkipQuestion, nil},
+    {EventReady, nil},
+}
+
+// ServerEvents are the events the server may send
+var ServerEvents = []EventSpec{
+    {EventConnected, ConnectedData{}},
+    {EventAck, AckData{}},
+    {EventError, ErrorData{}},
+    {EventPlayerJoined, PlayerJoinedData{}},
+    {EventRoomJoined, RoomJoinedData{}},
+    {EventPlayerDisconnected, PlayerData{}},
+    {EventPlayerReconnected, PlayerData{}},
+    {EventReconnected, GameStateData{}},
+    {EventRoundStarted, RoundStartedData{}},
+    {EventTimerUpdate, TimerUpdateData{}},
+    {EventTimerWarning, TimerWarningData{}},
+    {EventPong, PongData{}},
+    {EventAnswerResult, RoundResult{}},
+    {EventRoundResult, RoundResultData{}},
+    {EventGameEnd, GameEndData{}},
+    {EventGameRestart, GameRestartData{}},
+    {EventGamePaused, GamePausedData{}},
+    {EventGameResumed, GameResumedData{}},
+    {EventQuestionSkipped, QuestionSkippedData{}},
+    {EventIntermission, IntermissionData{}},
+    {EventIntermissionTick, IntermissionCountdownData{}},
+    {EventServerShutdown, ServerShutdownData{}},
+}
+
+// Client -> server payloads
+
+type JoinRoomData struct {
+    RoomCode string `json:"room_code"`
+    Username string `json:"username"`
+}
+
+type SubmitAnswerData struct {
+    Answer string `json:"answer"`
+}
+
+type PlayAgainData struct {
+    MaxRounds        int   `json:"max_rounds,omitempty"`
+    RoundTime        int   `json:"round_time,omitempty"`
+    TimerUpdates     *bool `json:"timer_updates,omitempty"`
+    IntermissionTime int   `json:"intermission_time,omitempty"`
+    ReadyCheck       *bool `json:"ready_check,omitempty"`
+    DifficultyRamp   *bool `json:"difficulty_ramp,omitempty"`
+}
+
+type ReconnectData struct {
+    RoomCode string `json:"room_code"`
+    PlayerID string `json:"player_id"`
+    Username string `json:"username,omitempty"`
+}
+
+// PingData asks for the server clock. Times are Unix milliseconds.
+type PingData struct {
+    ClientTime int64 `json:"client_time"`
+}
+
+// Server -> client payloads
+
+type ConnectedData struct {
+    ClientID          string `json:"client_id"`
+    ProtocolVersion   int    `json:"protocol_version"`
+    SupportedVersions []int  `json:"supported_versions"`
+    SessionToken      string `json:"session_token,omitempty"` // SSE only: authorizes POSTed messages
+    AccountID         string `json:"account_id,omitempty"`    // Set when connected signed in
+    Username          string `json:"username,omitempty"`
+}
+
+type AckData struct {
+    Event string `json:"event"`
+}
+
+type ErrorData struct {
+    Code       ErrorCode `json:"code"`
+    Message    string    `json:"message"`
+    RetryAfter int64     `json:"retry_after,omitempty"` // Milliseconds to wait before sending again, for RATE_LIMITED
+}
+
+type Player struct {
+    ID         string `json:"id"`
+    Username   string `json:"username"`
+    Registered bool   `json:"registered,omitempty"` // Signed in; ID is the account ID
+}
+
+type PlayerData struct {
+    PlayerID string `json:"player_id"`
+    Username string `json:"username"`
+}
+
+type PlayerJoinedData struct {
+    PlayerID     string `json:"player_id"`
+    Username     string `json:"username"`
+    TotalPlayers int    `json:"total_players"`
+}
+
+type RoomSettings struct {
+    MaxPlayers       int    `json:"max_players"`
+    RoundTime        int    `json:"round_time"`
+    MaxRounds        int    `json:"max_rounds"`
+    TimerUpdates     bool   `json:"timer_updates"`     // Per-second timer_update broadcasts
+    IntermissionTime int    `json:"intermission_time"` // Seconds between rounds
+    ReadyCheck       bool   `json:"ready_check"`       // Next round starts once everyone is ready
+    DifficultyRamp   bool   `json:"difficulty_ramp"`   // Questions get harder as the game goes on
+    PackID           string `json:"pack_id,omitempty"` // Private question pack the room draws from
+}
+
+type RoomJoinedData struct {
+    RoomCode string       `json:"room_code"`
+    HostID   string       `json:"host_id"`
+    Players  []Player     `json:"players"`
+    Settings RoomSettings `json:"settings"`
+}
+
+// Question is a question as shown to players, without its answer
+type Question struct {
+    ID        string `json:"id"`
+    Content   string `json:"content"`
+    Category  string `json:"category,omitempty"`
+    MediaURL  string `json:"media_url,omitempty"`
+    MediaType string `json:"media_type,omitempty"` // "image", "audio", "video"
+}
+
+// RoundStartedData lets clients run the countdown locally. Deadline and
+// ServerTime are Unix milliseconds; clients correct for clock skew with ping.
+type RoundStartedData struct {
+    Question    Question `json:"question"`
+    RoundNumber int      `json:"round_number"`
+    TimeLimit   int      `json:"time_limit"`
+    Deadline    int64    `json:"deadline"`
+    ServerTime  int64    `json:"server_time"`
+}
+
+type TimerUpdateData struct {
+    Remaining int  `json:"remaining"`
+    Warning   bool `json:"warning"`
+}
+
+// TimerWarningData is sent once per round shortly before the deadline
+type TimerWarningData struct {
+    Remaining int   `json:"remaining"`
+    Deadline  int64 `json:"deadline"`
+}
+
+// PongData answers a ping. Round-trip time is now - client_time, and the
+// server clock offset is about server_time - (client_time + rtt/2).
+type PongData struct {
+    ClientTime int64 `json:"client_time"`
+    ServerTime int64 `json:"server_time"`
+}
+
+// RoundResult is a player's outcome for one round. It is also the payload
+// of answer_result.
+type RoundResult struct {
+    Correct bool   `json:"correct"`
+    Score   int    `json:"score"`
+    Order   int    `json:"order"`
+    Message string `json:"message,omitempty"`
+}
+
+type Answer struct {
+    PlayerID    string    `json:"player_id"`
+    Answer      string    `json:"answer"`
+    Score       int       `json:"score"`
+    AnswerOrder int       `json:"answer_order"`
+    AnsweredAt  time.Time `json:"answered_at"`
+}
+
+type RoundResultData struct {
+    RoundNumber   int      `json:"round_number"`
+    Answers       []Answer `json:"answers"`
+    Question      Question `json:"question"`
+    CorrectAnswer string   `json:"correct_answer"`
+}
+
+type PlayerResult struct {
+    PlayerID   string        `json:"player_id"`
+    Username   string        `json:"username"`
+    TotalScore int           `json:"total_score"`
+    Rank       int           `json:"rank"`
+    Rounds     []RoundResult `json:"rounds"`
+}
+
+type GameEndData struct {
+    FinalResults []*PlayerResult `json:"final_results"`
+    TotalRounds  int             `json:"total_rounds"`
+    RoomCode     string          `json:"room_code"`
+}
+
+type GameRestartData struct {
+    Settings RoomSettings `json:"settings"`
+}
+
+// GamePausedData freezes the countdown. Remaining is what's left of the
+// round, in seconds.
+type GamePausedData struct {
+    RoundNumber int    `json:"round_number"`
+    Remaining   int    `json:"remaining"`
+    PausedBy    string `json:"paused_by"`
+}
+
+// GameResumedData restarts the countdown with a new deadline
+type GameResumedData struct {
+    RoundNumber int   `json:"round_number"`
+    Remaining   int   `json:"remaining"`
+    Deadline    int64 `json:"deadline"`
+    ServerTime  int64 `json:"server_time"`
+}
+
+// QuestionSkippedData is sent when the host throws out a question. Points
+// scored on it don't count, and a new question follows for the same round.
+type QuestionSkippedData struct {
+    RoundNumber   int      `json:"round_number"`
+    Question      Question `json:"question"`
+    CorrectAnswer string   `json:"correct_answer"`
+}
+
+// IntermissionData is sent when a round ends and another one follows.
+// Deadline and ServerTime are Unix milliseconds.
+type IntermissionData struct {
+    NextRound  int   `json:"next_round"`
+    Duration   int   `json:"duration"`
+    Deadline   int64 `json:"deadline"`
+    ServerTime int64 `json:"server_time"`
+    ReadyCheck bool  `json:"ready_check"`
+}
+
+// IntermissionCountdownData is sent every second of the intermission and
+// whenever a player gets ready
+type IntermissionCountdownData struct {
+    NextRound    int `json:"next_round"`
+    Remaining    int `json:"remaining"`
+    ReadyCount   int `json:"ready_count"`
+    TotalPlayers int `json:"total_players"`
+}
+
+// ServerShutdownData is sent to every client when the server starts
+// draining. Games underway get until Deadline (Unix milliseconds) to
+// finish; any still running then are paused, and the connection closes.
+type ServerShutdownData struct {
+    Message    string `json:"message"`
+    Deadline   int64  `json:"deadline"`
+    ServerTime int64  `json:"server_time"`
+}
+
+type Round struct {
+    ID          string    `json:"id"`
+    RoundNumber int       `json:"round_number"`
+    State       string    `json:"state"`
+    StartTime   time.Time `json:"start_time"`
+    EndTime     time.Time `json:"end_time"`
+    AnswerCount int       `json:"answer_count"`
+}
+
+// GameStateData is sent to a reconnecting player so it can resume the game
+type GameStateData struct {
+    RoomCode        string            `json:"room_code"`
+    GameStatus      string            `json:"game_status"`
+    CurrentRound    int               `json:"current_round"`
+    MaxRounds       int               `json:"max_rounds"`
+    RoundTime       int               `json:"round_time"`
+    Players         []Player          `json:"players"`
+    YourAnswers     []Answer          `json:"your_answers"`
+    YourScore       int               `json:"your_score"`
+    Rounds          []Round           `json:"rounds"`
+    CurrentQuestion *Question         `json:"current_question,omitempty"`
+    RoundEndTime    *time.Time        `json:"round_end_time,omitempty"`
+    TimeRemaining   int               `json:"time_remaining,omitempty"`
+    Deadline        int64             `json:"deadline,omitempty"`
+    Paused          bool              `json:"paused"`
+    Intermission    *IntermissionData `json:"intermission,omitempty"`
+    ServerTime      int64             `json:"server_time"`
+}
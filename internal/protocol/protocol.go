@@ -0,0 +1,148 @@
+// internal/protocol/protocol.go
+
+// Package protocol defines the messages exchanged with clients over the
+// real-time connection.
+package protocol
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Version is the protocol version spoken by this server
+const Version = 1
+
+// SupportedVersions lists every protocol version the server accepts
+var SupportedVersions = []int{1}
+
+// Negotiate picks the protocol version for a connection. An empty request
+// gets the current version.
+func Negotiate(requested string) (int, error) {
+    if requested == "" {
+        return Version, nil
+    }
+
+    version, err := strconv.Atoi(requested)
+    if err != nil {
+        return 0, fmt.Errorf("invalid protocol version %q", requested)
+    }
+
+    for _, supported := range SupportedVersions {
+        if version == supported {
+            return version, nil
+        }
+    }
+    return 0, fmt.Errorf("unsupported protocol version %d", version)
+}
+
+// Client -> server event types
+const (
+    EventJoinRoom     = "join_room"
+    EventStartGame    = "start_game"
+    EventSubmitAnswer = "submit_answer"
+    EventPlayAgain    = "play_again"
+    EventReconnect    = "reconnect"
+    EventPing         = "ping"
+    EventPauseGame    = "pause_game"
+    EventResumeGame   = "resume_game"
+    EventSkipQuestion = "skip_question"
+    EventReady        = "ready"
+)
+
+// Server -> client event types
+const (
+    EventConnected          = "connected"
+    EventAck                = "ack"
+    EventError              = "error"
+    EventPlayerJoined       = "player_joined"
+    EventRoomJoined         = "room_joined"
+    EventPlayerDisconnected = "player_disconnected"
+    EventPlayerReconnected  = "player_reconnected"
+    EventReconnected        = "reconnected"
+    EventRoundStarted       = "round_started"
+    EventTimerUpdate        = "timer_update"
+    EventTimerWarning       = "timer_warning"
+    EventPong               = "pong"
+    EventAnswerResult       = "answer_result"
+    EventRoundResult        = "round_result"
+    EventGameEnd            = "game_end"
+    EventGameRestart        = "game_restart"
+    EventGamePaused         = "game_paused"
+    EventGameResumed        = "game_resumed"
+    EventQuestionSkipped    = "question_skipped"
+    EventIntermission       = "intermission"
+    EventIntermissionTick   = "intermission_countdown"
+    EventServerShutdown     = "server_shutdown"
+)
+
+// ErrorCode is a machine-readable error identifier sent to clients
+type ErrorCode string
+
+const (
+    CodeInvalidMessage      ErrorCode = "INVALID_MESSAGE"
+    CodeUnknownEvent        ErrorCode = "UNKNOWN_EVENT"
+    CodeNotInRoom           ErrorCode = "NOT_IN_ROOM"
+    CodeRoomNotFound        ErrorCode = "ROOM_NOT_FOUND"
+    CodeRoomFull            ErrorCode = "ROOM_FULL"
+    CodeGameInProgress      ErrorCode = "GAME_IN_PROGRESS"
+    CodeGameNotInProgress   ErrorCode = "GAME_NOT_IN_PROGRESS"
+    CodeNotEnoughPlayers    ErrorCode = "NOT_ENOUGH_PLAYERS"
+    CodeNotHost             ErrorCode = "NOT_HOST"
+    CodeRoundNotActive      ErrorCode = "ROUND_NOT_ACTIVE"
+    CodeGamePaused          ErrorCode = "GAME_PAUSED"
+    CodeGameNotPaused       ErrorCode = "GAME_NOT_PAUSED"
+    CodeNotInIntermission   ErrorCode = "NOT_IN_INTERMISSION"
+    CodeAlreadyAnswered     ErrorCode = "ALREADY_ANSWERED"
+    CodeQuestionUnavailable ErrorCode = "QUESTION_UNAVAILABLE"
+    CodeInvalidPlayer       ErrorCode = "INVALID_PLAYER"
+    CodeGameNotFound        ErrorCode = "GAME_NOT_FOUND"
+    CodeUsernameTaken       ErrorCode = "USERNAME_TAKEN"
+    CodeEmailTaken          ErrorCode = "EMAIL_TAKEN"
+    CodeInvalidCredentials  ErrorCode = "INVALID_CREDENTIALS"
+    CodeUnauthorized        ErrorCode = "UNAUTHORIZED"
+    CodeAccountBanned       ErrorCode = "ACCOUNT_BANNED"
+    CodeAccountNotFound     ErrorCode = "ACCOUNT_NOT_FOUND"
+    CodeAuthRequired        ErrorCode = "AUTH_REQUIRED"
+    CodeForbidden           ErrorCode = "FORBIDDEN"
+    CodeQuestionNotFound    ErrorCode = "QUESTION_NOT_FOUND"
+    CodeDuplicateQuestion   ErrorCode = "DUPLICATE_QUESTION"
+    CodePackNotFound        ErrorCode = "PACK_NOT_FOUND"
+    CodeRateLimited         ErrorCode = "RATE_LIMITED"
+    CodeServerDraining      ErrorCode = "SERVER_DRAINING"
+    CodeInternal            ErrorCode = "INTERNAL_ERROR"
+)
+
+// ErrorCodes lists every error code, in the order they are documented
+var ErrorCodes = []ErrorCode{
+    CodeInvalidMessage,
+    CodeUnknownEvent,
+    CodeNotInRoom,
+    CodeRoomNotFound,
+    CodeRoomFull,
+    CodeGameInProgress,
+    CodeGameNotInProgress,
+    CodeNotEnoughPlayers,
+    CodeNotHost,
+    CodeRoundNotActive,
+    CodeGamePaused,
+    CodeGameNotPaused,
+    CodeNotInIntermission,
+    CodeAlreadyAnswered,
+    CodeQuestionUnavailable,
+    CodeInvalidPlayer,
+    CodeGameNotFound,
+    CodeUsernameTaken,
+    CodeEmailTaken,
+    CodeInvalidCredentials,
+    CodeUnauthorized,
+    CodeAccountBanned,
+    CodeAccountNotFound,
+    CodeAuthRequired,
+    CodeForbidden,
+    CodeQuestionNotFound,
+    CodeDuplicateQuestion,
+    CodePackNotFound,
+    CodeRateLimited,
+    CodeServerDraining,
+    CodeInternal,
+}
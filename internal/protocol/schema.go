@@ -0,0 +1,199 @@
+// internal/protocol/schema.go
+
+package protocol
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// enums lists the allowed values of enum-like string types
+var enums = map[reflect.Type][]string{
+    reflect.TypeOf(ErrorCode("")): errorCodeStrings(),
+}
+
+func errorCodeStrings() []string {
+    values := make([]string, len(ErrorCodes))
+    for i, code := range ErrorCodes {
+        values[i] = string(code)
+    }
+    return values
+}
+
+// Schema generates the JSON Schema document describing every message of the
+// protocol. Frontends validate incoming messages against
+// #/definitions/ServerMessage and outgoing ones against #/definitions/ClientMessage.
+func Schema() ([]byte, error) {
+    definitions := make(map[string]interface{})
+
+    definitions["ClientMessage"] = map[string]interface{}{
+        "oneOf": eventRefs(ClientEvents, definitions),
+    }
+    definitions["ServerMessage"] = map[string]interface{}{
+        "oneOf": eventRefs(ServerEvents, definitions),
+    }
+
+    doc := map[string]interface{}{
+        "$schema":          "http://json-schema.org/draft-07/schema#",
+        "title":            "Quiz real-time protocol",
+        "protocol_version": Version,
+        "oneOf": []interface{}{
+            ref("ClientMessage"),
+            ref("ServerMessage"),
+        },
+        "definitions": definitions,
+    }
+
+    out, err := json.MarshalIndent(doc, "", "  ")
+    if err != nil {
+        return nil, err
+    }
+    return append(out, '\n'), nil
+}
+
+// eventRefs adds an envelope definition for each event and returns refs to them
+func eventRefs(events []EventSpec, definitions map[string]interface{}) []interface{} {
+    refs := make([]interface{}, 0, len(events))
+    for _, event := range events {
+        name := envelopeName(event.Type)
+
+        properties := map[string]interface{}{
+            "type":       map[string]interface{}{"const": event.Type},
+            "room_id":    map[string]interface{}{"type": "string"},
+            "request_id": map[string]interface{}{"type": "string"},
+        }
+        required := []string{"type"}
+
+        if event.Data != nil {
+            properties["data"] = schemaFor(reflect.TypeOf(event.Data), definitions)
+            required = append(required, "data")
+        }
+
+        definitions[name] = map[string]interface{}{
+            "type":                 "object",
+            "properties":           properties,
+            "required":             required,
+            "additionalProperties": false,
+        }
+        refs = append(refs, ref(name))
+    }
+    return refs
+}
+
+// envelopeName turns an event type like round_started into RoundStartedEvent
+func envelopeName(eventType string) string {
+    var b strings.Builder
+    for _, part := range strings.Split(eventType, "_") {
+        if part == "" {
+            continue
+        }
+        b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+    }
+    b.WriteString("Event")
+    return b.String()
+}
+
+func ref(name string) map[string]interface{} {
+    return map[string]interface{}{"$ref": "#/definitions/" + name}
+}
+
+// schemaFor describes a Go type, registering named structs as definitions
+func schemaFor(t reflect.Type, definitions map[string]interface{}) map[string]interface{} {
+    if t == timeType {
+        return map[string]interface{}{"type": "string", "format": "date-time"}
+    }
+
+    if values, ok := enums[t]; ok {
+        return map[string]interface{}{"type": "string", "enum": values}
+    }
+
+    switch t.Kind() {
+    case reflect.Ptr:
+        return schemaFor(t.Elem(), definitions)
+    case reflect.String:
+        return map[string]interface{}{"type": "string"}
+    case reflect.Bool:
+        return map[string]interface{}{"type": "boolean"}
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+        reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+        return map[string]interface{}{"type": "integer"}
+    case reflect.Float32, reflect.Float64:
+        return map[string]interface{}{"type": "number"}
+    case reflect.Slice, reflect.Array:
+        return map[string]interface{}{
+            "type":  "array",
+            "items": schemaFor(t.Elem(), definitions),
+        }
+    case reflect.Map:
+        return map[string]interface{}{
+            "type":                 "object",
+            "additionalProperties": schemaFor(t.Elem(), definitions),
+        }
+    case reflect.Struct:
+        name := t.Name()
+        if _, exists := definitions[name]; !exists {
+            // Reserve the name first so recursive types terminate
+            definitions[name] = nil
+            definitions[name] = structSchema(t, definitions)
+        }
+        return ref(name)
+    default:
+        // interface{} and anything else accepts any value
+        return map[string]interface{}{}
+    }
+}
+
+func structSchema(t reflect.Type, definitions map[string]interface{}) map[string]interface{} {
+    properties := make(map[string]interface{})
+    required := []string{}
+
+    for i := 0; i < t.NumField(); i++ {
+        field := t.Field(i)
+        if !field.IsExported() {
+            continue
+        }
+
+        name, omitempty := jsonName(field)
+        if name == "-" {
+            continue
+        }
+
+        properties[name] = schemaFor(field.Type, definitions)
+        if !omitempty && field.Type.Kind() != reflect.Ptr {
+            required = append(required, name)
+        }
+    }
+
+    return map[string]interface{}{
+        "type":                 "object",
+        "properties":           properties,
+        "required":             required,
+        "additionalProperties": false,
+    }
+}
+
+// jsonName returns the JSON key of a struct field and whether it is omitempty
+func jsonName(field reflect.StructField) (string, bool) {
+    tag := field.Tag.Get("json")
+    if tag == "" {
+        return field.Name, false
+    }
+
+    parts := strings.Split(tag, ",")
+    name := parts[0]
+    if name == "" {
+        name = field.Name
+    }
+
+    omitempty := false
+    for _, option := range parts[1:] {
+        if option == "omitempty" {
+            omitempty = true
+        }
+    }
+    return name, omitempty
+}
@@ -0,0 +1,91 @@
+// internal/protocol/schema_test.go
+
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+)
+
+// Regenerate the checked-in schema with:
+//
+//	go test ./internal/protocol -run TestSchemaUpToDate -update
+var update = flag.Bool("update", false, "rewrite the checked-in protocol schema")
+
+const schemaPath = "../../Docs/protocol.schema.json"
+
+func TestSchemaUpToDate(t *testing.T) {
+    generated, err := Schema()
+    if err != nil {
+        t.Fatalf("generating schema: %v", err)
+    }
+
+    if *update {
+        if err := os.WriteFile(schemaPath, generated, 0644); err != nil {
+            t.Fatalf("writing schema: %v", err)
+        }
+    }
+
+    checkedIn, err := os.ReadFile(schemaPath)
+    if err != nil {
+        t.Fatalf("reading %s: %v", schemaPath, err)
+    }
+
+    if !bytes.Equal(generated, checkedIn) {
+        t.Fatalf("%s is out of date with the protocol structs; rerun with -update", schemaPath)
+    }
+}
+
+func TestSchemaCoversEveryEvent(t *testing.T) {
+    generated, err := Schema()
+    if err != nil {
+        t.Fatalf("generating schema: %v", err)
+    }
+
+    var doc struct {
+        Definitions map[string]json.RawMessage `json:"definitions"`
+    }
+    if err := json.Unmarshal(generated, &doc); err != nil {
+        t.Fatalf("schema is not valid JSON: %v", err)
+    }
+
+    seen := make(map[string]bool)
+    for _, events := range [][]EventSpec{ClientEvents, ServerEvents} {
+        for _, event := range events {
+            if _, ok := doc.Definitions[envelopeName(event.Type)]; !ok {
+                t.Errorf("no definition for event %s", event.Type)
+            }
+            if seen[event.Type] {
+                t.Errorf("event %s registered twice", event.Type)
+            }
+            seen[event.Type] = true
+        }
+    }
+}
+
+func TestNegotiate(t *testing.T) {
+    tests := []struct {
+        requested string
+        want      int
+        wantErr   bool
+    }{
+        {"", Version, false},
+        {"1", 1, false},
+        {"99", 0, true},
+        {"abc", 0, true},
+    }
+
+    for _, tt := range tests {
+        got, err := Negotiate(tt.requested)
+        if (err != nil) != tt.wantErr {
+            t.Errorf("Negotiate(%q) error = %v, wantErr %v", tt.requested, err, tt.wantErr)
+            continue
+        }
+        if got != tt.want {
+            t.Errorf("Negotiate(%q) = %d, want %d", tt.requested, got, tt.want)
+        }
+    }
+}
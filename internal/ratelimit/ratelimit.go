@@ -0,0 +1,176 @@
+// internal/ratelimit/ratelimit.go
+
+// Package ratelimit keeps clients from flooding the server. Limits are
+// token buckets: each message or request takes a token, and tokens come
+// back at a steady rate up to the bucket's burst.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Rate is a token bucket's refill rate and size. A zero Rate doesn't limit.
+type Rate struct {
+    PerSecond float64 // Tokens added a second
+    Burst     int     // Most tokens the bucket holds
+}
+
+// Unlimited reports whether the rate lets everything through
+func (r Rate) Unlimited() bool {
+    return r.PerSecond <= 0
+}
+
+// Bucket is a single token bucket. It isn't safe for concurrent use.
+type Bucket struct {
+    rate   Rate
+    tokens float64
+    last   time.Time
+}
+
+// NewBucket returns a full bucket
+func NewBucket(rate Rate) *Bucket {
+    return &Bucket{rate: rate, tokens: float64(rate.Burst)}
+}
+
+// Take takes a token. When the bucket is empty it returns false and how
+// long until the next token.
+func (b *Bucket) Take(now time.Time) (bool, time.Duration) {
+    if b.rate.Unlimited() {
+        return true, 0
+    }
+
+    if !b.last.IsZero() {
+        b.tokens = math.Min(float64(b.rate.Burst), b.tokens+now.Sub(b.last).Seconds()*b.rate.PerSecond)
+    }
+    b.last = now
+
+    if b.tokens >= 1 {
+        b.tokens--
+        return true, 0
+    }
+    wait := (1 - b.tokens) / b.rate.PerSecond
+    return false, time.Duration(math.Ceil(wait * float64(time.Second)))
+}
+
+// full reports whether the bucket would be full at now, so forgetting it
+// changes nothing
+func (b *Bucket) full(now time.Time) bool {
+    return b.tokens+now.Sub(b.last).Seconds()*b.rate.PerSecond >= float64(b.rate.Burst)
+}
+
+// How often Keyed forgets the buckets of keys that went quiet
+const sweepInterval = time.Minute
+
+// Keyed keeps a bucket per key, such as a client IP
+type Keyed struct {
+    rate Rate
+
+    mu        sync.Mutex
+    buckets   map[string]*Bucket
+    lastSweep time.Time
+}
+
+// NewKeyed returns a limiter giving every key its own bucket of the rate
+func NewKeyed(rate Rate) *Keyed {
+    return &Keyed{
+        rate:    rate,
+        buckets: make(map[string]*Bucket),
+    }
+}
+
+// Allow takes a token from key's bucket. When it's empty it returns false
+// and how long until the next token.
+func (k *Keyed) Allow(key string) (bool, time.Duration) {
+    if k.rate.Unlimited() {
+        return true, 0
+    }
+
+    k.mu.Lock()
+    defer k.mu.Unlock()
+
+    now := time.Now()
+    if now.Sub(k.lastSweep) >= sweepInterval {
+        for key, bucket := range k.buckets {
+            if bucket.full(now) {
+                delete(k.buckets, key)
+            }
+        }
+        k.lastSweep = now
+    }
+
+    bucket, ok := k.buckets[key]
+    if !ok {
+        bucket = NewBucket(k.rate)
+        k.buckets[key] = bucket
+    }
+    return bucket.Take(now)
+}
+
+// Limits are the limits on the messages of each connection
+type Limits struct {
+    Messages   Rate            // Every message
+    Events     map[string]Rate // Messages of one event type, on top of Messages
+    MaxStrikes int             // Limited messages a minute before the client is dropped, 0 never drops
+}
+
+// Conn tracks one connection's limits. It's safe for concurrent use, as
+// event stream clients can post messages in parallel.
+type Conn struct {
+    limits Limits
+
+    mu       sync.Mutex
+    messages *Bucket
+    events   map[string]*Bucket
+    strikes  *Bucket
+}
+
+// NewConn returns the limits of a new connection
+func (l Limits) NewConn() *Conn {
+    c := &Conn{
+        limits:   l,
+        messages: NewBucket(l.Messages),
+        events:   make(map[string]*Bucket),
+    }
+    if l.MaxStrikes > 0 {
+        // Strikes wear off over a minute
+        c.strikes = NewBucket(Rate{PerSecond: float64(l.MaxStrikes) / 60, Burst: l.MaxStrikes})
+    }
+    return c
+}
+
+// Allow takes a token for a message of the event type. When the message is
+// over a limit it returns false, how long until it would be allowed, and
+// whether the connection has hit its limits so often it should be dropped.
+// A nil Conn allows everything.
+func (c *Conn) Allow(event string) (ok bool, retryAfter time.Duration, abusive bool) {
+    if c == nil {
+        return true, 0, false
+    }
+
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    now := time.Now()
+    ok, retryAfter = c.messages.Take(now)
+    if ok {
+        if rate, limited := c.limits.Events[event]; limited {
+            bucket, exists := c.events[event]
+            if !exists {
+                bucket = NewBucket(rate)
+                c.events[event] = bucket
+            }
+            ok, retryAfter = bucket.Take(now)
+        }
+    }
+    if ok {
+        return true, 0, false
+    }
+
+    if c.strikes != nil {
+        struck, _ := c.strikes.Take(now)
+        abusive = !struck
+    }
+    return false, retryAfter, abusive
+}
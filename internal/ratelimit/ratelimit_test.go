@@ -0,0 +1,90 @@
+// internal/ratelimit/ratelimit_test.go
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucket(t *testing.T) {
+    start := time.Now()
+    bucket := NewBucket(Rate{PerSecond: 2, Burst: 3})
+
+    for i := 0; i < 3; i++ {
+        if ok, _ := bucket.Take(start); !ok {
+            t.Fatalf("token %d of the burst was refused", i+1)
+        }
+    }
+    ok, wait := bucket.Take(start)
+    if ok || wait != 500*time.Millisecond {
+        t.Fatalf("got %v, %v on an empty bucket, want a 500ms wait", ok, wait)
+    }
+
+    if ok, _ := bucket.Take(start.Add(500 * time.Millisecond)); !ok {
+        t.Error("no token came back after 500ms")
+    }
+    // Idle time refills up to the burst only
+    later := start.Add(time.Hour)
+    for i := 0; i < 3; i++ {
+        bucket.Take(later)
+    }
+    if ok, _ := bucket.Take(later); ok {
+        t.Error("the bucket held more than its burst")
+    }
+}
+
+func TestUnlimited(t *testing.T) {
+    keyed := NewKeyed(Rate{})
+    for i := 0; i < 100; i++ {
+        if ok, _ := keyed.Allow("10.0.0.1"); !ok {
+            t.Fatal("a zero rate limited a request")
+        }
+    }
+
+    var conn *Conn
+    if ok, _, _ := conn.Allow("submit_answer"); !ok {
+        t.Error("a nil Conn limited a message")
+    }
+}
+
+func TestKeyed(t *testing.T) {
+    keyed := NewKeyed(Rate{PerSecond: 1, Burst: 1})
+    if ok, _ := keyed.Allow("10.0.0.1"); !ok {
+        t.Fatal("first request refused")
+    }
+    if ok, _ := keyed.Allow("10.0.0.1"); ok {
+        t.Error("second request in a row allowed")
+    }
+    if ok, _ := keyed.Allow("10.0.0.2"); !ok {
+        t.Error("another key shared the first one's bucket")
+    }
+}
+
+func TestConn(t *testing.T) {
+    conn := Limits{
+        Messages:   Rate{PerSecond: 100, Burst: 100},
+        Events:     map[string]Rate{"submit_answer": {PerSecond: 1, Burst: 2}},
+        MaxStrikes: 3,
+    }.NewConn()
+
+    for i := 0; i < 2; i++ {
+        if ok, _, _ := conn.Allow("submit_answer"); !ok {
+            t.Fatalf("answer %d refused", i+1)
+        }
+    }
+    ok, retryAfter, abusive := conn.Allow("submit_answer")
+    if ok || retryAfter <= 0 || abusive {
+        t.Fatalf("got %v, %v, %v for the third answer, want limited with a retry", ok, retryAfter, abusive)
+    }
+    if ok, _, _ := conn.Allow("ping"); !ok {
+        t.Error("other events were limited by submit_answer's bucket")
+    }
+
+    // The strikes run out after MaxStrikes limited messages
+    conn.Allow("submit_answer")
+    conn.Allow("submit_answer")
+    if _, _, abusive := conn.Allow("submit_answer"); !abusive {
+        t.Error("the connection wasn't flagged after using up its strikes")
+    }
+}
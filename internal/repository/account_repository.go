@@ -0,0 +1,147 @@
+// internal/repository/account_repository.go
+
+package repository
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/rohan03122001/quizzing/internal/models"
+)
+
+// CreateAccount returns these when another account has the username or
+// email, ignoring case
+var (
+    ErrUsernameExists = errors.New("username already exists")
+    ErrEmailExists    = errors.New("email already exists")
+)
+
+type AccountRepository struct {
+    db *Database
+}
+
+func NewAccountRepository(db *Database) *AccountRepository {
+    return &AccountRepository{
+        db: db,
+    }
+}
+
+// CreateAccount adds a new account
+func (r *AccountRepository) CreateAccount(account *models.Account) error {
+    slog.Debug("Creating account", "username", account.Username)
+    err := r.db.Create(account).Error
+    if err == nil {
+        return nil
+    }
+
+    // Both drivers name the index or column in the message: SQLite's
+    // "UNIQUE constraint failed: ...", Postgres's "duplicate key value
+    // violates unique constraint ..."
+    msg := strings.ToLower(err.Error())
+    if !strings.Contains(msg, "unique constraint") {
+        return err
+    }
+    if strings.Contains(msg, "email") {
+        return ErrEmailExists
+    }
+    return ErrUsernameExists
+}
+
+// GetByID finds an account by its ID
+func (r *AccountRepository) GetByID(id string) (*models.Account, error) {
+    var account models.Account
+    if err := r.db.First(&account, "id = ?", id).Error; err != nil {
+        return nil, err
+    }
+    return &account, nil
+}
+
+// GetByUsername finds an account by username, ignoring case
+func (r *AccountRepository) GetByUsername(username string) (*models.Account, error) {
+    var account models.Account
+    if err := r.db.Where("LOWER(username) = LOWER(?)", username).First(&account).Error; err != nil {
+        return nil, err
+    }
+    return &account, nil
+}
+
+// GetByEmail finds an account by email, ignoring case
+func (r *AccountRepository) GetByEmail(email string) (*models.Account, error) {
+    var account models.Account
+    if err := r.db.Where("LOWER(email) = LOWER(?)", email).First(&account).Error; err != nil {
+        return nil, err
+    }
+    return &account, nil
+}
+
+// Exists checks if there is an account with the given ID
+func (r *AccountRepository) Exists(id string) (bool, error) {
+    var count int64
+    err := r.db.Model(&models.Account{}).Where("id = ?", id).Count(&count).Error
+    return count > 0, err
+}
+
+// UpdateLastLogin records a successful sign-in
+func (r *AccountRepository) UpdateLastLogin(id string) error {
+    return r.db.Model(&models.Account{}).
+        Where("id = ?", id).
+        Update("last_login_at", time.Now()).Error
+}
+
+// SetBanned bans an account, or lifts the ban when bannedAt is nil. It
+// reports whether the account exists.
+func (r *AccountRepository) SetBanned(id string, bannedAt *time.Time, reason string) (bool, error) {
+    slog.Info("Setting account ban", "account_id", id, "banned", bannedAt != nil)
+    result := r.db.Model(&models.Account{}).
+        Where("id = ?", id).
+        Updates(map[string]interface{}{
+            "banned_at":  bannedAt,
+            "ban_reason": reason,
+        })
+    return result.RowsAffected > 0, result.Error
+}
+
+// SetAdmin grants or revokes admin rights by username, ignoring case. It
+// reports whether the account exists.
+func (r *AccountRepository) SetAdmin(username string, admin bool) (bool, error) {
+    result := r.db.Model(&models.Account{}).
+        Where("LOWER(username) = LOWER(?)", username).
+        Update("is_admin", admin)
+    return result.RowsAffected > 0, result.Error
+}
+
+// SaveToken stores a session or magic-link token
+func (r *AccountRepository) SaveToken(token *models.AuthToken) error {
+    return r.db.Create(token).Error
+}
+
+// GetToken finds an unexpired token of the given kind
+func (r *AccountRepository) GetToken(tokenHash string, kind string) (*models.AuthToken, error) {
+    var token models.AuthToken
+    err := r.db.Where("token_hash = ? AND kind = ? AND expires_at > ?", tokenHash, kind, time.Now()).
+        First(&token).Error
+    if err != nil {
+        return nil, err
+    }
+    return &token, nil
+}
+
+// ClaimToken deletes an unexpired token of the given kind. It reports
+// whether this call deleted it, so only one caller can use a token.
+func (r *AccountRepository) ClaimToken(tokenHash string, kind string) (bool, error) {
+    result := r.db.Where("token_hash = ? AND kind = ? AND expires_at > ?", tokenHash, kind, time.Now()).
+        Delete(&models.AuthToken{})
+    return result.RowsAffected == 1, result.Error
+}
+
+// DeleteToken revokes a token
+func (r *AccountRepository) DeleteToken(tokenHash string) error {
+    return r.db.Where("token_hash = ?", tokenHash).Delete(&models.AuthToken{}).Error
+}
+
+// DeleteAccountTokens revokes every token of an account
+func (r *AccountRepository) DeleteAccountTokens(accountID string) error {
+    return r.db.Where("account_id = ?", accountID).Delete(&models.AuthToken{}).Error
+}
@@ -3,44 +3,67 @@
 package repository
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"strings"
+	"time"
 
-	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/metrics"
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 type Database struct {
     *gorm.DB
 }
 
+// Database drivers
+const (
+    DriverPostgres = "postgres"
+    DriverSQLite   = "sqlite"
+)
+
 type DBConfig struct {
+    Driver   string // "postgres" (the default) or "sqlite"
     Host     string
     Port     string
     User     string
     Password string
     DBName   string
     SSLMode  string
+    Path     string // SQLite database file, or ":memory:"
 }
 
 func NewDatabase(config *DBConfig) (*Database, error) {
-    // Construct DSN (Data Source Name)
-    dsn := fmt.Sprintf(
-        "host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
-        config.Host, config.User, config.Password, config.DBName, config.Port, config.SSLMode,
-    )
-
-    log.Printf("Connecting to database on %s:%s", config.Host, config.Port)
+    var dialector gorm.Dialector
+    switch config.Driver {
+    case "", DriverPostgres:
+        // Construct DSN (Data Source Name)
+        dsn := fmt.Sprintf(
+            "host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+            config.Host, config.User, config.Password, config.DBName, config.Port, config.SSLMode,
+        )
+        slog.Info("Connecting to database", "host", config.Host, "port", config.Port)
+        dialector = postgres.Open(dsn)
+    case DriverSQLite:
+        if config.Path == "" {
+            return nil, fmt.Errorf("sqlite needs a database path")
+        }
+        slog.Info("Opening SQLite database", "path", config.Path)
+        dialector = sqlite.Open(sqliteDSN(config.Path))
+    default:
+        return nil, fmt.Errorf("unknown database driver %q", config.Driver)
+    }
 
     // Configure GORM
     gormConfig := &gorm.Config{
-        Logger: logger.Default.LogMode(logger.Info), // Set logging level
+        Logger: gormLogger{}, // SQL is logged at debug level
     }
 
     // Open connection
-    db, err := gorm.Open(postgres.Open(dsn), gormConfig)
+    db, err := gorm.Open(dialector, gormConfig)
     if err != nil {
         return nil, fmt.Errorf("failed to connect to database: %w", err)
     }
@@ -52,32 +75,85 @@ func NewDatabase(config *DBConfig) (*Database, error) {
     }
 
     // Set connection pool settings
-    sqlDB.SetMaxIdleConns(10)  // Maximum number of idle connections
-    sqlDB.SetMaxOpenConns(100) // Maximum number of open connections
-
-    log.Println("Running database migrations...")
-    
-    // Auto migrate schemas
-    err = db.AutoMigrate(
-        &models.Room{},
-        &models.Question{},
-        &models.GameRound{},
-        &models.PlayerAnswer{},
-    )
-    if err != nil {
-        return nil, fmt.Errorf("failed to migrate database: %w", err)
+    if config.Driver == DriverSQLite {
+        // SQLite takes one writer at a time, and each connection to
+        // ":memory:" would get its own empty database
+        sqlDB.SetMaxOpenConns(1)
+    } else {
+        sqlDB.SetMaxIdleConns(10)  // Maximum number of idle connections
+        sqlDB.SetMaxOpenConns(100) // Maximum number of open connections
     }
 
-    log.Println("Database migrations completed successfully")
-
     return &Database{db}, nil
 }
 
+// sqliteDSN turns on foreign keys and waits out locks instead of failing
+func sqliteDSN(path string) string {
+    separator := "?"
+    if strings.Contains(path, "?") {
+        separator = "&"
+    }
+    return path + separator + "_foreign_keys=on&_busy_timeout=5000"
+}
+
+// SetMetrics times every query with GORM callbacks. Call it before the
+// database is used.
+func (d *Database) SetMetrics(m *metrics.Metrics) error {
+    const startKey = "metrics:start"
+    before := func(db *gorm.DB) {
+        db.InstanceSet(startKey, time.Now())
+    }
+    after := func(operation string) func(*gorm.DB) {
+        return func(db *gorm.DB) {
+            start, ok := db.InstanceGet(startKey)
+            if !ok {
+                return
+            }
+            table := db.Statement.Table
+            if table == "" {
+                table = "raw"
+            }
+            m.Query(operation, table, time.Since(start.(time.Time)))
+        }
+    }
+
+    callbacks := d.Callback()
+    errs := []error{
+        callbacks.Create().Before("gorm:create").Register("metrics:before_create", before),
+        callbacks.Create().After("gorm:create").Register("metrics:after_create", after("create")),
+        callbacks.Query().Before("gorm:query").Register("metrics:before_query", before),
+        callbacks.Query().After("gorm:query").Register("metrics:after_query", after("query")),
+        callbacks.Update().Before("gorm:update").Register("metrics:before_update", before),
+        callbacks.Update().After("gorm:update").Register("metrics:after_update", after("update")),
+        callbacks.Delete().Before("gorm:delete").Register("metrics:before_delete", before),
+        callbacks.Delete().After("gorm:delete").Register("metrics:after_delete", after("delete")),
+        callbacks.Row().Before("gorm:row").Register("metrics:before_row", before),
+        callbacks.Row().After("gorm:row").Register("metrics:after_row", after("row")),
+        callbacks.Raw().Before("gorm:raw").Register("metrics:before_raw", before),
+        callbacks.Raw().After("gorm:raw").Register("metrics:after_raw", after("raw")),
+    }
+    for _, err := range errs {
+        if err != nil {
+            return fmt.Errorf("registering metrics callbacks: %w", err)
+        }
+    }
+    return nil
+}
+
 // GetDB returns the underlying GORM DB instance
 func (d *Database) GetDB() *gorm.DB {
     return d.DB
 }
 
+// Ping checks the database can be reached
+func (d *Database) Ping(ctx context.Context) error {
+    sqlDB, err := d.DB.DB()
+    if err != nil {
+        return err
+    }
+    return sqlDB.PingContext(ctx)
+}
+
 // Close closes the database connection
 func (d *Database) Close() error {
     sqlDB, err := d.DB.DB()
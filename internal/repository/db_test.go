@@ -0,0 +1,116 @@
+// internal/repository/db_test.go
+
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rohan03122001/quizzing/internal/metrics"
+	"github.com/rohan03122001/quizzing/internal/models"
+)
+
+func TestQueryMetricsAndRoomCounts(t *testing.T) {
+    db := openSQLite(t)
+    if _, err := db.MigrateUp(); err != nil {
+        t.Fatal(err)
+    }
+    reg := prometheus.NewRegistry()
+    if err := db.SetMetrics(metrics.New(reg)); err != nil {
+        t.Fatal(err)
+    }
+
+    rooms := NewRoomRepository(db)
+    for i, status := range []string{"waiting", "playing", "playing"} {
+        room := &models.Room{Code: string(rune('A'+i)) + "BCDEF", Status: status}
+        if err := rooms.CreateRoom(room); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    counts, err := rooms.CountByStatus()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if counts["waiting"] != 1 || counts["playing"] != 2 {
+        t.Errorf("got counts %v, want 1 waiting and 2 playing", counts)
+    }
+
+    families, err := reg.Gather()
+    if err != nil {
+        t.Fatal(err)
+    }
+    timed := map[string]uint64{}
+    for _, family := range families {
+        if family.GetName() != "quiz_db_query_duration_seconds" {
+            continue
+        }
+        for _, metric := range family.GetMetric() {
+            labels := metric.GetLabel()
+            timed[labels[0].GetValue()+" "+labels[1].GetValue()] = metric.GetHistogram().GetSampleCount()
+        }
+    }
+    if timed["create rooms"] != 3 || timed["row rooms"]+timed["query rooms"] == 0 {
+        t.Errorf("got timed queries %v, want 3 room creates and the count", timed)
+    }
+}
+
+func TestOneCorrectAnswerPerRound(t *testing.T) {
+    db := openSQLite(t)
+    if _, err := db.MigrateUp(); err != nil {
+        t.Fatal(err)
+    }
+
+    room := &models.Room{Code: "ABCDEF", Status: "playing"}
+    if err := NewRoomRepository(db).CreateRoom(room); err != nil {
+        t.Fatal(err)
+    }
+    question := &models.Question{Content: "Capital of France?", Answer: "Paris"}
+    if err := NewQuestionRepository(db).CreateQuestion(question); err != nil {
+        t.Fatal(err)
+    }
+    rounds := NewGameRoundRepository(db)
+    round := &models.GameRound{RoomID: room.ID, QuestionID: question.ID, RoundNumber: 1, State: "active"}
+    if err := rounds.CreateRound(round); err != nil {
+        t.Fatal(err)
+    }
+
+    score := func(order int) int { return 1000 / order }
+    answer := func(text string) *models.PlayerAnswer {
+        return &models.PlayerAnswer{RoundID: round.ID, PlayerID: "alice", Answer: text, AnsweredAt: time.Now()}
+    }
+
+    // Wrong answers aren't limited
+    for i := 0; i < 2; i++ {
+        if err := rounds.SaveAnswer(answer("Lyon")); err != nil {
+            t.Fatal(err)
+        }
+    }
+    correct := answer("Paris")
+    if err := rounds.SaveCorrectAnswer(correct, score); err != nil {
+        t.Fatal(err)
+    }
+    if correct.AnswerOrder != 1 || correct.Score != 1000 {
+        t.Errorf("got order %d and score %d, want 1 and 1000", correct.AnswerOrder, correct.Score)
+    }
+    if err := rounds.SaveCorrectAnswer(answer("Paris"), score); !errors.Is(err, ErrAlreadyAnswered) {
+        t.Errorf("got %v scoring twice, want ErrAlreadyAnswered", err)
+    }
+
+    // The index holds even when the check is skipped
+    duplicate := answer("Paris")
+    duplicate.AnswerOrder = 2
+    if err := rounds.SaveAnswer(duplicate); err == nil {
+        t.Error("saved a second correct answer for the same player")
+    }
+
+    current, err := rounds.GetCurrentRound(room.ID.String())
+    if err != nil {
+        t.Fatal(err)
+    }
+    if current.AnswerCount != 1 {
+        t.Errorf("got answer count %d, want 1", current.AnswerCount)
+    }
+}
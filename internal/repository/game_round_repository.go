@@ -3,11 +3,19 @@
 package repository
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/rohan03122001/quizzing/internal/models"
+	"gorm.io/gorm"
 )
 
+// SaveCorrectAnswer returns this when the player already scored in the round
+var ErrAlreadyAnswered = errors.New("player already answered the round correctly")
+
 type GameRoundRepository struct {
     db *Database
 }
@@ -18,20 +26,24 @@ func NewGameRoundRepository(db *Database) *GameRoundRepository {
     }
 }
 
+// WithContext returns the repository running its queries with ctx, so they
+// join the caller's trace
+func (r *GameRoundRepository) WithContext(ctx context.Context) RoundStore {
+    return &GameRoundRepository{db: &Database{r.db.WithContext(ctx)}}
+}
+
 // CreateRound starts a new round
 func (r *GameRoundRepository) CreateRound(round *models.GameRound) error {
-    log.Printf("Creating round %d for room %s", round.RoundNumber, round.RoomID)
+    slog.Debug("Creating round", "room_id", round.RoomID, "round", round.RoundNumber)
     return r.db.Create(round).Error
 }
 
 // GetCurrentRound gets the active round for a room
 func (r *GameRoundRepository) GetCurrentRound(roomID string) (*models.GameRound, error) {
-    log.Printf("Fetching current round for room %s", roomID)
     var round models.GameRound
     err := r.db.Where("room_id = ? AND state = ?", roomID, "active").
         First(&round).Error
     if err != nil {
-        log.Printf("Error fetching current round: %v", err)
         return nil, err
     }
     return &round, nil
@@ -39,13 +51,21 @@ func (r *GameRoundRepository) GetCurrentRound(roomID string) (*models.GameRound,
 
 // SaveAnswer records a player's answer
 func (r *GameRoundRepository) SaveAnswer(answer *models.PlayerAnswer) error {
-    log.Printf("Saving answer from player %s for round %s", answer.PlayerID, answer.RoundID)
+    slog.Debug("Saving answer", "round_id", answer.RoundID, "player", answer.PlayerID)
     return r.db.Create(answer).Error
 }
 
+// HasCorrectAnswer checks if a player already answered a round correctly
+func (r *GameRoundRepository) HasCorrectAnswer(roundID string, playerID string) (bool, error) {
+    var count int64
+    err := r.db.Model(&models.PlayerAnswer{}).
+        Where("round_id = ? AND player_id = ? AND answer_order > 0", roundID, playerID).
+        Count(&count).Error
+    return count > 0, err
+}
+
 // GetRoundAnswers gets all answers for a round
 func (r *GameRoundRepository) GetRoundAnswers(roundID string) ([]models.PlayerAnswer, error) {
-    log.Printf("Fetching answers for round %s", roundID)
     var answers []models.PlayerAnswer
     err := r.db.Where("round_id = ?", roundID).
         Order("answer_order asc").
@@ -53,22 +73,95 @@ func (r *GameRoundRepository) GetRoundAnswers(roundID string) ([]models.PlayerAn
     return answers, err
 }
 
-// UpdateAnswerCount increments the answer count
-func (r *GameRoundRepository) UpdateAnswerCount(roundID string) error {
-    log.Printf("Incrementing answer count for round %s", roundID)
-    return r.db.Model(&models.GameRound{}).
-        Where("id = ?", roundID).
-        UpdateColumn("answer_count", r.db.Raw("answer_count + 1")).Error
+// SaveCorrectAnswer records a correct answer in one transaction: it checks
+// the player hasn't scored in the round yet, counts the answer and saves it
+// with its order and the score for that order. Incrementing the count locks
+// the round, so concurrent answers get different orders, and the
+// idx_player_answers_one_correct index stops a player scoring twice.
+func (r *GameRoundRepository) SaveCorrectAnswer(answer *models.PlayerAnswer, score func(order int) int) error {
+    slog.Debug("Saving correct answer", "round_id", answer.RoundID, "player", answer.PlayerID)
+    return r.db.Transaction(func(tx *gorm.DB) error {
+        var answered int64
+        err := tx.Model(&models.PlayerAnswer{}).
+            Where("round_id = ? AND player_id = ? AND answer_order > 0", answer.RoundID, answer.PlayerID).
+            Count(&answered).Error
+        if err != nil {
+            return err
+        }
+        if answered > 0 {
+            return ErrAlreadyAnswered
+        }
+
+        err = tx.Model(&models.GameRound{}).
+            Where("id = ?", answer.RoundID).
+            UpdateColumn("answer_count", gorm.Expr("answer_count + 1")).Error
+        if err != nil {
+            return err
+        }
+        var order int
+        err = tx.Model(&models.GameRound{}).
+            Where("id = ?", answer.RoundID).
+            Pluck("answer_count", &order).Error
+        if err != nil {
+            return err
+        }
+
+        answer.AnswerOrder = order
+        answer.Score = score(order)
+        err = tx.Create(answer).Error
+        if err != nil && strings.Contains(strings.ToLower(err.Error()), "unique constraint") {
+            return ErrAlreadyAnswered
+        }
+        return err
+    })
 }
 
 // UpdateRoundState updates the state of a round
 func (r *GameRoundRepository) UpdateRoundState(roundID string, state string) error {
-    log.Printf("Updating round %s state to %s", roundID, state)
+    slog.Debug("Updating round state", "round_id", roundID, "state", state)
     return r.db.Model(&models.GameRound{}).
         Where("id = ?", roundID).
         Update("state", state).Error
 }
 
+// PauseRound marks a round as paused
+func (r *GameRoundRepository) PauseRound(roundID string, pausedAt time.Time) error {
+    slog.Debug("Pausing round", "round_id", roundID)
+    return r.db.Model(&models.GameRound{}).
+        Where("id = ?", roundID).
+        Update("paused_at", pausedAt).Error
+}
+
+// ResumeRound clears the pause and moves the round's end time
+func (r *GameRoundRepository) ResumeRound(roundID string, endTime time.Time) error {
+    slog.Debug("Resuming round", "round_id", roundID, "end_time", endTime)
+    return r.db.Model(&models.GameRound{}).
+        Where("id = ?", roundID).
+        Updates(map[string]interface{}{
+            "end_time":  endTime,
+            "paused_at": nil,
+        }).Error
+}
+
+// VoidRound throws out a round. Its answers are kept but no longer score.
+func (r *GameRoundRepository) VoidRound(roundID string) error {
+    slog.Debug("Voiding round", "round_id", roundID)
+    return r.db.Transaction(func(tx *gorm.DB) error {
+        err := tx.Model(&models.GameRound{}).
+            Where("id = ?", roundID).
+            Updates(map[string]interface{}{
+                "state":     "voided",
+                "paused_at": nil,
+            }).Error
+        if err != nil {
+            return err
+        }
+        return tx.Model(&models.PlayerAnswer{}).
+            Where("round_id = ?", roundID).
+            Update("score", 0).Error
+    })
+}
+
 // GetRoundScores gets scores for all players in a round
 func (r *GameRoundRepository) GetRoundScores(roundID string) (map[string]int, error) {
     var answers []models.PlayerAnswer
@@ -91,7 +184,6 @@ func (r *GameRoundRepository) GetRoomRounds(roomID string) ([]models.GameRound,
         Order("round_number asc").
         Find(&rounds).Error
     if err != nil {
-        log.Printf("Error fetching rounds for room %s: %v", roomID, err)
         return nil, err
     }
     return rounds, nil
@@ -110,12 +202,12 @@ func (r *GameRoundRepository) GetPlayerAnswers(roomID string, playerID string) (
 
 // DeleteRoundAnswers deletes all player answers for a specific round
 func (r *GameRoundRepository) DeleteRoundAnswers(roundID string) error {
-    log.Printf("Deleting all player answers for round %s", roundID)
+    slog.Debug("Deleting round answers", "round_id", roundID)
     return r.db.Where("round_id = ?", roundID).Delete(&models.PlayerAnswer{}).Error
 }
 
 // DeleteRound deletes a specific game round
 func (r *GameRoundRepository) DeleteRound(roundID string) error {
-    log.Printf("Deleting round %s", roundID)
+    slog.Debug("Deleting round", "round_id", roundID)
     return r.db.Where("id = ?", roundID).Delete(&models.GameRound{}).Error
 }
\ No newline at end of file
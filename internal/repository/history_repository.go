@@ -0,0 +1,174 @@
+// internal/repository/history_repository.go
+
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/models"
+	"gorm.io/gorm"
+)
+
+type HistoryRepository struct {
+    db *Database
+}
+
+func NewHistoryRepository(db *Database) *HistoryRepository {
+    return &HistoryRepository{
+        db: db,
+    }
+}
+
+// WithContext returns the repository running its queries with ctx, so they
+// join the caller's trace
+func (r *HistoryRepository) WithContext(ctx context.Context) HistoryStore {
+    return &HistoryRepository{db: &Database{r.db.WithContext(ctx)}}
+}
+
+// SaveSession archives a finished game with its players, rounds and answers
+func (r *HistoryRepository) SaveSession(session *models.GameSession) error {
+    if err := r.db.Create(session).Error; err != nil {
+        return err
+    }
+    slog.Info("Archived game", "room", session.RoomCode, "game_id", session.ID)
+    return nil
+}
+
+// ListByRoomCode returns a page of a room's games, newest first, with
+// their final standings but without rounds
+func (r *HistoryRepository) ListByRoomCode(roomCode string, limit int, offset int) ([]models.GameSession, int64, error) {
+    var total int64
+    if err := r.db.Model(&models.GameSession{}).Where("room_code = ?", roomCode).Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    var sessions []models.GameSession
+    err := r.db.Where("room_code = ?", roomCode).
+        Preload("Players", func(db *gorm.DB) *gorm.DB {
+            return db.Order("rank asc")
+        }).
+        Order("ended_at desc").
+        Limit(limit).
+        Offset(offset).
+        Find(&sessions).Error
+    if err != nil {
+        return nil, 0, err
+    }
+    return sessions, total, nil
+}
+
+// GetSession returns an archived game with everything in it
+func (r *HistoryRepository) GetSession(id string) (*models.GameSession, error) {
+    var session models.GameSession
+    err := r.db.
+        Preload("Players", func(db *gorm.DB) *gorm.DB {
+            return db.Order("rank asc")
+        }).
+        Preload("Rounds", func(db *gorm.DB) *gorm.DB {
+            return db.Order("round_number asc, start_time asc")
+        }).
+        Preload("Rounds.Answers", func(db *gorm.DB) *gorm.DB {
+            return db.Order("answered_at asc")
+        }).
+        First(&session, "id = ?", id).Error
+    if err != nil {
+        return nil, err
+    }
+    return &session, nil
+}
+
+// GetPlayerGames returns a player's standings in every archived game,
+// oldest first
+func (r *HistoryRepository) GetPlayerGames(playerID string) ([]models.SessionPlayer, error) {
+    var players []models.SessionPlayer
+    err := r.db.
+        Joins("JOIN game_sessions ON game_sessions.id = session_players.session_id").
+        Where("session_players.player_id = ?", playerID).
+        Order("game_sessions.ended_at asc").
+        Find(&players).Error
+    return players, err
+}
+
+// GetPlayerRounds returns the counted rounds of every game a player was in,
+// in the order they were played, with only that player's answers
+func (r *HistoryRepository) GetPlayerRounds(playerID string) ([]models.SessionRound, error) {
+    var rounds []models.SessionRound
+    err := r.db.
+        Where("voided = ? AND session_id IN (?)", false,
+            r.db.Model(&models.SessionPlayer{}).Select("session_id").Where("player_id = ?", playerID)).
+        Preload("Answers", func(db *gorm.DB) *gorm.DB {
+            return db.Where("player_id = ?", playerID).Order("answered_at asc")
+        }).
+        Order("start_time asc").
+        Find(&rounds).Error
+    return rounds, err
+}
+
+// AnswerRecord is an archived answer together with the round it was given
+// in. Rounds nobody answered appear once, with the answer fields nil.
+type AnswerRecord struct {
+    QuestionID  uuid.UUID
+    RoundID     uuid.UUID
+    Question    string
+    Category    string
+    Voided      bool
+    StartTime   time.Time
+    PlayerID    *string
+    Answer      *string
+    AnswerOrder *int
+    AnsweredAt  *time.Time
+}
+
+// GetAnswerRecords returns every archived round with its answers, optionally
+// only for one category
+func (r *HistoryRepository) GetAnswerRecords(category string) ([]AnswerRecord, error) {
+    query := r.db.Table("session_rounds").
+        Select(`session_rounds.question_id, session_rounds.id AS round_id,
+            session_rounds.question, session_rounds.category, session_rounds.voided,
+            session_rounds.start_time, session_answers.player_id, session_answers.answer,
+            session_answers.answer_order, session_answers.answered_at`).
+        Joins("LEFT JOIN session_answers ON session_answers.round_id = session_rounds.id").
+        Order("session_rounds.start_time asc, session_answers.answered_at asc")
+    if category != "" {
+        query = query.Where("LOWER(session_rounds.category) = LOWER(?)", category)
+    }
+
+    var records []AnswerRecord
+    if err := query.Scan(&records).Error; err != nil {
+        return nil, err
+    }
+    return records, nil
+}
+
+// AnswerStats counts the players who answered a question in archived rounds
+// that counted, and how many of them got it right
+func (r *HistoryRepository) AnswerStats(questionID string) (attempts int64, correct int64, err error) {
+    var stats struct {
+        Attempts int64
+        Correct  int64
+    }
+    err = r.db.Raw(`
+        SELECT COUNT(*) AS attempts, COALESCE(SUM(CASE WHEN best > 0 THEN 1 ELSE 0 END), 0) AS correct
+        FROM (
+            SELECT session_answers.player_id, session_answers.round_id, MAX(session_answers.answer_order) AS best
+            FROM session_answers
+            JOIN session_rounds ON session_rounds.id = session_answers.round_id
+            WHERE session_rounds.question_id = ? AND session_rounds.voided = ?
+            GROUP BY session_answers.player_id, session_answers.round_id
+        ) AS answered`, questionID, false).Scan(&stats).Error
+    return stats.Attempts, stats.Correct, err
+}
+
+// PlayedQuestionIDs returns every question asked in an archived round that
+// counted
+func (r *HistoryRepository) PlayedQuestionIDs() ([]string, error) {
+    var ids []string
+    err := r.db.Model(&models.SessionRound{}).
+        Where("voided = ?", false).
+        Distinct().
+        Pluck("question_id", &ids).Error
+    return ids, err
+}
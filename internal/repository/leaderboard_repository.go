@@ -0,0 +1,79 @@
+// internal/repository/leaderboard_repository.go
+
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/rohan03122001/quizzing/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LeaderboardRepository struct {
+    db *Database
+}
+
+func NewLeaderboardRepository(db *Database) *LeaderboardRepository {
+    return &LeaderboardRepository{
+        db: db,
+    }
+}
+
+// WithContext returns the repository running its queries with ctx, so they
+// join the caller's trace
+func (r *LeaderboardRepository) WithContext(ctx context.Context) LeaderboardStore {
+    return &LeaderboardRepository{db: &Database{r.db.WithContext(ctx)}}
+}
+
+// AddResults adds each entry's totals onto the existing row for its board
+// and account, creating it if needed
+func (r *LeaderboardRepository) AddResults(entries []models.LeaderboardEntry) error {
+    if len(entries) == 0 {
+        return nil
+    }
+
+    slog.Debug("Updating leaderboard entries", "count", len(entries))
+    return r.db.Clauses(clause.OnConflict{
+        Columns: []clause.Column{{Name: "board"}, {Name: "account_id"}},
+        DoUpdates: clause.Assignments(map[string]interface{}{
+            "username":        gorm.Expr("excluded.username"),
+            "score":           gorm.Expr("leaderboard_entries.score + excluded.score"),
+            "games_played":    gorm.Expr("leaderboard_entries.games_played + excluded.games_played"),
+            "wins":            gorm.Expr("leaderboard_entries.wins + excluded.wins"),
+            "correct_answers": gorm.Expr("leaderboard_entries.correct_answers + excluded.correct_answers"),
+            "updated_at":      gorm.Expr("excluded.updated_at"),
+        }),
+    }).Create(&entries).Error
+}
+
+// List returns a page of a board, highest score first, and its size
+func (r *LeaderboardRepository) List(board string, limit int, offset int) ([]models.LeaderboardEntry, int64, error) {
+    var total int64
+    if err := r.db.Model(&models.LeaderboardEntry{}).Where("board = ?", board).Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    var entries []models.LeaderboardEntry
+    err := r.db.Where("board = ?", board).
+        Order("score desc, wins desc, username asc").
+        Limit(limit).
+        Offset(offset).
+        Find(&entries).Error
+    if err != nil {
+        return nil, 0, err
+    }
+    return entries, total, nil
+}
+
+// Categories returns the categories that have a leaderboard
+func (r *LeaderboardRepository) Categories() ([]string, error) {
+    var boards []string
+    err := r.db.Model(&models.LeaderboardEntry{}).
+        Where("board LIKE ?", "category:%").
+        Distinct().
+        Order("board asc").
+        Pluck("board", &boards).Error
+    return boards, err
+}
@@ -0,0 +1,64 @@
+// internal/repository/logger.go
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Queries slower than this are logged as warnings
+const slowQueryThreshold = 200 * time.Millisecond
+
+// gormLogger sends GORM's logs to slog. Every statement is logged at debug
+// level, slow ones as warnings and failed ones as errors. A lookup that
+// finds nothing isn't a failure.
+type gormLogger struct{}
+
+func (l gormLogger) LogMode(logger.LogLevel) logger.Interface {
+    // The level is slog's
+    return l
+}
+
+func (gormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+    slog.InfoContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (gormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+    slog.WarnContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (gormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+    slog.ErrorContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+    elapsed := time.Since(begin)
+    level := slog.LevelDebug
+    switch {
+    case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+        level = slog.LevelError
+    case elapsed > slowQueryThreshold:
+        level = slog.LevelWarn
+    }
+    if !slog.Default().Enabled(ctx, level) {
+        return
+    }
+
+    sql, rows := fc()
+    attrs := []interface{}{"sql", sql, "rows", rows, "elapsed", elapsed}
+    switch level {
+    case slog.LevelError:
+        slog.ErrorContext(ctx, "Query failed", append(attrs, "err", err)...)
+    case slog.LevelWarn:
+        slog.WarnContext(ctx, "Slow query", attrs...)
+    default:
+        slog.DebugContext(ctx, "Query", attrs...)
+    }
+}
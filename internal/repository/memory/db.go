@@ -0,0 +1,84 @@
+// internal/repository/memory/db.go
+
+// Package memory keeps the game's data in memory, for tests and local runs
+// without Postgres. Its repositories implement the interfaces in the
+// repository package with the same semantics as the GORM ones.
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Database holds the tables. Repositories created with the same Database
+// see each other's changes, as they would sharing a real one.
+type Database struct {
+    mu sync.RWMutex
+
+    // Rows in insertion order
+    rooms       []*models.Room
+    questions   []*models.Question
+    rounds      []*models.GameRound
+    answers     []*models.PlayerAnswer
+    sessions    []*models.GameSession
+    leaderboard []*models.LeaderboardEntry
+}
+
+func NewDatabase() *Database {
+    return &Database{}
+}
+
+// Lookups that find nothing fail like GORM's First
+var errNotFound = gorm.ErrRecordNotFound
+
+var (
+    _ repository.RoomStore        = (*RoomRepository)(nil)
+    _ repository.QuestionStore    = (*QuestionRepository)(nil)
+    _ repository.RoundStore       = (*GameRoundRepository)(nil)
+    _ repository.HistoryStore     = (*HistoryRepository)(nil)
+    _ repository.LeaderboardStore = (*LeaderboardRepository)(nil)
+)
+
+// newID fills in a missing primary key, as the models' BeforeCreate hooks do
+func newID(id *uuid.UUID) {
+    if *id == uuid.Nil {
+        *id = uuid.New()
+    }
+}
+
+// stamp sets a missing timestamp, as GORM does for CreatedAt and UpdatedAt
+func stamp(t *time.Time, now time.Time) {
+    if t.IsZero() {
+        *t = now
+    }
+}
+
+func copyTime(t *time.Time) *time.Time {
+    if t == nil {
+        return nil
+    }
+    copied := *t
+    return &copied
+}
+
+func copyUUID(id *uuid.UUID) *uuid.UUID {
+    if id == nil {
+        return nil
+    }
+    copied := *id
+    return &copied
+}
+
+// parseID parses a key given as a string. An invalid one matches nothing.
+func parseID(id string) uuid.UUID {
+    parsed, err := uuid.Parse(id)
+    if err != nil {
+        return uuid.Nil
+    }
+    return parsed
+}
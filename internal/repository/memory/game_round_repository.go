@@ -0,0 +1,282 @@
+// internal/repository/memory/game_round_repository.go
+
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/repository"
+)
+
+type GameRoundRepository struct {
+    db *Database
+}
+
+func NewGameRoundRepository(db *Database) *GameRoundRepository {
+    return &GameRoundRepository{
+        db: db,
+    }
+}
+
+// WithContext returns the repository itself; it makes no queries to trace
+func (r *GameRoundRepository) WithContext(ctx context.Context) repository.RoundStore {
+    return r
+}
+
+func copyRound(round *models.GameRound) *models.GameRound {
+    copied := *round
+    copied.PausedAt = copyTime(round.PausedAt)
+    return &copied
+}
+
+// update changes a round if it exists, like an UPDATE matching no rows
+func (r *GameRoundRepository) update(roundID string, change func(round *models.GameRound)) error {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    id := parseID(roundID)
+    for _, round := range r.db.rounds {
+        if round.ID == id {
+            change(round)
+        }
+    }
+    return nil
+}
+
+// answersOf returns copies of a round's answers, in the order they were
+// saved. Must be called with the lock held.
+func (r *GameRoundRepository) answersOf(roundID string) []models.PlayerAnswer {
+    id := parseID(roundID)
+    answers := []models.PlayerAnswer{}
+    for _, answer := range r.db.answers {
+        if answer.RoundID == id {
+            answers = append(answers, *answer)
+        }
+    }
+    return answers
+}
+
+// CreateRound starts a new round
+func (r *GameRoundRepository) CreateRound(round *models.GameRound) error {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    newID(&round.ID)
+    if round.State == "" {
+        round.State = "waiting"
+    }
+    r.db.rounds = append(r.db.rounds, copyRound(round))
+    return nil
+}
+
+// GetCurrentRound gets the active round for a room
+func (r *GameRoundRepository) GetCurrentRound(roomID string) (*models.GameRound, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    id := parseID(roomID)
+    for _, round := range r.db.rounds {
+        if round.RoomID == id && round.State == "active" {
+            return copyRound(round), nil
+        }
+    }
+    return nil, errNotFound
+}
+
+// SaveAnswer records a player's answer
+func (r *GameRoundRepository) SaveAnswer(answer *models.PlayerAnswer) error {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    newID(&answer.ID)
+    copied := *answer
+    r.db.answers = append(r.db.answers, &copied)
+    return nil
+}
+
+// HasCorrectAnswer checks if a player already answered a round correctly
+func (r *GameRoundRepository) HasCorrectAnswer(roundID string, playerID string) (bool, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    for _, answer := range r.answersOf(roundID) {
+        if answer.PlayerID == playerID && answer.AnswerOrder > 0 {
+            return true, nil
+        }
+    }
+    return false, nil
+}
+
+// GetRoundAnswers gets all answers for a round
+func (r *GameRoundRepository) GetRoundAnswers(roundID string) ([]models.PlayerAnswer, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    answers := r.answersOf(roundID)
+    sort.SliceStable(answers, func(i, j int) bool {
+        return answers[i].AnswerOrder < answers[j].AnswerOrder
+    })
+    return answers, nil
+}
+
+// SaveCorrectAnswer counts a correct answer and saves it with its order
+// and the score for that order, unless the player already scored in the
+// round
+func (r *GameRoundRepository) SaveCorrectAnswer(answer *models.PlayerAnswer, score func(order int) int) error {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    for _, existing := range r.answersOf(answer.RoundID.String()) {
+        if existing.PlayerID == answer.PlayerID && existing.AnswerOrder > 0 {
+            return repository.ErrAlreadyAnswered
+        }
+    }
+
+    order := 0
+    for _, round := range r.db.rounds {
+        if round.ID == answer.RoundID {
+            round.AnswerCount++
+            order = round.AnswerCount
+        }
+    }
+
+    answer.AnswerOrder = order
+    answer.Score = score(order)
+    newID(&answer.ID)
+    copied := *answer
+    r.db.answers = append(r.db.answers, &copied)
+    return nil
+}
+
+// UpdateRoundState updates the state of a round
+func (r *GameRoundRepository) UpdateRoundState(roundID string, state string) error {
+    return r.update(roundID, func(round *models.GameRound) {
+        round.State = state
+    })
+}
+
+// PauseRound marks a round as paused
+func (r *GameRoundRepository) PauseRound(roundID string, pausedAt time.Time) error {
+    return r.update(roundID, func(round *models.GameRound) {
+        round.PausedAt = &pausedAt
+    })
+}
+
+// ResumeRound clears the pause and moves the round's end time
+func (r *GameRoundRepository) ResumeRound(roundID string, endTime time.Time) error {
+    return r.update(roundID, func(round *models.GameRound) {
+        round.EndTime = endTime
+        round.PausedAt = nil
+    })
+}
+
+// VoidRound throws out a round. Its answers are kept but no longer score.
+func (r *GameRoundRepository) VoidRound(roundID string) error {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    id := parseID(roundID)
+    for _, round := range r.db.rounds {
+        if round.ID == id {
+            round.State = "voided"
+            round.PausedAt = nil
+        }
+    }
+    for _, answer := range r.db.answers {
+        if answer.RoundID == id {
+            answer.Score = 0
+        }
+    }
+    return nil
+}
+
+// GetRoundScores gets scores for all players in a round
+func (r *GameRoundRepository) GetRoundScores(roundID string) (map[string]int, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    scores := make(map[string]int)
+    for _, answer := range r.answersOf(roundID) {
+        scores[answer.PlayerID] = answer.Score
+    }
+    return scores, nil
+}
+
+// GetRoomRounds gets all rounds for a room
+func (r *GameRoundRepository) GetRoomRounds(roomID string) ([]models.GameRound, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    id := parseID(roomID)
+    rounds := []models.GameRound{}
+    for _, round := range r.db.rounds {
+        if round.RoomID == id {
+            rounds = append(rounds, *copyRound(round))
+        }
+    }
+    sort.SliceStable(rounds, func(i, j int) bool {
+        return rounds[i].RoundNumber < rounds[j].RoundNumber
+    })
+    return rounds, nil
+}
+
+// GetPlayerAnswers gets all answers for a player in a room
+func (r *GameRoundRepository) GetPlayerAnswers(roomID string, playerID string) ([]models.PlayerAnswer, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    id := parseID(roomID)
+    roundNumbers := make(map[uuid.UUID]int)
+    for _, round := range r.db.rounds {
+        if round.RoomID == id {
+            roundNumbers[round.ID] = round.RoundNumber
+        }
+    }
+
+    answers := []models.PlayerAnswer{}
+    for _, answer := range r.db.answers {
+        if _, inRoom := roundNumbers[answer.RoundID]; inRoom && answer.PlayerID == playerID {
+            answers = append(answers, *answer)
+        }
+    }
+    sort.SliceStable(answers, func(i, j int) bool {
+        return roundNumbers[answers[i].RoundID] < roundNumbers[answers[j].RoundID]
+    })
+    return answers, nil
+}
+
+// DeleteRoundAnswers deletes all player answers for a specific round
+func (r *GameRoundRepository) DeleteRoundAnswers(roundID string) error {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    id := parseID(roundID)
+    answers := r.db.answers[:0]
+    for _, answer := range r.db.answers {
+        if answer.RoundID != id {
+            answers = append(answers, answer)
+        }
+    }
+    r.db.answers = answers
+    return nil
+}
+
+// DeleteRound deletes a specific game round
+func (r *GameRoundRepository) DeleteRound(roundID string) error {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    id := parseID(roundID)
+    rounds := r.db.rounds[:0]
+    for _, round := range r.db.rounds {
+        if round.ID != id {
+            rounds = append(rounds, round)
+        }
+    }
+    r.db.rounds = rounds
+    return nil
+}
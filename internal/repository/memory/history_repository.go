@@ -0,0 +1,292 @@
+// internal/repository/memory/history_repository.go
+
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/repository"
+)
+
+type HistoryRepository struct {
+    db *Database
+}
+
+func NewHistoryRepository(db *Database) *HistoryRepository {
+    return &HistoryRepository{
+        db: db,
+    }
+}
+
+// WithContext returns the repository itself; it makes no queries to trace
+func (r *HistoryRepository) WithContext(ctx context.Context) repository.HistoryStore {
+    return r
+}
+
+func copySession(session *models.GameSession) *models.GameSession {
+    copied := *session
+    copied.Players = append([]models.SessionPlayer(nil), session.Players...)
+    for i := range copied.Players {
+        copied.Players[i].AccountID = copyUUID(session.Players[i].AccountID)
+    }
+    copied.Rounds = make([]models.SessionRound, len(session.Rounds))
+    for i, round := range session.Rounds {
+        copied.Rounds[i] = round
+        copied.Rounds[i].Answers = append([]models.SessionAnswer(nil), round.Answers...)
+    }
+    if session.Rounds == nil {
+        copied.Rounds = nil
+    }
+    return &copied
+}
+
+func sortPlayers(players []models.SessionPlayer) {
+    sort.SliceStable(players, func(i, j int) bool {
+        return players[i].Rank < players[j].Rank
+    })
+}
+
+func sortAnswers(answers []models.SessionAnswer) {
+    sort.SliceStable(answers, func(i, j int) bool {
+        return answers[i].AnsweredAt.Before(answers[j].AnsweredAt)
+    })
+}
+
+// SaveSession archives a finished game with its players, rounds and answers
+func (r *HistoryRepository) SaveSession(session *models.GameSession) error {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    // Keys are filled in on the caller's copy, as GORM does
+    newID(&session.ID)
+    for i := range session.Players {
+        newID(&session.Players[i].ID)
+        session.Players[i].SessionID = session.ID
+    }
+    for i := range session.Rounds {
+        round := &session.Rounds[i]
+        newID(&round.ID)
+        round.SessionID = session.ID
+        for j := range round.Answers {
+            newID(&round.Answers[j].ID)
+            round.Answers[j].RoundID = round.ID
+        }
+    }
+
+    r.db.sessions = append(r.db.sessions, copySession(session))
+    return nil
+}
+
+// ListByRoomCode returns a page of a room's games, newest first, with
+// their final standings but without rounds
+func (r *HistoryRepository) ListByRoomCode(roomCode string, limit int, offset int) ([]models.GameSession, int64, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    sessions := []models.GameSession{}
+    for _, stored := range r.db.sessions {
+        if stored.RoomCode != roomCode {
+            continue
+        }
+        session := copySession(stored)
+        session.Rounds = nil
+        sortPlayers(session.Players)
+        sessions = append(sessions, *session)
+    }
+    sort.SliceStable(sessions, func(i, j int) bool {
+        return sessions[i].EndedAt.After(sessions[j].EndedAt)
+    })
+    return page(sessions, limit, offset), int64(len(sessions)), nil
+}
+
+// GetSession returns an archived game with everything in it
+func (r *HistoryRepository) GetSession(id string) (*models.GameSession, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    sessionID := parseID(id)
+    for _, stored := range r.db.sessions {
+        if stored.ID != sessionID {
+            continue
+        }
+        session := copySession(stored)
+        sortPlayers(session.Players)
+        sort.SliceStable(session.Rounds, func(i, j int) bool {
+            a, b := session.Rounds[i], session.Rounds[j]
+            if a.RoundNumber != b.RoundNumber {
+                return a.RoundNumber < b.RoundNumber
+            }
+            return a.StartTime.Before(b.StartTime)
+        })
+        for i := range session.Rounds {
+            sortAnswers(session.Rounds[i].Answers)
+        }
+        return session, nil
+    }
+    return nil, errNotFound
+}
+
+// endedOrder returns the sessions sorted by when they ended. Must be
+// called with the lock held.
+func (r *HistoryRepository) endedOrder() []*models.GameSession {
+    sessions := append([]*models.GameSession(nil), r.db.sessions...)
+    sort.SliceStable(sessions, func(i, j int) bool {
+        return sessions[i].EndedAt.Before(sessions[j].EndedAt)
+    })
+    return sessions
+}
+
+// GetPlayerGames returns a player's standings in every archived game,
+// oldest first
+func (r *HistoryRepository) GetPlayerGames(playerID string) ([]models.SessionPlayer, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    players := []models.SessionPlayer{}
+    for _, session := range r.endedOrder() {
+        for _, player := range session.Players {
+            if player.PlayerID == playerID {
+                player.AccountID = copyUUID(player.AccountID)
+                players = append(players, player)
+            }
+        }
+    }
+    return players, nil
+}
+
+// GetPlayerRounds returns the counted rounds of every game a player was in,
+// in the order they were played, with only that player's answers
+func (r *HistoryRepository) GetPlayerRounds(playerID string) ([]models.SessionRound, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    rounds := []models.SessionRound{}
+    for _, session := range r.db.sessions {
+        played := false
+        for _, player := range session.Players {
+            played = played || player.PlayerID == playerID
+        }
+        if !played {
+            continue
+        }
+
+        for _, round := range session.Rounds {
+            if round.Voided {
+                continue
+            }
+            answers := []models.SessionAnswer{}
+            for _, answer := range round.Answers {
+                if answer.PlayerID == playerID {
+                    answers = append(answers, answer)
+                }
+            }
+            sortAnswers(answers)
+            round.Answers = answers
+            rounds = append(rounds, round)
+        }
+    }
+    sort.SliceStable(rounds, func(i, j int) bool {
+        return rounds[i].StartTime.Before(rounds[j].StartTime)
+    })
+    return rounds, nil
+}
+
+// GetAnswerRecords returns every archived round with its answers, optionally
+// only for one category
+func (r *HistoryRepository) GetAnswerRecords(category string) ([]repository.AnswerRecord, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    var rounds []models.SessionRound
+    for _, session := range r.db.sessions {
+        for _, round := range session.Rounds {
+            if category == "" || strings.EqualFold(round.Category, category) {
+                rounds = append(rounds, round)
+            }
+        }
+    }
+    sort.SliceStable(rounds, func(i, j int) bool {
+        return rounds[i].StartTime.Before(rounds[j].StartTime)
+    })
+
+    records := []repository.AnswerRecord{}
+    for _, round := range rounds {
+        record := repository.AnswerRecord{
+            QuestionID: round.QuestionID,
+            RoundID:    round.ID,
+            Question:   round.Question,
+            Category:   round.Category,
+            Voided:     round.Voided,
+            StartTime:  round.StartTime,
+        }
+        if len(round.Answers) == 0 {
+            records = append(records, record)
+            continue
+        }
+
+        answers := append([]models.SessionAnswer(nil), round.Answers...)
+        sortAnswers(answers)
+        for _, answer := range answers {
+            withAnswer := record
+            withAnswer.PlayerID = &answer.PlayerID
+            withAnswer.Answer = &answer.Answer
+            withAnswer.AnswerOrder = &answer.AnswerOrder
+            withAnswer.AnsweredAt = &answer.AnsweredAt
+            records = append(records, withAnswer)
+        }
+    }
+    return records, nil
+}
+
+// AnswerStats counts the players who answered a question in archived rounds
+// that counted, and how many of them got it right
+func (r *HistoryRepository) AnswerStats(questionID string) (attempts int64, correct int64, err error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    id := parseID(questionID)
+    for _, session := range r.db.sessions {
+        for _, round := range session.Rounds {
+            if round.QuestionID != id || round.Voided {
+                continue
+            }
+
+            best := make(map[string]int)
+            for _, answer := range round.Answers {
+                if order, seen := best[answer.PlayerID]; !seen || answer.AnswerOrder > order {
+                    best[answer.PlayerID] = answer.AnswerOrder
+                }
+            }
+            for _, order := range best {
+                attempts++
+                if order > 0 {
+                    correct++
+                }
+            }
+        }
+    }
+    return attempts, correct, nil
+}
+
+// PlayedQuestionIDs returns every question asked in an archived round that
+// counted
+func (r *HistoryRepository) PlayedQuestionIDs() ([]string, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    seen := make(map[uuid.UUID]bool)
+    var ids []string
+    for _, session := range r.db.sessions {
+        for _, round := range session.Rounds {
+            if !round.Voided && !seen[round.QuestionID] {
+                seen[round.QuestionID] = true
+                ids = append(ids, round.QuestionID.String())
+            }
+        }
+    }
+    return ids, nil
+}
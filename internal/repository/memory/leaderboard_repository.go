@@ -0,0 +1,98 @@
+// internal/repository/memory/leaderboard_repository.go
+
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/repository"
+)
+
+type LeaderboardRepository struct {
+    db *Database
+}
+
+func NewLeaderboardRepository(db *Database) *LeaderboardRepository {
+    return &LeaderboardRepository{
+        db: db,
+    }
+}
+
+// WithContext returns the repository itself; it makes no queries to trace
+func (r *LeaderboardRepository) WithContext(ctx context.Context) repository.LeaderboardStore {
+    return r
+}
+
+// AddResults adds each entry's totals onto the existing row for its board
+// and account, creating it if needed
+func (r *LeaderboardRepository) AddResults(entries []models.LeaderboardEntry) error {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    for _, entry := range entries {
+        var existing *models.LeaderboardEntry
+        for _, stored := range r.db.leaderboard {
+            if stored.Board == entry.Board && stored.AccountID == entry.AccountID {
+                existing = stored
+                break
+            }
+        }
+
+        if existing == nil {
+            copied := entry
+            r.db.leaderboard = append(r.db.leaderboard, &copied)
+            continue
+        }
+        existing.Username = entry.Username
+        existing.Score += entry.Score
+        existing.GamesPlayed += entry.GamesPlayed
+        existing.Wins += entry.Wins
+        existing.CorrectAnswers += entry.CorrectAnswers
+        existing.UpdatedAt = entry.UpdatedAt
+    }
+    return nil
+}
+
+// List returns a page of a board, highest score first, and its size
+func (r *LeaderboardRepository) List(board string, limit int, offset int) ([]models.LeaderboardEntry, int64, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    entries := []models.LeaderboardEntry{}
+    for _, entry := range r.db.leaderboard {
+        if entry.Board == board {
+            entries = append(entries, *entry)
+        }
+    }
+    sort.SliceStable(entries, func(i, j int) bool {
+        a, b := entries[i], entries[j]
+        if a.Score != b.Score {
+            return a.Score > b.Score
+        }
+        if a.Wins != b.Wins {
+            return a.Wins > b.Wins
+        }
+        return a.Username < b.Username
+    })
+    return page(entries, limit, offset), int64(len(entries)), nil
+}
+
+// Categories returns the categories that have a leaderboard
+func (r *LeaderboardRepository) Categories() ([]string, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    seen := make(map[string]bool)
+    boards := []string{}
+    for _, entry := range r.db.leaderboard {
+        if strings.HasPrefix(entry.Board, "category:") && !seen[entry.Board] {
+            seen[entry.Board] = true
+            boards = append(boards, entry.Board)
+        }
+    }
+    sort.Strings(boards)
+    return boards, nil
+}
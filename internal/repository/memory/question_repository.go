@@ -0,0 +1,357 @@
+// internal/repository/memory/question_repository.go
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/repository"
+)
+
+type QuestionRepository struct {
+    db *Database
+}
+
+func NewQuestionRepository(db *Database) *QuestionRepository {
+    return &QuestionRepository{
+        db: db,
+    }
+}
+
+// WithContext returns the repository itself; it makes no queries to trace
+func (r *QuestionRepository) WithContext(ctx context.Context) repository.QuestionStore {
+    return r
+}
+
+func copyQuestion(question *models.Question) *models.Question {
+    copied := *question
+    if question.Aliases != nil {
+        copied.Aliases = append(models.StringList{}, question.Aliases...)
+    }
+    copied.PackID = copyUUID(question.PackID)
+    return &copied
+}
+
+// inPool reports whether a question is in a pack, or in the shared bank if
+// packID is nil
+func inPool(question *models.Question, packID *uuid.UUID) bool {
+    if packID == nil {
+        return question.PackID == nil
+    }
+    return question.PackID != nil && *question.PackID == *packID
+}
+
+// random picks one of the stored questions that match. Must be called with
+// the lock held.
+func (r *QuestionRepository) random(match func(question *models.Question) bool) (*models.Question, error) {
+    var candidates []*models.Question
+    for _, question := range r.db.questions {
+        if match(question) {
+            candidates = append(candidates, question)
+        }
+    }
+    if len(candidates) == 0 {
+        return nil, errNotFound
+    }
+    return copyQuestion(candidates[rand.Intn(len(candidates))]), nil
+}
+
+func excluded(ids []string) map[uuid.UUID]bool {
+    set := make(map[uuid.UUID]bool, len(ids))
+    for _, id := range ids {
+        set[parseID(id)] = true
+    }
+    return set
+}
+
+// CreateQuestion adds a new question
+func (r *QuestionRepository) CreateQuestion(question *models.Question) error {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    return r.insert(question)
+}
+
+// insert stores a new question. Must be called with the lock held.
+func (r *QuestionRepository) insert(question *models.Question) error {
+    newID(&question.ID)
+    for _, existing := range r.db.questions {
+        if existing.ID == question.ID {
+            return fmt.Errorf("question %s already exists", question.ID)
+        }
+    }
+
+    now := time.Now()
+    stamp(&question.CreatedAt, now)
+    stamp(&question.UpdatedAt, now)
+    r.db.questions = append(r.db.questions, copyQuestion(question))
+    return nil
+}
+
+// GetRandom gets a random question from a pack, or from the shared bank if
+// packID is nil
+func (r *QuestionRepository) GetRandom(packID *uuid.UUID) (*models.Question, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    return r.random(func(question *models.Question) bool {
+        return inPool(question, packID)
+    })
+}
+
+// GetRandomExcluding gets a random question from a pack or the shared bank
+// that isn't one of the given IDs
+func (r *QuestionRepository) GetRandomExcluding(packID *uuid.UUID, exclude []string) (*models.Question, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    skip := excluded(exclude)
+    return r.random(func(question *models.Question) bool {
+        return inPool(question, packID) && !skip[question.ID]
+    })
+}
+
+// GetRandomOfDifficulty gets a random question from a pack or the shared
+// bank of a difficulty, 0 meaning unrated, that isn't one of the given IDs
+func (r *QuestionRepository) GetRandomOfDifficulty(packID *uuid.UUID, difficulty int, exclude []string) (*models.Question, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    skip := excluded(exclude)
+    return r.random(func(question *models.Question) bool {
+        return inPool(question, packID) && !skip[question.ID] && question.EffectiveDifficulty() == difficulty
+    })
+}
+
+// SetDerivedDifficulty stores the difficulty worked out from answers
+func (r *QuestionRepository) SetDerivedDifficulty(questionID string, difficulty int) error {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    id := parseID(questionID)
+    for _, question := range r.db.questions {
+        if question.ID == id {
+            question.DerivedDifficulty = difficulty
+        }
+    }
+    return nil
+}
+
+// GetByID gets a specific question by ID
+func (r *QuestionRepository) GetByID(id string) (*models.Question, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    questionID := parseID(id)
+    for _, question := range r.db.questions {
+        if question.ID == questionID {
+            return copyQuestion(question), nil
+        }
+    }
+    return nil, errNotFound
+}
+
+// GetByIDs gets the questions with the given IDs that still exist
+func (r *QuestionRepository) GetByIDs(ids []string) ([]models.Question, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    wanted := excluded(ids)
+    questions := []models.Question{}
+    for _, question := range r.db.questions {
+        if wanted[question.ID] {
+            questions = append(questions, *copyQuestion(question))
+        }
+    }
+    return questions, nil
+}
+
+// GetQuestionCount returns total number of questions in the shared bank
+func (r *QuestionRepository) GetQuestionCount() (int64, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    var count int64
+    for _, question := range r.db.questions {
+        if question.PackID == nil {
+            count++
+        }
+    }
+    return count, nil
+}
+
+// filtered returns the shared bank's questions matching the filter, in
+// insertion order. Must be called with the lock held.
+func (r *QuestionRepository) filtered(filter repository.QuestionFilter) []models.Question {
+    query := strings.ToLower(filter.Query)
+    questions := []models.Question{}
+    for _, question := range r.db.questions {
+        if question.PackID != nil {
+            continue
+        }
+        if query != "" {
+            aliases, _ := question.Aliases.Value()
+            if !strings.Contains(strings.ToLower(question.Content), query) &&
+                !strings.Contains(strings.ToLower(question.Answer), query) &&
+                !strings.Contains(strings.ToLower(aliases.(string)), query) {
+                continue
+            }
+        }
+        if filter.Category != "" && !strings.EqualFold(question.Category, filter.Category) {
+            continue
+        }
+        if filter.Difficulty > 0 && question.EffectiveDifficulty() != filter.Difficulty {
+            continue
+        }
+        questions = append(questions, *copyQuestion(question))
+    }
+    return questions
+}
+
+// Search returns a page of questions matching the filter, newest first,
+// and the total number of matches
+func (r *QuestionRepository) Search(filter repository.QuestionFilter, limit int, offset int) ([]models.Question, int64, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    questions := r.filtered(filter)
+    sort.SliceStable(questions, func(i, j int) bool {
+        return questions[i].CreatedAt.After(questions[j].CreatedAt)
+    })
+    return page(questions, limit, offset), int64(len(questions)), nil
+}
+
+// ListAll returns every question matching the filter, oldest first
+func (r *QuestionRepository) ListAll(filter repository.QuestionFilter) ([]models.Question, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    questions := r.filtered(filter)
+    sort.SliceStable(questions, func(i, j int) bool {
+        return questions[i].CreatedAt.Before(questions[j].CreatedAt)
+    })
+    return questions, nil
+}
+
+// UpdateQuestion saves every field of an existing question
+func (r *QuestionRepository) UpdateQuestion(question *models.Question) error {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    question.UpdatedAt = time.Now()
+    for i, existing := range r.db.questions {
+        if existing.ID == question.ID {
+            r.db.questions[i] = copyQuestion(question)
+            return nil
+        }
+    }
+    return r.insert(question)
+}
+
+// DeleteQuestion removes a question from the shared bank. It reports
+// whether there was one.
+func (r *QuestionRepository) DeleteQuestion(id string) (bool, error) {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    questionID := parseID(id)
+    for i, question := range r.db.questions {
+        if question.ID == questionID && question.PackID == nil {
+            r.db.questions = append(r.db.questions[:i], r.db.questions[i+1:]...)
+            return true, nil
+        }
+    }
+    return false, nil
+}
+
+// contentKey is how questions are compared for duplicates
+func contentKey(content string) string {
+    return strings.ToLower(strings.TrimSpace(content))
+}
+
+// FindByContent finds a question in the shared bank with the same text,
+// ignoring case and surrounding spaces
+func (r *QuestionRepository) FindByContent(content string) (*models.Question, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    key := contentKey(content)
+    for _, question := range r.db.questions {
+        if question.PackID == nil && contentKey(question.Content) == key {
+            return copyQuestion(question), nil
+        }
+    }
+    return nil, errNotFound
+}
+
+// ContentKeys returns the content of every question in the shared bank,
+// lowercased and trimmed, for spotting duplicates
+func (r *QuestionRepository) ContentKeys() (map[string]bool, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    keys := make(map[string]bool, len(r.db.questions))
+    for _, question := range r.db.questions {
+        if question.PackID == nil {
+            keys[contentKey(question.Content)] = true
+        }
+    }
+    return keys, nil
+}
+
+// CreateQuestions adds questions; either all of them are added or none
+func (r *QuestionRepository) CreateQuestions(questions []*models.Question) error {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    stored := len(r.db.questions)
+    for _, question := range questions {
+        if err := r.insert(question); err != nil {
+            r.db.questions = r.db.questions[:stored]
+            return err
+        }
+    }
+    return nil
+}
+
+// Categories returns every category in the shared bank with its number of
+// questions
+func (r *QuestionRepository) Categories() ([]repository.CategoryCount, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    counts := make(map[string]int64)
+    for _, question := range r.db.questions {
+        if question.PackID == nil {
+            counts[question.Category]++
+        }
+    }
+
+    categories := []repository.CategoryCount{}
+    for category, count := range counts {
+        categories = append(categories, repository.CategoryCount{Category: category, Count: count})
+    }
+    sort.Slice(categories, func(i, j int) bool {
+        return categories[i].Category < categories[j].Category
+    })
+    return categories, nil
+}
+
+// page applies a LIMIT and OFFSET
+func page[T any](rows []T, limit int, offset int) []T {
+    if offset > len(rows) {
+        offset = len(rows)
+    }
+    rows = rows[offset:]
+    if limit >= 0 && limit < len(rows) {
+        rows = rows[:limit]
+    }
+    return rows
+}
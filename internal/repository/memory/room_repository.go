@@ -0,0 +1,260 @@
+// internal/repository/memory/room_repository.go
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/repository"
+)
+
+type RoomRepository struct {
+    db *Database
+}
+
+func NewRoomRepository(db *Database) *RoomRepository {
+    return &RoomRepository{
+        db: db,
+    }
+}
+
+// WithContext returns the repository itself; it makes no queries to trace
+func (r *RoomRepository) WithContext(ctx context.Context) repository.RoomStore {
+    return r
+}
+
+func copyRoom(room *models.Room) *models.Room {
+    copied := *room
+    copied.PackID = copyUUID(room.PackID)
+    copied.EndedAt = copyTime(room.EndedAt)
+    copied.CheckpointedAt = copyTime(room.CheckpointedAt)
+    return &copied
+}
+
+// room finds a stored room by ID. Must be called with the lock held.
+func (r *RoomRepository) room(roomID string) *models.Room {
+    id := parseID(roomID)
+    for _, room := range r.db.rooms {
+        if room.ID == id {
+            return room
+        }
+    }
+    return nil
+}
+
+// update changes a room if it exists, like an UPDATE matching no rows
+func (r *RoomRepository) update(roomID string, change func(room *models.Room)) error {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    if room := r.room(roomID); room != nil {
+        change(room)
+    }
+    return nil
+}
+
+// CreateRoom adds a new room. Codes are unique.
+func (r *RoomRepository) CreateRoom(room *models.Room) error {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    for _, existing := range r.db.rooms {
+        if existing.Code == room.Code {
+            return fmt.Errorf("room code %s already exists", room.Code)
+        }
+    }
+
+    // Column defaults apply to fields left at their zero value
+    newID(&room.ID)
+    if room.MaxPlayers == 0 {
+        room.MaxPlayers = 10
+    }
+    if room.RoundTime == 0 {
+        room.RoundTime = 30
+    }
+    if room.MaxRounds == 0 {
+        room.MaxRounds = 2
+    }
+    if room.IntermissionTime == 0 {
+        room.IntermissionTime = 5
+    }
+    now := time.Now()
+    stamp(&room.CreatedAt, now)
+    stamp(&room.LastActivity, now)
+
+    r.db.rooms = append(r.db.rooms, copyRoom(room))
+    return nil
+}
+
+// GetByCode finds a room by its code
+func (r *RoomRepository) GetByCode(code string) (*models.Room, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    for _, room := range r.db.rooms {
+        if room.Code == code {
+            return copyRoom(room), nil
+        }
+    }
+    return nil, errNotFound
+}
+
+// UpdateStatus updates room's status
+func (r *RoomRepository) UpdateStatus(roomID string, status string) error {
+    return r.update(roomID, func(room *models.Room) {
+        room.Status = status
+    })
+}
+
+// UpdateCurrentRound increments the current round
+func (r *RoomRepository) UpdateCurrentRound(roomID string) error {
+    return r.update(roomID, func(room *models.Room) {
+        room.CurrentRound++
+    })
+}
+
+// UpdateHost sets the room's host player
+func (r *RoomRepository) UpdateHost(roomID string, playerID string) error {
+    return r.update(roomID, func(room *models.Room) {
+        room.HostID = playerID
+    })
+}
+
+// GetActive gets all rooms that are waiting or playing
+func (r *RoomRepository) GetActive() ([]models.Room, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    rooms := []models.Room{}
+    for _, room := range r.db.rooms {
+        if room.Status == "waiting" || room.Status == "playing" {
+            rooms = append(rooms, *copyRoom(room))
+        }
+    }
+    return rooms, nil
+}
+
+// CountByStatus counts the rooms in each status
+func (r *RoomRepository) CountByStatus() (map[string]int64, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    counts := make(map[string]int64)
+    for _, room := range r.db.rooms {
+        counts[room.Status]++
+    }
+    return counts, nil
+}
+
+// EndGame marks a room as finished
+func (r *RoomRepository) EndGame(roomID string) error {
+    return r.update(roomID, func(room *models.Room) {
+        now := time.Now()
+        room.Status = "finished"
+        room.EndedAt = &now
+    })
+}
+
+// UpdateRoom saves every field of a room, adding it if it's new
+func (r *RoomRepository) UpdateRoom(room *models.Room) error {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    newID(&room.ID)
+    for i, existing := range r.db.rooms {
+        if existing.ID == room.ID {
+            r.db.rooms[i] = copyRoom(room)
+            return nil
+        }
+    }
+    r.db.rooms = append(r.db.rooms, copyRoom(room))
+    return nil
+}
+
+// GetInactiveRooms gets rooms that haven't had activity recently
+func (r *RoomRepository) GetInactiveRooms(before time.Time) ([]models.Room, error) {
+    r.db.mu.RLock()
+    defer r.db.mu.RUnlock()
+
+    rooms := []models.Room{}
+    for _, room := range r.db.rooms {
+        if room.LastActivity.Before(before) && (room.Status == "waiting" || room.Status == "playing") {
+            rooms = append(rooms, *copyRoom(room))
+        }
+    }
+    return rooms, nil
+}
+
+// DeleteRoom removes a room and its rounds and answers
+func (r *RoomRepository) DeleteRoom(roomID string) error {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    id := parseID(roomID)
+    roundIDs := make(map[uuid.UUID]bool)
+    rounds := r.db.rounds[:0]
+    for _, round := range r.db.rounds {
+        if round.RoomID == id {
+            roundIDs[round.ID] = true
+            continue
+        }
+        rounds = append(rounds, round)
+    }
+    r.db.rounds = rounds
+
+    answers := r.db.answers[:0]
+    for _, answer := range r.db.answers {
+        if !roundIDs[answer.RoundID] {
+            answers = append(answers, answer)
+        }
+    }
+    r.db.answers = answers
+
+    rooms := r.db.rooms[:0]
+    for _, room := range r.db.rooms {
+        if room.ID != id {
+            rooms = append(rooms, room)
+        }
+    }
+    r.db.rooms = rooms
+    return nil
+}
+
+// UpdateLastActivity updates the room's last activity timestamp
+func (r *RoomRepository) UpdateLastActivity(roomID string) error {
+    return r.update(roomID, func(room *models.Room) {
+        room.LastActivity = time.Now()
+    })
+}
+
+// SetPack sets the question pack a room draws from, nil for the shared bank
+func (r *RoomRepository) SetPack(roomID string, packID *uuid.UUID) error {
+    return r.update(roomID, func(room *models.Room) {
+        room.PackID = copyUUID(packID)
+    })
+}
+
+// SetCheckpoint marks a game as stopped between rounds by a shutdown
+func (r *RoomRepository) SetCheckpoint(roomID string, at time.Time) error {
+    return r.update(roomID, func(room *models.Room) {
+        room.CheckpointedAt = &at
+    })
+}
+
+// ClearCheckpoint removes a room's checkpoint mark. It reports whether the
+// room had one.
+func (r *RoomRepository) ClearCheckpoint(roomID string) (bool, error) {
+    r.db.mu.Lock()
+    defer r.db.mu.Unlock()
+
+    room := r.room(roomID)
+    if room == nil || room.CheckpointedAt == nil {
+        return false, nil
+    }
+    room.CheckpointedAt = nil
+    return true, nil
+}
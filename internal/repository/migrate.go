@@ -0,0 +1,272 @@
+// internal/repository/migrate.go
+
+package repository
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Each driver has its own copy of every migration, with the same versions
+// and names, in migrations/<driver>/<version>_<name>.{up,down}.sql
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// Migration is one versioned change to the schema, with the SQL that
+// applies it and the SQL that reverts it
+type Migration struct {
+    Version int
+    Name    string
+    up      string
+    down    string
+}
+
+// MigrationState is a migration and when it was applied, if it has been
+type MigrationState struct {
+    Migration
+    AppliedAt *time.Time
+}
+
+// schemaMigration is a row of the table recording applied migrations
+type schemaMigration struct {
+    Version   int
+    Name      string
+    AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+    return "schema_migrations"
+}
+
+// Migrations lists every migration, oldest first, and whether it has been
+// applied
+func (d *Database) Migrations() ([]MigrationState, error) {
+    migrations, err := loadMigrations(d.Dialector.Name())
+    if err != nil {
+        return nil, err
+    }
+    applied, err := d.appliedMigrations()
+    if err != nil {
+        return nil, err
+    }
+
+    states := make([]MigrationState, len(migrations))
+    for i, migration := range migrations {
+        states[i].Migration = migration
+        if row, ok := applied[migration.Version]; ok {
+            appliedAt := row.AppliedAt
+            states[i].AppliedAt = &appliedAt
+        }
+    }
+    return states, nil
+}
+
+// CheckSchema returns an error unless every migration, and no other, has
+// been applied
+func (d *Database) CheckSchema() error {
+    migrations, err := loadMigrations(d.Dialector.Name())
+    if err != nil {
+        return err
+    }
+    applied, err := d.appliedMigrations()
+    if err != nil {
+        return err
+    }
+
+    known := 0
+    for _, migration := range migrations {
+        if _, ok := applied[migration.Version]; ok {
+            known++
+        }
+    }
+    if known < len(migrations) {
+        return fmt.Errorf("%d database migrations haven't been applied, run `migrate up`", len(migrations)-known)
+    }
+    if len(applied) > known {
+        return fmt.Errorf("the database has migrations this build doesn't know about")
+    }
+    return nil
+}
+
+// MigrateUp applies every pending migration, oldest first, each in its own
+// transaction. It returns the ones it applied.
+func (d *Database) MigrateUp() ([]Migration, error) {
+    states, err := d.Migrations()
+    if err != nil {
+        return nil, err
+    }
+
+    var done []Migration
+    for _, state := range states {
+        if state.AppliedAt != nil {
+            continue
+        }
+        migration := state.Migration
+        err := d.runMigration(migration.up, func(tx *gorm.DB) error {
+            return tx.Create(&schemaMigration{
+                Version:   migration.Version,
+                Name:      migration.Name,
+                AppliedAt: time.Now().UTC(),
+            }).Error
+        })
+        if err != nil {
+            return done, fmt.Errorf("migration %04d %s: %w", migration.Version, migration.Name, err)
+        }
+        slog.Info("Applied migration", "version", migration.Version, "name", migration.Name)
+        done = append(done, migration)
+    }
+    return done, nil
+}
+
+// MigrateDown reverts the latest applied migrations, up to steps of them.
+// It returns the ones it reverted.
+func (d *Database) MigrateDown(steps int) ([]Migration, error) {
+    states, err := d.Migrations()
+    if err != nil {
+        return nil, err
+    }
+
+    var done []Migration
+    for i := len(states) - 1; i >= 0 && len(done) < steps; i-- {
+        if states[i].AppliedAt == nil {
+            continue
+        }
+        migration := states[i].Migration
+        err := d.runMigration(migration.down, func(tx *gorm.DB) error {
+            return tx.Where("version = ?", migration.Version).Delete(&schemaMigration{}).Error
+        })
+        if err != nil {
+            return done, fmt.Errorf("reverting migration %04d %s: %w", migration.Version, migration.Name, err)
+        }
+        slog.Info("Reverted migration", "version", migration.Version, "name", migration.Name)
+        done = append(done, migration)
+    }
+    return done, nil
+}
+
+// runMigration runs a migration's SQL and records it in one transaction
+func (d *Database) runMigration(sql string, record func(tx *gorm.DB) error) error {
+    apply := func(tx *gorm.DB) error {
+        if err := tx.Exec(sql).Error; err != nil {
+            return err
+        }
+        return record(tx)
+    }
+
+    if d.Dialector.Name() != DriverSQLite {
+        return d.Transaction(apply)
+    }
+
+    // SQLite can only add a foreign key by rebuilding the table, and the
+    // old one can't be dropped with foreign keys on. They're switched off
+    // for the migration and checked before it commits.
+    return d.Connection(func(conn *gorm.DB) error {
+        if err := conn.Exec("PRAGMA foreign_keys = OFF").Error; err != nil {
+            return err
+        }
+        defer conn.Exec("PRAGMA foreign_keys = ON")
+
+        return conn.Transaction(func(tx *gorm.DB) error {
+            if err := apply(tx); err != nil {
+                return err
+            }
+            var violations []map[string]interface{}
+            if err := tx.Raw("PRAGMA foreign_key_check").Scan(&violations).Error; err != nil {
+                return err
+            }
+            if len(violations) > 0 {
+                return fmt.Errorf("%d rows break foreign keys, first %v", len(violations), violations[0])
+            }
+            return nil
+        })
+    })
+}
+
+// appliedMigrations reads the migrations table, creating it the first time
+func (d *Database) appliedMigrations() (map[int]schemaMigration, error) {
+    err := d.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+        version INTEGER PRIMARY KEY,
+        name TEXT NOT NULL,
+        applied_at TIMESTAMP NOT NULL
+    )`).Error
+    if err != nil {
+        return nil, err
+    }
+
+    var rows []schemaMigration
+    if err := d.Order("version").Find(&rows).Error; err != nil {
+        return nil, err
+    }
+    applied := make(map[int]schemaMigration, len(rows))
+    for _, row := range rows {
+        applied[row.Version] = row
+    }
+    return applied, nil
+}
+
+// loadMigrations reads a driver's migrations, oldest first. Versions must
+// run from 1 without gaps, each with an up and a down file.
+func loadMigrations(driver string) ([]Migration, error) {
+    dir := path.Join("migrations", driver)
+    entries, err := fs.ReadDir(migrationFiles, dir)
+    if err != nil {
+        return nil, fmt.Errorf("no migrations for database driver %q", driver)
+    }
+
+    byVersion := make(map[int]*Migration)
+    for _, entry := range entries {
+        stem, ok := strings.CutSuffix(entry.Name(), ".sql")
+        if !ok {
+            continue
+        }
+        direction := path.Ext(stem)
+        stem = strings.TrimSuffix(stem, direction)
+        number, name, _ := strings.Cut(stem, "_")
+        version, err := strconv.Atoi(number)
+        if err != nil || (direction != ".up" && direction != ".down") {
+            return nil, fmt.Errorf("bad migration file name %s", entry.Name())
+        }
+
+        data, err := fs.ReadFile(migrationFiles, path.Join(dir, entry.Name()))
+        if err != nil {
+            return nil, err
+        }
+        migration, exists := byVersion[version]
+        if !exists {
+            migration = &Migration{Version: version, Name: name}
+            byVersion[version] = migration
+        }
+        if direction == ".up" {
+            migration.up = string(data)
+        } else {
+            migration.down = string(data)
+        }
+    }
+
+    migrations := make([]Migration, 0, len(byVersion))
+    for _, migration := range byVersion {
+        migrations = append(migrations, *migration)
+    }
+    sort.Slice(migrations, func(i, j int) bool {
+        return migrations[i].Version < migrations[j].Version
+    })
+    for i, migration := range migrations {
+        if migration.Version != i+1 {
+            return nil, fmt.Errorf("migration %04d is missing", i+1)
+        }
+        if migration.up == "" || migration.down == "" {
+            return nil, fmt.Errorf("migration %04d %s needs an up and a down file", migration.Version, migration.Name)
+        }
+    }
+    return migrations, nil
+}
@@ -0,0 +1,251 @@
+// internal/repository/migrate_test.go
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/models"
+	"gorm.io/gorm"
+)
+
+var allModels = []interface{}{
+    &models.Room{},
+    &models.Account{},
+    &models.AuthToken{},
+    &models.Question{},
+    &models.QuestionPack{},
+    &models.GameRound{},
+    &models.PlayerAnswer{},
+    &models.GameSession{},
+    &models.SessionPlayer{},
+    &models.SessionRound{},
+    &models.SessionAnswer{},
+    &models.LeaderboardEntry{},
+}
+
+func openSQLite(t *testing.T) *Database {
+    t.Helper()
+    db, err := NewDatabase(&DBConfig{Driver: DriverSQLite, Path: ":memory:"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { db.Close() })
+    return db
+}
+
+func TestMigrateUpAndDown(t *testing.T) {
+    db := openSQLite(t)
+    if err := db.CheckSchema(); err == nil {
+        t.Error("an empty database should fail the schema check")
+    }
+
+    applied, err := db.MigrateUp()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(applied) < 2 {
+        t.Fatalf("applied %d migrations, want them all", len(applied))
+    }
+    if err := db.CheckSchema(); err != nil {
+        t.Errorf("got %v after migrating", err)
+    }
+    if again, err := db.MigrateUp(); err != nil || len(again) != 0 {
+        t.Errorf("migrating twice applied %d, %v", len(again), err)
+    }
+
+    reverted, err := db.MigrateDown(len(applied))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(reverted) != len(applied) || reverted[0].Version != applied[len(applied)-1].Version {
+        t.Errorf("reverted %+v, want the latest first", reverted)
+    }
+    if db.Migrator().HasTable("rooms") {
+        t.Error("reverting everything should drop the tables")
+    }
+
+    // And back up again from nothing
+    if _, err := db.MigrateUp(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+// Every model field needs a column, now nothing adds them on startup
+func TestMigrationsMatchModels(t *testing.T) {
+    db := openSQLite(t)
+    if _, err := db.MigrateUp(); err != nil {
+        t.Fatal(err)
+    }
+
+    for _, model := range allModels {
+        stmt := &gorm.Statement{DB: db.DB}
+        if err := stmt.Parse(model); err != nil {
+            t.Fatal(err)
+        }
+        if !db.Migrator().HasTable(stmt.Schema.Table) {
+            t.Errorf("no table %s", stmt.Schema.Table)
+            continue
+        }
+        for _, field := range stmt.Schema.Fields {
+            if field.DBName != "" && !db.Migrator().HasColumn(model, field.DBName) {
+                t.Errorf("no column %s.%s", stmt.Schema.Table, field.DBName)
+            }
+        }
+    }
+}
+
+// The models as they were when the schema was built by AutoMigrate on
+// startup, before there were migrations
+type (
+    baselineRoom struct {
+        ID           uuid.UUID `gorm:"type:uuid;primary_key"`
+        Code         string    `gorm:"unique;not null"`
+        Status       string    `gorm:"not null"`
+        MaxPlayers   int       `gorm:"default:10"`
+        RoundTime    int       `gorm:"default:30"`
+        MaxRounds    int       `gorm:"default:2"`
+        CurrentRound int       `gorm:"default:0"`
+        CreatedAt    time.Time
+        EndedAt      *time.Time
+        LastActivity time.Time `gorm:"not null"`
+    }
+    baselineQuestion struct {
+        ID        uuid.UUID `gorm:"type:uuid;primary_key"`
+        Content   string    `gorm:"not null"`
+        Answer    string    `gorm:"not null"`
+        CreatedAt time.Time
+    }
+    baselineGameRound struct {
+        ID          uuid.UUID `gorm:"type:uuid;primary_key"`
+        RoomID      uuid.UUID `gorm:"type:uuid;not null"`
+        QuestionID  uuid.UUID `gorm:"type:uuid;not null"`
+        StartTime   time.Time
+        EndTime     time.Time
+        RoundNumber int    `gorm:"not null"`
+        State       string `gorm:"not null;default:'waiting'"`
+        AnswerCount int    `gorm:"default:0"`
+    }
+    baselinePlayerAnswer struct {
+        ID          uuid.UUID `gorm:"type:uuid;primary_key"`
+        RoundID     uuid.UUID `gorm:"type:uuid;not null"`
+        PlayerID    string    `gorm:"not null"`
+        Answer      string    `gorm:"not null"`
+        Score       int       `gorm:"default:0"`
+        AnswerOrder int       `gorm:"not null"`
+        AnsweredAt  time.Time
+    }
+)
+
+func (baselineRoom) TableName() string         { return "rooms" }
+func (baselineQuestion) TableName() string     { return "questions" }
+func (baselineGameRound) TableName() string    { return "game_rounds" }
+func (baselinePlayerAnswer) TableName() string { return "player_answers" }
+
+// Databases AutoMigrate created before there were migrations are adopted
+// and brought up to date
+func TestMigrateAutoMigratedDatabase(t *testing.T) {
+    db := openSQLite(t)
+    if err := db.AutoMigrate(&baselineRoom{}, &baselineQuestion{}, &baselineGameRound{}, &baselinePlayerAnswer{}); err != nil {
+        t.Fatal(err)
+    }
+
+    room := &baselineRoom{ID: uuid.New(), Code: "ABC123", Status: "finished", LastActivity: time.Now()}
+    question := &baselineQuestion{ID: uuid.New(), Content: "Capital of France?", Answer: "Paris"}
+    round := &baselineGameRound{ID: uuid.New(), RoomID: room.ID, QuestionID: question.ID, RoundNumber: 1}
+    orphan := &baselineGameRound{ID: uuid.New(), RoomID: uuid.New(), QuestionID: question.ID, RoundNumber: 1}
+    for _, row := range []interface{}{room, question, round, orphan} {
+        if err := db.Create(row).Error; err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    if _, err := db.MigrateUp(); err != nil {
+        t.Fatal(err)
+    }
+    if err := db.CheckSchema(); err != nil {
+        t.Fatal(err)
+    }
+
+    // The old rows get the new columns' defaults
+    var upgraded models.Room
+    if err := db.First(&upgraded, "id = ?", room.ID).Error; err != nil {
+        t.Fatal(err)
+    }
+    if upgraded.Code != "ABC123" || upgraded.IntermissionTime != 5 || upgraded.PackID != nil {
+        t.Errorf("got room %+v after migrating", upgraded)
+    }
+    var kept models.Question
+    if err := db.First(&kept, "id = ?", question.ID).Error; err != nil {
+        t.Fatal(err)
+    }
+    if kept.Answer != "Paris" || kept.Difficulty != 0 || kept.Category != "" {
+        t.Errorf("got question %+v after migrating", kept)
+    }
+
+    var rounds []models.GameRound
+    db.Find(&rounds)
+    if len(rounds) != 1 || rounds[0].ID != round.ID {
+        t.Fatalf("got rounds %+v, want the orphan cleared out", rounds)
+    }
+
+    // Rounds now go with their room
+    if err := db.Delete(&models.Room{ID: room.ID}).Error; err != nil {
+        t.Fatal(err)
+    }
+    var count int64
+    db.Model(&models.GameRound{}).Count(&count)
+    if count != 0 {
+        t.Errorf("got %d rounds after deleting the room, want 0", count)
+    }
+
+    // And the whole way back down
+    if _, err := db.MigrateDown(100); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestMigrationsForEveryDriver(t *testing.T) {
+    postgres, err := loadMigrations(DriverPostgres)
+    if err != nil {
+        t.Fatal(err)
+    }
+    sqlite, err := loadMigrations(DriverSQLite)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if len(postgres) != len(sqlite) {
+        t.Fatalf("got %d postgres migrations and %d sqlite ones", len(postgres), len(sqlite))
+    }
+    for i := range postgres {
+        if postgres[i].Version != sqlite[i].Version || postgres[i].Name != sqlite[i].Name {
+            t.Errorf("migration %d is %04d %s on postgres but %04d %s on sqlite", i+1,
+                postgres[i].Version, postgres[i].Name, sqlite[i].Version, sqlite[i].Name)
+        }
+    }
+}
+
+// Only the indexes stop two registrations racing past the service's checks
+func TestAccountsUniqueIgnoringCase(t *testing.T) {
+    db := openSQLite(t)
+    if _, err := db.MigrateUp(); err != nil {
+        t.Fatal(err)
+    }
+    accounts := NewAccountRepository(db)
+
+    email := "alice@example.com"
+    if err := accounts.CreateAccount(&models.Account{Username: "alice", Email: &email}); err != nil {
+        t.Fatal(err)
+    }
+
+    shouted := "ALICE@example.com"
+    if err := accounts.CreateAccount(&models.Account{Username: "Alice"}); err != ErrUsernameExists {
+        t.Errorf("same username in another case: got %v, want ErrUsernameExists", err)
+    }
+    if err := accounts.CreateAccount(&models.Account{Username: "bob", Email: &shouted}); err != ErrEmailExists {
+        t.Errorf("same email in another case: got %v, want ErrEmailExists", err)
+    }
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS player_answers;
+DROP TABLE IF EXISTS game_rounds;
+DROP TABLE IF EXISTS questions;
+DROP TABLE IF EXISTS rooms;
@@ -0,0 +1,49 @@
+-- The schema as AutoMigrate created it before there were migrations. IF
+-- NOT EXISTS lets databases created that way adopt it as they are, and
+-- the migrations after this one bring them up to date.
+
+CREATE TABLE IF NOT EXISTS rooms (
+    id uuid,
+    code text NOT NULL,
+    status text NOT NULL,
+    max_players bigint DEFAULT 10,
+    round_time bigint DEFAULT 30,
+    max_rounds bigint DEFAULT 2,
+    current_round bigint DEFAULT 0,
+    created_at timestamptz,
+    ended_at timestamptz,
+    last_activity timestamptz NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT uni_rooms_code UNIQUE (code)
+);
+
+CREATE TABLE IF NOT EXISTS questions (
+    id uuid,
+    content text NOT NULL,
+    answer text NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS game_rounds (
+    id uuid,
+    room_id uuid NOT NULL,
+    question_id uuid NOT NULL,
+    start_time timestamptz,
+    end_time timestamptz,
+    round_number bigint NOT NULL,
+    state text NOT NULL DEFAULT 'waiting',
+    answer_count bigint DEFAULT 0,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS player_answers (
+    id uuid,
+    round_id uuid NOT NULL,
+    player_id text NOT NULL,
+    answer text NOT NULL,
+    score bigint DEFAULT 0,
+    answer_order bigint NOT NULL,
+    answered_at timestamptz,
+    PRIMARY KEY (id)
+);
@@ -0,0 +1,8 @@
+ALTER TABLE game_rounds DROP COLUMN paused_at;
+
+ALTER TABLE rooms
+    DROP COLUMN host_id,
+    DROP COLUMN difficulty_ramp,
+    DROP COLUMN ready_check,
+    DROP COLUMN intermission_time,
+    DROP COLUMN timer_updates;
@@ -0,0 +1,9 @@
+-- Room options, the host, and pausing
+ALTER TABLE rooms
+    ADD COLUMN timer_updates boolean DEFAULT false,
+    ADD COLUMN intermission_time bigint DEFAULT 5,
+    ADD COLUMN ready_check boolean DEFAULT false,
+    ADD COLUMN difficulty_ramp boolean DEFAULT false,
+    ADD COLUMN host_id text;
+
+ALTER TABLE game_rounds ADD COLUMN paused_at timestamptz;
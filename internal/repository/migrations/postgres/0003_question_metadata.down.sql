@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_questions_difficulty;
+DROP INDEX IF EXISTS idx_questions_derived_difficulty;
+DROP INDEX IF EXISTS idx_questions_category;
+
+ALTER TABLE questions
+    DROP COLUMN updated_at,
+    DROP COLUMN media_type,
+    DROP COLUMN media_url,
+    DROP COLUMN derived_difficulty,
+    DROP COLUMN difficulty,
+    DROP COLUMN category,
+    DROP COLUMN aliases;
@@ -0,0 +1,25 @@
+-- Some deployments added category and image_url by hand to load the seed
+-- files, hence IF NOT EXISTS
+ALTER TABLE questions
+    ADD COLUMN IF NOT EXISTS aliases text,
+    ADD COLUMN IF NOT EXISTS category text,
+    ADD COLUMN IF NOT EXISTS difficulty bigint DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS derived_difficulty bigint DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS media_url text,
+    ADD COLUMN IF NOT EXISTS media_type text,
+    ADD COLUMN IF NOT EXISTS updated_at timestamptz;
+
+-- Images loaded into image_url move to media_url. The old column is left
+-- as it is.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+            WHERE table_schema = current_schema() AND table_name = 'questions' AND column_name = 'image_url') THEN
+        UPDATE questions SET media_url = image_url, media_type = 'image'
+            WHERE COALESCE(media_url, '') = '' AND COALESCE(image_url, '') <> '';
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_questions_category ON questions (category);
+CREATE INDEX IF NOT EXISTS idx_questions_derived_difficulty ON questions (derived_difficulty);
+CREATE INDEX IF NOT EXISTS idx_questions_difficulty ON questions (difficulty);
@@ -0,0 +1,73 @@
+-- Archived games and the leaderboards built from them
+
+CREATE TABLE game_sessions (
+    id uuid,
+    room_id uuid,
+    room_code text NOT NULL,
+    started_at timestamptz,
+    ended_at timestamptz,
+    total_rounds bigint,
+    player_count bigint,
+    PRIMARY KEY (id)
+);
+CREATE INDEX idx_game_sessions_ended_at ON game_sessions (ended_at);
+CREATE INDEX idx_game_sessions_room_code ON game_sessions (room_code);
+CREATE INDEX idx_game_sessions_room_id ON game_sessions (room_id);
+
+CREATE TABLE session_players (
+    id uuid,
+    session_id uuid NOT NULL,
+    player_id text NOT NULL,
+    account_id uuid,
+    username text,
+    total_score bigint,
+    rank bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_game_sessions_players FOREIGN KEY (session_id) REFERENCES game_sessions (id)
+);
+CREATE INDEX idx_session_players_player_id ON session_players (player_id);
+CREATE INDEX idx_session_players_session_id ON session_players (session_id);
+CREATE INDEX idx_session_players_account_id ON session_players (account_id);
+
+CREATE TABLE session_rounds (
+    id uuid,
+    session_id uuid NOT NULL,
+    round_number bigint NOT NULL,
+    question_id uuid,
+    question text,
+    answer text,
+    category text,
+    voided boolean,
+    start_time timestamptz,
+    end_time timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_game_sessions_rounds FOREIGN KEY (session_id) REFERENCES game_sessions (id)
+);
+CREATE INDEX idx_session_rounds_session_id ON session_rounds (session_id);
+
+CREATE TABLE session_answers (
+    id uuid,
+    round_id uuid NOT NULL,
+    player_id text NOT NULL,
+    answer text,
+    score bigint,
+    answer_order bigint,
+    answered_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_session_rounds_answers FOREIGN KEY (round_id) REFERENCES session_rounds (id)
+);
+CREATE INDEX idx_session_answers_player_id ON session_answers (player_id);
+CREATE INDEX idx_session_answers_round_id ON session_answers (round_id);
+
+CREATE TABLE leaderboard_entries (
+    board text,
+    account_id uuid,
+    username text,
+    score bigint,
+    games_played bigint,
+    wins bigint,
+    correct_answers bigint,
+    updated_at timestamptz,
+    PRIMARY KEY (board, account_id)
+);
+CREATE INDEX idx_leaderboard_rank ON leaderboard_entries (board, score DESC);
@@ -0,0 +1,7 @@
+DROP INDEX idx_questions_pack_id;
+ALTER TABLE questions DROP COLUMN pack_id;
+
+DROP INDEX idx_rooms_pack_id;
+ALTER TABLE rooms DROP COLUMN pack_id;
+
+DROP TABLE question_packs;
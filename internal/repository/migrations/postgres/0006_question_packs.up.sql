@@ -0,0 +1,18 @@
+CREATE TABLE question_packs (
+    id uuid,
+    owner_id uuid NOT NULL,
+    name text NOT NULL,
+    description text,
+    question_count bigint,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX idx_question_packs_owner_id ON question_packs (owner_id);
+
+ALTER TABLE rooms ADD COLUMN pack_id uuid;
+CREATE INDEX idx_rooms_pack_id ON rooms (pack_id);
+
+ALTER TABLE questions ADD COLUMN pack_id uuid
+    CONSTRAINT fk_question_packs_questions REFERENCES question_packs (id);
+CREATE INDEX idx_questions_pack_id ON questions (pack_id);
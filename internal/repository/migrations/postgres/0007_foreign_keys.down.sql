@@ -0,0 +1,10 @@
+DROP INDEX idx_player_answers_round_id;
+DROP INDEX idx_game_rounds_question_id;
+DROP INDEX idx_game_rounds_room_state;
+DROP INDEX idx_rooms_status;
+
+ALTER TABLE rooms DROP CONSTRAINT fk_rooms_pack;
+ALTER TABLE question_packs DROP CONSTRAINT fk_question_packs_owner;
+ALTER TABLE auth_tokens DROP CONSTRAINT fk_auth_tokens_account;
+ALTER TABLE player_answers DROP CONSTRAINT fk_player_answers_round;
+ALTER TABLE game_rounds DROP CONSTRAINT fk_game_rounds_room;
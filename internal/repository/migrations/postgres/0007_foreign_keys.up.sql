@@ -0,0 +1,23 @@
+-- Clear out rows left behind before there were constraints to stop them
+DELETE FROM game_rounds WHERE room_id NOT IN (SELECT id FROM rooms);
+DELETE FROM player_answers WHERE round_id NOT IN (SELECT id FROM game_rounds);
+DELETE FROM auth_tokens WHERE account_id NOT IN (SELECT id FROM accounts);
+UPDATE rooms SET pack_id = NULL WHERE pack_id NOT IN (SELECT id FROM question_packs);
+
+-- Rounds don't reference their question: questions can be deleted, and
+-- archived games keep their own copy
+ALTER TABLE game_rounds ADD CONSTRAINT fk_game_rounds_room
+    FOREIGN KEY (room_id) REFERENCES rooms (id) ON DELETE CASCADE;
+ALTER TABLE player_answers ADD CONSTRAINT fk_player_answers_round
+    FOREIGN KEY (round_id) REFERENCES game_rounds (id) ON DELETE CASCADE;
+ALTER TABLE auth_tokens ADD CONSTRAINT fk_auth_tokens_account
+    FOREIGN KEY (account_id) REFERENCES accounts (id) ON DELETE CASCADE;
+ALTER TABLE question_packs ADD CONSTRAINT fk_question_packs_owner
+    FOREIGN KEY (owner_id) REFERENCES accounts (id);
+ALTER TABLE rooms ADD CONSTRAINT fk_rooms_pack
+    FOREIGN KEY (pack_id) REFERENCES question_packs (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_rooms_status ON rooms (status);
+CREATE INDEX idx_game_rounds_room_state ON game_rounds (room_id, state);
+CREATE INDEX idx_game_rounds_question_id ON game_rounds (question_id);
+CREATE INDEX idx_player_answers_round_id ON player_answers (round_id);
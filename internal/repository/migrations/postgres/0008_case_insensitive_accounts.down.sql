@@ -0,0 +1,2 @@
+DROP INDEX idx_accounts_lower_email;
+DROP INDEX idx_accounts_lower_username;
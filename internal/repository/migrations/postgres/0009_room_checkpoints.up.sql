@@ -0,0 +1,3 @@
+-- Set when a shutdown stops a game between rounds, so only those games
+-- start their next round on resume_game
+ALTER TABLE rooms ADD COLUMN checkpointed_at timestamptz;
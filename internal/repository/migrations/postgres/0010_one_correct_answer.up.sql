@@ -0,0 +1,5 @@
+-- A player scores at most once per round. Answering checks first, but only
+-- this stops two submissions racing each other. This fails if a player
+-- already has two correct answers in a round; delete the later ones first.
+CREATE UNIQUE INDEX idx_player_answers_one_correct ON player_answers (round_id, player_id)
+    WHERE answer_order > 0;
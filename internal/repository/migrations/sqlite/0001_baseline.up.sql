@@ -0,0 +1,49 @@
+-- The schema as AutoMigrate created it before there were migrations, in
+-- SQLite's types. IF NOT EXISTS lets databases created that way adopt it
+-- as they are, and the migrations after this one bring them up to date.
+
+CREATE TABLE IF NOT EXISTS rooms (
+    id uuid,
+    code text NOT NULL,
+    status text NOT NULL,
+    max_players integer DEFAULT 10,
+    round_time integer DEFAULT 30,
+    max_rounds integer DEFAULT 2,
+    current_round integer DEFAULT 0,
+    created_at datetime,
+    ended_at datetime,
+    last_activity datetime NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT uni_rooms_code UNIQUE (code)
+);
+
+CREATE TABLE IF NOT EXISTS questions (
+    id uuid,
+    content text NOT NULL,
+    answer text NOT NULL,
+    created_at datetime,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS game_rounds (
+    id uuid,
+    room_id uuid NOT NULL,
+    question_id uuid NOT NULL,
+    start_time datetime,
+    end_time datetime,
+    round_number integer NOT NULL,
+    state text NOT NULL DEFAULT 'waiting',
+    answer_count integer DEFAULT 0,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS player_answers (
+    id uuid,
+    round_id uuid NOT NULL,
+    player_id text NOT NULL,
+    answer text NOT NULL,
+    score integer DEFAULT 0,
+    answer_order integer NOT NULL,
+    answered_at datetime,
+    PRIMARY KEY (id)
+);
@@ -0,0 +1,7 @@
+ALTER TABLE game_rounds DROP COLUMN paused_at;
+
+ALTER TABLE rooms DROP COLUMN host_id;
+ALTER TABLE rooms DROP COLUMN difficulty_ramp;
+ALTER TABLE rooms DROP COLUMN ready_check;
+ALTER TABLE rooms DROP COLUMN intermission_time;
+ALTER TABLE rooms DROP COLUMN timer_updates;
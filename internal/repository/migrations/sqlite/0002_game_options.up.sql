@@ -0,0 +1,8 @@
+-- Room options, the host, and pausing
+ALTER TABLE rooms ADD COLUMN timer_updates numeric DEFAULT false;
+ALTER TABLE rooms ADD COLUMN intermission_time integer DEFAULT 5;
+ALTER TABLE rooms ADD COLUMN ready_check numeric DEFAULT false;
+ALTER TABLE rooms ADD COLUMN difficulty_ramp numeric DEFAULT false;
+ALTER TABLE rooms ADD COLUMN host_id text;
+
+ALTER TABLE game_rounds ADD COLUMN paused_at datetime;
@@ -0,0 +1,11 @@
+DROP INDEX idx_questions_difficulty;
+DROP INDEX idx_questions_derived_difficulty;
+DROP INDEX idx_questions_category;
+
+ALTER TABLE questions DROP COLUMN updated_at;
+ALTER TABLE questions DROP COLUMN media_type;
+ALTER TABLE questions DROP COLUMN media_url;
+ALTER TABLE questions DROP COLUMN derived_difficulty;
+ALTER TABLE questions DROP COLUMN difficulty;
+ALTER TABLE questions DROP COLUMN category;
+ALTER TABLE questions DROP COLUMN aliases;
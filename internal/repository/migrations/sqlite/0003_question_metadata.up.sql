@@ -0,0 +1,13 @@
+-- SQLite databases never had the hand-added image_url column Postgres
+-- ones may have, so there's nothing to move to media_url
+ALTER TABLE questions ADD COLUMN aliases text;
+ALTER TABLE questions ADD COLUMN category text;
+ALTER TABLE questions ADD COLUMN difficulty integer DEFAULT 0;
+ALTER TABLE questions ADD COLUMN derived_difficulty integer DEFAULT 0;
+ALTER TABLE questions ADD COLUMN media_url text;
+ALTER TABLE questions ADD COLUMN media_type text;
+ALTER TABLE questions ADD COLUMN updated_at datetime;
+
+CREATE INDEX idx_questions_category ON questions (category);
+CREATE INDEX idx_questions_derived_difficulty ON questions (derived_difficulty);
+CREATE INDEX idx_questions_difficulty ON questions (difficulty);
@@ -0,0 +1,25 @@
+CREATE TABLE accounts (
+    id uuid,
+    username text NOT NULL,
+    email text,
+    password_hash text,
+    banned_at datetime,
+    ban_reason text,
+    created_at datetime,
+    last_login_at datetime,
+    is_admin numeric DEFAULT false,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX idx_accounts_email ON accounts (email);
+CREATE UNIQUE INDEX idx_accounts_username ON accounts (username);
+
+CREATE TABLE auth_tokens (
+    token_hash text,
+    account_id uuid NOT NULL,
+    kind text NOT NULL,
+    expires_at datetime NOT NULL,
+    created_at datetime,
+    PRIMARY KEY (token_hash)
+);
+CREATE INDEX idx_auth_tokens_expires_at ON auth_tokens (expires_at);
+CREATE INDEX idx_auth_tokens_account_id ON auth_tokens (account_id);
@@ -0,0 +1,5 @@
+DROP TABLE leaderboard_entries;
+DROP TABLE session_answers;
+DROP TABLE session_rounds;
+DROP TABLE session_players;
+DROP TABLE game_sessions;
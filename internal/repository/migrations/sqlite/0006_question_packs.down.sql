@@ -0,0 +1,32 @@
+-- SQLite can't drop a column with a foreign key, so questions is rebuilt
+-- without pack_id
+
+CREATE TABLE questions_new (
+    id uuid,
+    content text NOT NULL,
+    answer text NOT NULL,
+    created_at datetime,
+    aliases text,
+    category text,
+    difficulty integer DEFAULT 0,
+    derived_difficulty integer DEFAULT 0,
+    media_url text,
+    media_type text,
+    updated_at datetime,
+    PRIMARY KEY (id)
+);
+INSERT INTO questions_new (id, content, answer, created_at, aliases, category, difficulty,
+        derived_difficulty, media_url, media_type, updated_at)
+    SELECT id, content, answer, created_at, aliases, category, difficulty,
+        derived_difficulty, media_url, media_type, updated_at
+    FROM questions;
+DROP TABLE questions;
+ALTER TABLE questions_new RENAME TO questions;
+CREATE INDEX idx_questions_category ON questions (category);
+CREATE INDEX idx_questions_derived_difficulty ON questions (derived_difficulty);
+CREATE INDEX idx_questions_difficulty ON questions (difficulty);
+
+DROP INDEX idx_rooms_pack_id;
+ALTER TABLE rooms DROP COLUMN pack_id;
+
+DROP TABLE question_packs;
@@ -0,0 +1,98 @@
+-- Rebuilds the tables without their foreign keys, as the earlier migrations left them
+
+DROP INDEX idx_player_answers_round_id;
+DROP INDEX idx_game_rounds_question_id;
+DROP INDEX idx_game_rounds_room_state;
+DROP INDEX idx_rooms_status;
+
+CREATE TABLE rooms_new (
+    id uuid,
+    code text NOT NULL,
+    status text NOT NULL,
+    max_players integer DEFAULT 10,
+    round_time integer DEFAULT 30,
+    max_rounds integer DEFAULT 2,
+    current_round integer DEFAULT 0,
+    timer_updates numeric DEFAULT false,
+    intermission_time integer DEFAULT 5,
+    ready_check numeric DEFAULT false,
+    difficulty_ramp numeric DEFAULT false,
+    pack_id uuid,
+    host_id text,
+    created_at datetime,
+    ended_at datetime,
+    last_activity datetime NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT uni_rooms_code UNIQUE (code)
+);
+INSERT INTO rooms_new (id, code, status, max_players, round_time, max_rounds, current_round, timer_updates,
+        intermission_time, ready_check, difficulty_ramp, pack_id, host_id, created_at, ended_at, last_activity)
+    SELECT id, code, status, max_players, round_time, max_rounds, current_round, timer_updates,
+        intermission_time, ready_check, difficulty_ramp, pack_id, host_id, created_at, ended_at, last_activity
+    FROM rooms;
+DROP TABLE rooms;
+ALTER TABLE rooms_new RENAME TO rooms;
+CREATE INDEX idx_rooms_pack_id ON rooms (pack_id);
+
+CREATE TABLE question_packs_new (
+    id uuid,
+    owner_id uuid NOT NULL,
+    name text NOT NULL,
+    description text,
+    question_count integer,
+    created_at datetime,
+    updated_at datetime,
+    PRIMARY KEY (id)
+);
+INSERT INTO question_packs_new (id, owner_id, name, description, question_count, created_at, updated_at)
+    SELECT id, owner_id, name, description, question_count, created_at, updated_at FROM question_packs;
+DROP TABLE question_packs;
+ALTER TABLE question_packs_new RENAME TO question_packs;
+CREATE INDEX idx_question_packs_owner_id ON question_packs (owner_id);
+
+CREATE TABLE auth_tokens_new (
+    token_hash text,
+    account_id uuid NOT NULL,
+    kind text NOT NULL,
+    expires_at datetime NOT NULL,
+    created_at datetime,
+    PRIMARY KEY (token_hash)
+);
+INSERT INTO auth_tokens_new (token_hash, account_id, kind, expires_at, created_at)
+    SELECT token_hash, account_id, kind, expires_at, created_at FROM auth_tokens;
+DROP TABLE auth_tokens;
+ALTER TABLE auth_tokens_new RENAME TO auth_tokens;
+CREATE INDEX idx_auth_tokens_expires_at ON auth_tokens (expires_at);
+CREATE INDEX idx_auth_tokens_account_id ON auth_tokens (account_id);
+
+CREATE TABLE game_rounds_new (
+    id uuid,
+    room_id uuid NOT NULL,
+    question_id uuid NOT NULL,
+    start_time datetime,
+    end_time datetime,
+    round_number integer NOT NULL,
+    state text NOT NULL DEFAULT 'waiting',
+    answer_count integer DEFAULT 0,
+    paused_at datetime,
+    PRIMARY KEY (id)
+);
+INSERT INTO game_rounds_new (id, room_id, question_id, start_time, end_time, round_number, state, answer_count, paused_at)
+    SELECT id, room_id, question_id, start_time, end_time, round_number, state, answer_count, paused_at FROM game_rounds;
+DROP TABLE game_rounds;
+ALTER TABLE game_rounds_new RENAME TO game_rounds;
+
+CREATE TABLE player_answers_new (
+    id uuid,
+    round_id uuid NOT NULL,
+    player_id text NOT NULL,
+    answer text NOT NULL,
+    score integer DEFAULT 0,
+    answer_order integer NOT NULL,
+    answered_at datetime,
+    PRIMARY KEY (id)
+);
+INSERT INTO player_answers_new (id, round_id, player_id, answer, score, answer_order, answered_at)
+    SELECT id, round_id, player_id, answer, score, answer_order, answered_at FROM player_answers;
+DROP TABLE player_answers;
+ALTER TABLE player_answers_new RENAME TO player_answers;
@@ -0,0 +1,6 @@
+-- Usernames and emails are looked up ignoring case, so two accounts mustn't
+-- differ only by case. Registration checks first, but only these stop two
+-- sign-ups racing each other. This fails if such accounts already exist;
+-- rename or merge them first.
+CREATE UNIQUE INDEX idx_accounts_lower_username ON accounts (LOWER(username));
+CREATE UNIQUE INDEX idx_accounts_lower_email ON accounts (LOWER(email));
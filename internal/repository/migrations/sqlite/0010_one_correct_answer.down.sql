@@ -0,0 +1 @@
+DROP INDEX idx_player_answers_one_correct;
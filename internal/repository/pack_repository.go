@@ -0,0 +1,123 @@
+// internal/repository/pack_repository.go
+
+package repository
+
+import (
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/models"
+	"gorm.io/gorm"
+)
+
+type PackRepository struct {
+    db *Database
+}
+
+func NewPackRepository(db *Database) *PackRepository {
+    return &PackRepository{
+        db: db,
+    }
+}
+
+// CreatePack adds a pack with its questions in a single transaction
+func (r *PackRepository) CreatePack(pack *models.QuestionPack, questions []*models.Question) error {
+    err := r.db.Transaction(func(tx *gorm.DB) error {
+        pack.QuestionCount = len(questions)
+        if err := tx.Omit("Questions").Create(pack).Error; err != nil {
+            return err
+        }
+        return createPackQuestions(tx, pack.ID, questions)
+    })
+    if err != nil {
+        return err
+    }
+    slog.Info("Created question pack", "pack_id", pack.ID, "questions", len(questions))
+    return nil
+}
+
+// GetByID finds a pack, without its questions
+func (r *PackRepository) GetByID(id string) (*models.QuestionPack, error) {
+    var pack models.QuestionPack
+    if err := r.db.First(&pack, "id = ?", id).Error; err != nil {
+        return nil, err
+    }
+    return &pack, nil
+}
+
+// GetWithQuestions finds a pack and loads its questions, oldest first
+func (r *PackRepository) GetWithQuestions(id string) (*models.QuestionPack, error) {
+    var pack models.QuestionPack
+    err := r.db.Preload("Questions", func(db *gorm.DB) *gorm.DB {
+        return db.Order("created_at asc")
+    }).First(&pack, "id = ?", id).Error
+    if err != nil {
+        return nil, err
+    }
+    return &pack, nil
+}
+
+// GetByOwner returns an account's packs, newest first
+func (r *PackRepository) GetByOwner(ownerID string) ([]models.QuestionPack, error) {
+    var packs []models.QuestionPack
+    err := r.db.Where("owner_id = ?", ownerID).Order("created_at desc").Find(&packs).Error
+    return packs, err
+}
+
+// ReplaceQuestions swaps a pack's questions for new ones and saves its
+// name and description
+func (r *PackRepository) ReplaceQuestions(pack *models.QuestionPack, questions []*models.Question) error {
+    err := r.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Where("pack_id = ?", pack.ID).Delete(&models.Question{}).Error; err != nil {
+            return err
+        }
+        if err := createPackQuestions(tx, pack.ID, questions); err != nil {
+            return err
+        }
+        pack.QuestionCount = len(questions)
+        return tx.Omit("Questions").Save(pack).Error
+    })
+    if err != nil {
+        return err
+    }
+    slog.Info("Replaced pack questions", "pack_id", pack.ID, "questions", len(questions))
+    return nil
+}
+
+// DeletePack removes a pack and its questions. Rooms that used it go back
+// to the shared bank.
+func (r *PackRepository) DeletePack(id string) error {
+    err := r.db.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Model(&models.Room{}).Where("pack_id = ?", id).Update("pack_id", nil).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("pack_id = ?", id).Delete(&models.Question{}).Error; err != nil {
+            return err
+        }
+        return tx.Where("id = ?", id).Delete(&models.QuestionPack{}).Error
+    })
+    if err != nil {
+        return err
+    }
+    slog.Info("Deleted question pack", "pack_id", id)
+    return nil
+}
+
+// InPlay reports whether a room is playing a game with the pack
+func (r *PackRepository) InPlay(id string) (bool, error) {
+    var count int64
+    err := r.db.Model(&models.Room{}).
+        Where("pack_id = ? AND status = ?", id, "playing").
+        Count(&count).Error
+    return count > 0, err
+}
+
+func createPackQuestions(tx *gorm.DB, packID uuid.UUID, questions []*models.Question) error {
+    if len(questions) == 0 {
+        return nil
+    }
+    for _, question := range questions {
+        question.PackID = &packID
+    }
+    return tx.CreateInBatches(questions, 100).Error
+}
@@ -3,9 +3,14 @@
 package repository
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"math/rand"
+	"strings"
 
+	"github.com/google/uuid"
 	"github.com/rohan03122001/quizzing/internal/models"
+	"gorm.io/gorm"
 )
 
 type QuestionRepository struct {
@@ -18,43 +23,245 @@ func NewQuestionRepository(db *Database) *QuestionRepository {
     }
 }
 
+// WithContext returns the repository running its queries with ctx, so they
+// join the caller's trace
+func (r *QuestionRepository) WithContext(ctx context.Context) QuestionStore {
+    return &QuestionRepository{db: &Database{r.db.WithContext(ctx)}}
+}
+
+// public scopes a query to the shared question bank, leaving out the
+// questions of hosts' private packs
+func (r *QuestionRepository) public() *gorm.DB {
+    return r.db.Model(&models.Question{}).Where("pack_id IS NULL")
+}
+
+// pool scopes a query to a pack's questions, or to the shared bank if
+// packID is nil
+func (r *QuestionRepository) pool(packID *uuid.UUID) *gorm.DB {
+    if packID == nil {
+        return r.public()
+    }
+    return r.db.Model(&models.Question{}).Where("pack_id = ?", *packID)
+}
+
 // CreateQuestion adds a new question
 func (r *QuestionRepository) CreateQuestion(question *models.Question) error {
-    log.Printf("Creating new question: %s", question.Content)
+    slog.Debug("Creating question", "content", question.Content)
     return r.db.Create(question).Error
 }
 
-// GetRandom gets a random question
-func (r *QuestionRepository) GetRandom() (*models.Question, error) {
-    log.Println("Fetching random question")
+// GetRandom gets a random question from a pack, or from the shared bank if
+// packID is nil
+func (r *QuestionRepository) GetRandom(packID *uuid.UUID) (*models.Question, error) {
+    question, err := pickRandom(func() *gorm.DB {
+        return r.pool(packID)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return question, nil
+}
+
+// pickRandom picks one of the questions a query matches. It counts them and
+// takes one at a random offset, rather than ORDER BY RANDOM(), so it works
+// the same on every database.
+func pickRandom(query func() *gorm.DB) (*models.Question, error) {
+    var count int64
+    if err := query().Count(&count).Error; err != nil {
+        return nil, err
+    }
+    if count == 0 {
+        return nil, gorm.ErrRecordNotFound
+    }
+
     var question models.Question
-    err := r.db.Order("RANDOM()").First(&question).Error
+    err := query().Order("id").Offset(rand.Intn(int(count))).Limit(1).Take(&question).Error
     if err != nil {
-        log.Printf("Error fetching random question: %v", err)
         return nil, err
     }
     return &question, nil
 }
 
+// effectiveDifficulty is the editors' difficulty, or the derived one for
+// questions they haven't rated
+const effectiveDifficulty = "CASE WHEN difficulty > 0 THEN difficulty ELSE derived_difficulty END"
+
+// GetRandomExcluding gets a random question from a pack or the shared bank
+// that isn't one of the given IDs
+func (r *QuestionRepository) GetRandomExcluding(packID *uuid.UUID, exclude []string) (*models.Question, error) {
+    return pickRandom(func() *gorm.DB {
+        query := r.pool(packID)
+        if len(exclude) > 0 {
+            query = query.Where("id NOT IN ?", exclude)
+        }
+        return query
+    })
+}
+
+// GetRandomOfDifficulty gets a random question from a pack or the shared
+// bank of a difficulty, 0 meaning unrated, that isn't one of the given IDs
+func (r *QuestionRepository) GetRandomOfDifficulty(packID *uuid.UUID, difficulty int, exclude []string) (*models.Question, error) {
+    return pickRandom(func() *gorm.DB {
+        query := r.pool(packID).Where(effectiveDifficulty+" = ?", difficulty)
+        if len(exclude) > 0 {
+            query = query.Where("id NOT IN ?", exclude)
+        }
+        return query
+    })
+}
+
+// SetDerivedDifficulty stores the difficulty worked out from answers
+func (r *QuestionRepository) SetDerivedDifficulty(questionID string, difficulty int) error {
+    return r.db.Model(&models.Question{}).
+        Where("id = ?", questionID).
+        UpdateColumn("derived_difficulty", difficulty).Error
+}
+
 // GetByID gets a specific question by ID
 func (r *QuestionRepository) GetByID(id string) (*models.Question, error) {
-    log.Printf("Fetching question by ID: %s", id)
     var question models.Question
     err := r.db.First(&question, "id = ?", id).Error
     if err != nil {
-        log.Printf("Error fetching question %s: %v", id, err)
         return nil, err
     }
     return &question, nil
 }
 
-// GetQuestionCount returns total number of questions
+// GetByIDs gets the questions with the given IDs that still exist
+func (r *QuestionRepository) GetByIDs(ids []string) ([]models.Question, error) {
+    var questions []models.Question
+    if len(ids) == 0 {
+        return questions, nil
+    }
+    err := r.db.Where("id IN ?", ids).Find(&questions).Error
+    return questions, err
+}
+
+// GetQuestionCount returns total number of questions in the shared bank
 func (r *QuestionRepository) GetQuestionCount() (int64, error) {
     var count int64
-    err := r.db.Model(&models.Question{}).Count(&count).Error
+    err := r.public().Count(&count).Error
     if err != nil {
-        log.Printf("Error counting questions: %v", err)
         return 0, err
     }
     return count, nil
-}
\ No newline at end of file
+}
+
+// QuestionFilter narrows a question search. Zero values match everything.
+type QuestionFilter struct {
+    Query      string // Matched against the content, answer and aliases
+    Category   string
+    Difficulty int
+}
+
+// Search returns a page of questions matching the filter, newest first,
+// and the total number of matches
+func (r *QuestionRepository) Search(filter QuestionFilter, limit int, offset int) ([]models.Question, int64, error) {
+    var total int64
+    if err := r.filtered(filter).Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    var questions []models.Question
+    err := r.filtered(filter).Order("created_at desc").Limit(limit).Offset(offset).Find(&questions).Error
+    if err != nil {
+        return nil, 0, err
+    }
+    return questions, total, nil
+}
+
+// ListAll returns every question matching the filter, oldest first
+func (r *QuestionRepository) ListAll(filter QuestionFilter) ([]models.Question, error) {
+    var questions []models.Question
+    err := r.filtered(filter).Order("created_at asc").Find(&questions).Error
+    return questions, err
+}
+
+func (r *QuestionRepository) filtered(filter QuestionFilter) *gorm.DB {
+    query := r.public()
+    if filter.Query != "" {
+        like := "%" + strings.ToLower(filter.Query) + "%"
+        query = query.Where("LOWER(content) LIKE ? OR LOWER(answer) LIKE ? OR LOWER(aliases) LIKE ?", like, like, like)
+    }
+    if filter.Category != "" {
+        query = query.Where("LOWER(category) = LOWER(?)", filter.Category)
+    }
+    if filter.Difficulty > 0 {
+        query = query.Where(effectiveDifficulty+" = ?", filter.Difficulty)
+    }
+    return query
+}
+
+// UpdateQuestion saves every field of an existing question
+func (r *QuestionRepository) UpdateQuestion(question *models.Question) error {
+    slog.Debug("Updating question", "question_id", question.ID)
+    return r.db.Save(question).Error
+}
+
+// DeleteQuestion removes a question from the shared bank. It reports
+// whether there was one.
+func (r *QuestionRepository) DeleteQuestion(id string) (bool, error) {
+    result := r.db.Where("pack_id IS NULL").Delete(&models.Question{}, "id = ?", id)
+    if result.Error != nil {
+        return false, result.Error
+    }
+    slog.Info("Deleted question", "question_id", id)
+    return result.RowsAffected > 0, nil
+}
+
+// FindByContent finds a question in the shared bank with the same text,
+// ignoring case and surrounding spaces
+func (r *QuestionRepository) FindByContent(content string) (*models.Question, error) {
+    var question models.Question
+    err := r.public().Where("LOWER(TRIM(content)) = LOWER(TRIM(?))", content).First(&question).Error
+    if err != nil {
+        return nil, err
+    }
+    return &question, nil
+}
+
+// ContentKeys returns the content of every question in the shared bank,
+// lowercased and trimmed, for spotting duplicates
+func (r *QuestionRepository) ContentKeys() (map[string]bool, error) {
+    var contents []string
+    if err := r.public().Pluck("LOWER(TRIM(content))", &contents).Error; err != nil {
+        return nil, err
+    }
+
+    keys := make(map[string]bool, len(contents))
+    for _, content := range contents {
+        keys[content] = true
+    }
+    return keys, nil
+}
+
+// CreateQuestions adds questions in a single transaction; either all of
+// them are added or none
+func (r *QuestionRepository) CreateQuestions(questions []*models.Question) error {
+    err := r.db.Transaction(func(tx *gorm.DB) error {
+        return tx.CreateInBatches(questions, 100).Error
+    })
+    if err != nil {
+        return err
+    }
+    slog.Info("Imported questions", "count", len(questions))
+    return nil
+}
+
+// CategoryCount is a category and how many questions are in it
+type CategoryCount struct {
+    Category string `json:"category"`
+    Count    int64  `json:"count"`
+}
+
+// Categories returns every category in the shared bank with its number of
+// questions
+func (r *QuestionRepository) Categories() ([]CategoryCount, error) {
+    var categories []CategoryCount
+    err := r.public().
+        Select("category, COUNT(*) AS count").
+        Group("category").
+        Order("category asc").
+        Scan(&categories).Error
+    return categories, err
+}
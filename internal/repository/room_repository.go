@@ -3,9 +3,11 @@
 package repository
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/rohan03122001/quizzing/internal/models"
 	"gorm.io/gorm"
 )
@@ -20,19 +22,23 @@ func NewRoomRepository(db *Database) *RoomRepository {
     }
 }
 
+// WithContext returns the repository running its queries with ctx, so they
+// join the caller's trace
+func (r *RoomRepository) WithContext(ctx context.Context) RoomStore {
+    return &RoomRepository{db: &Database{r.db.WithContext(ctx)}}
+}
+
 // CreateRoom adds a new room
 func (r *RoomRepository) CreateRoom(room *models.Room) error {
-    log.Printf("Creating room with code: %s", room.Code)
+    slog.Debug("Creating room", "room", room.Code)
     return r.db.Create(room).Error
 }
 
 // GetByCode finds a room by its code
 func (r *RoomRepository) GetByCode(code string) (*models.Room, error) {
-    log.Printf("Fetching room with code: %s", code)
     var room models.Room
     err := r.db.Where("code = ?", code).First(&room).Error
     if err != nil {
-        log.Printf("Error fetching room %s: %v", code, err)
         return nil, err
     }
     return &room, nil
@@ -40,7 +46,7 @@ func (r *RoomRepository) GetByCode(code string) (*models.Room, error) {
 
 // UpdateStatus updates room's status
 func (r *RoomRepository) UpdateStatus(roomID string, status string) error {
-    log.Printf("Updating status to %s for room %s", status, roomID)
+    slog.Debug("Updating room status", "room_id", roomID, "status", status)
     return r.db.Model(&models.Room{}).
         Where("id = ?", roomID).
         Update("status", status).Error
@@ -48,34 +54,62 @@ func (r *RoomRepository) UpdateStatus(roomID string, status string) error {
 
 // UpdateCurrentRound increments the current round
 func (r *RoomRepository) UpdateCurrentRound(roomID string) error {
-    log.Printf("Incrementing round for room %s", roomID)
+    slog.Debug("Incrementing round", "room_id", roomID)
     return r.db.Model(&models.Room{}).
         Where("id = ?", roomID).
-        UpdateColumn("current_round", r.db.Raw("current_round + 1")).Error
+        UpdateColumn("current_round", gorm.Expr("current_round + 1")).Error
+}
+
+// UpdateHost sets the room's host player
+func (r *RoomRepository) UpdateHost(roomID string, playerID string) error {
+    slog.Debug("Setting room host", "room_id", roomID, "player", playerID)
+    return r.db.Model(&models.Room{}).
+        Where("id = ?", roomID).
+        Update("host_id", playerID).Error
 }
 
 // GetActive gets all rooms that are waiting or playing
 func (r *RoomRepository) GetActive() ([]models.Room, error) {
-    log.Println("Fetching active rooms")
     var rooms []models.Room
     err := r.db.Where("status IN (?)", []string{"waiting", "playing"}).Find(&rooms).Error
     return rooms, err
 }
 
+// CountByStatus counts the rooms in each status
+func (r *RoomRepository) CountByStatus() (map[string]int64, error) {
+    var rows []struct {
+        Status string
+        Count  int64
+    }
+    err := r.db.Model(&models.Room{}).
+        Select("status, COUNT(*) AS count").
+        Group("status").
+        Scan(&rows).Error
+    if err != nil {
+        return nil, err
+    }
+
+    counts := make(map[string]int64, len(rows))
+    for _, row := range rows {
+        counts[row.Status] = row.Count
+    }
+    return counts, nil
+}
+
 // EndGame marks a room as finished
 func (r *RoomRepository) EndGame(roomID string) error {
-    log.Printf("Ending game for room %s", roomID)
+    slog.Debug("Ending game", "room_id", roomID)
     return r.db.Model(&models.Room{}).
         Where("id = ?", roomID).
         Updates(map[string]interface{}{
-            "status": "finished",
-            "ended_at": r.db.Raw("NOW()"),
+            "status":   "finished",
+            "ended_at": time.Now(),
         }).Error
 }
 
 // UpdateRoom updates room settings
 func (r *RoomRepository) UpdateRoom(room *models.Room) error {
-    log.Printf("Updating room %s settings", room.Code)
+    slog.Debug("Updating room settings", "room", room.Code)
     return r.db.Save(room).Error
 }
 
@@ -125,4 +159,33 @@ func (r *RoomRepository) UpdateLastActivity(roomID string) error {
 	return r.db.Model(&models.Room{}).
 		Where("id = ?", roomID).
 		Update("last_activity", time.Now()).Error
-}
\ No newline at end of file
+}
+
+// SetPack sets the question pack a room draws from, nil for the shared bank
+func (r *RoomRepository) SetPack(roomID string, packID *uuid.UUID) error {
+    slog.Debug("Setting room question pack", "room_id", roomID, "pack_id", packID)
+    var value interface{}
+    if packID != nil {
+        value = *packID
+    }
+    return r.db.Model(&models.Room{}).
+        Where("id = ?", roomID).
+        Update("pack_id", value).Error
+}
+
+// SetCheckpoint marks a game as stopped between rounds by a shutdown
+func (r *RoomRepository) SetCheckpoint(roomID string, at time.Time) error {
+    slog.Debug("Checkpointing room", "room_id", roomID)
+    return r.db.Model(&models.Room{}).
+        Where("id = ?", roomID).
+        Update("checkpointed_at", at).Error
+}
+
+// ClearCheckpoint removes a room's checkpoint mark. It reports whether the
+// room had one, so only one caller resumes the game.
+func (r *RoomRepository) ClearCheckpoint(roomID string) (bool, error) {
+    result := r.db.Model(&models.Room{}).
+        Where("id = ? AND checkpointed_at IS NOT NULL", roomID).
+        Update("checkpointed_at", nil)
+    return result.RowsAffected == 1, result.Error
+}
@@ -0,0 +1,107 @@
+// internal/repository/store.go
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/models"
+)
+
+// The game services depend on these interfaces rather than on the GORM
+// repositories, so they can run against another backend such as the
+// in-memory one in the memory package. Lookups that find nothing return
+// gorm.ErrRecordNotFound whatever the backend. WithContext returns a store
+// whose queries run with a context, so they show up in its trace.
+
+// RoomStore stores game rooms
+type RoomStore interface {
+    WithContext(ctx context.Context) RoomStore
+    CreateRoom(room *models.Room) error
+    GetByCode(code string) (*models.Room, error)
+    UpdateStatus(roomID string, status string) error
+    UpdateCurrentRound(roomID string) error
+    UpdateHost(roomID string, playerID string) error
+    GetActive() ([]models.Room, error)
+    CountByStatus() (map[string]int64, error)
+    EndGame(roomID string) error
+    UpdateRoom(room *models.Room) error
+    GetInactiveRooms(before time.Time) ([]models.Room, error)
+    DeleteRoom(roomID string) error
+    UpdateLastActivity(roomID string) error
+    SetPack(roomID string, packID *uuid.UUID) error
+    SetCheckpoint(roomID string, at time.Time) error
+    ClearCheckpoint(roomID string) (bool, error)
+}
+
+// QuestionStore stores the question bank and hosts' pack questions
+type QuestionStore interface {
+    WithContext(ctx context.Context) QuestionStore
+    CreateQuestion(question *models.Question) error
+    GetRandom(packID *uuid.UUID) (*models.Question, error)
+    GetRandomExcluding(packID *uuid.UUID, exclude []string) (*models.Question, error)
+    GetRandomOfDifficulty(packID *uuid.UUID, difficulty int, exclude []string) (*models.Question, error)
+    SetDerivedDifficulty(questionID string, difficulty int) error
+    GetByID(id string) (*models.Question, error)
+    GetByIDs(ids []string) ([]models.Question, error)
+    GetQuestionCount() (int64, error)
+    Search(filter QuestionFilter, limit int, offset int) ([]models.Question, int64, error)
+    ListAll(filter QuestionFilter) ([]models.Question, error)
+    UpdateQuestion(question *models.Question) error
+    DeleteQuestion(id string) (bool, error)
+    FindByContent(content string) (*models.Question, error)
+    ContentKeys() (map[string]bool, error)
+    CreateQuestions(questions []*models.Question) error
+    Categories() ([]CategoryCount, error)
+}
+
+// RoundStore stores the rounds of live games and their answers
+type RoundStore interface {
+    WithContext(ctx context.Context) RoundStore
+    CreateRound(round *models.GameRound) error
+    GetCurrentRound(roomID string) (*models.GameRound, error)
+    SaveAnswer(answer *models.PlayerAnswer) error
+    HasCorrectAnswer(roundID string, playerID string) (bool, error)
+    GetRoundAnswers(roundID string) ([]models.PlayerAnswer, error)
+    SaveCorrectAnswer(answer *models.PlayerAnswer, score func(order int) int) error
+    UpdateRoundState(roundID string, state string) error
+    PauseRound(roundID string, pausedAt time.Time) error
+    ResumeRound(roundID string, endTime time.Time) error
+    VoidRound(roundID string) error
+    GetRoundScores(roundID string) (map[string]int, error)
+    GetRoomRounds(roomID string) ([]models.GameRound, error)
+    GetPlayerAnswers(roomID string, playerID string) ([]models.PlayerAnswer, error)
+    DeleteRoundAnswers(roundID string) error
+    DeleteRound(roundID string) error
+}
+
+// HistoryStore stores archived games
+type HistoryStore interface {
+    WithContext(ctx context.Context) HistoryStore
+    SaveSession(session *models.GameSession) error
+    ListByRoomCode(roomCode string, limit int, offset int) ([]models.GameSession, int64, error)
+    GetSession(id string) (*models.GameSession, error)
+    GetPlayerGames(playerID string) ([]models.SessionPlayer, error)
+    GetPlayerRounds(playerID string) ([]models.SessionRound, error)
+    GetAnswerRecords(category string) ([]AnswerRecord, error)
+    AnswerStats(questionID string) (attempts int64, correct int64, err error)
+    PlayedQuestionIDs() ([]string, error)
+}
+
+// LeaderboardStore stores the leaderboards' running totals
+type LeaderboardStore interface {
+    WithContext(ctx context.Context) LeaderboardStore
+    AddResults(entries []models.LeaderboardEntry) error
+    List(board string, limit int, offset int) ([]models.LeaderboardEntry, int64, error)
+    Categories() ([]string, error)
+}
+
+var (
+    _ RoomStore        = (*RoomRepository)(nil)
+    _ QuestionStore    = (*QuestionRepository)(nil)
+    _ RoundStore       = (*GameRoundRepository)(nil)
+    _ HistoryStore     = (*HistoryRepository)(nil)
+    _ LeaderboardStore = (*LeaderboardRepository)(nil)
+)
@@ -0,0 +1,82 @@
+// internal/repository/tracing.go
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// SetTracing records a span for each query made with a context that is part
+// of a trace, e.g. through a store's WithContext. Queries outside a trace,
+// such as the cleanup routine's, aren't traced. Call it before the database
+// is used.
+func (d *Database) SetTracing(tracer trace.Tracer) error {
+    const spanKey = "tracing:span"
+    system := d.Dialector.Name()
+
+    before := func(operation string) func(*gorm.DB) {
+        return func(db *gorm.DB) {
+            ctx := db.Statement.Context
+            if !trace.SpanContextFromContext(ctx).IsValid() {
+                return
+            }
+            ctx, span := tracer.Start(ctx, "db "+operation, trace.WithSpanKind(trace.SpanKindClient))
+            db.Statement.Context = ctx
+            db.InstanceSet(spanKey, span)
+        }
+    }
+    after := func(operation string) func(*gorm.DB) {
+        return func(db *gorm.DB) {
+            value, ok := db.InstanceGet(spanKey)
+            if !ok {
+                return
+            }
+            span := value.(trace.Span)
+            defer span.End()
+
+            // The table is known once the statement is built
+            if db.Statement.Table != "" {
+                span.SetName("db " + operation + " " + db.Statement.Table)
+            }
+            span.SetAttributes(
+                attribute.String("db.system", system),
+                attribute.String("db.sql.table", db.Statement.Table),
+                attribute.String("db.statement", db.Statement.SQL.String()),
+                attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+            )
+            // A lookup that finds nothing isn't a failure
+            if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+                span.RecordError(db.Error)
+                span.SetStatus(codes.Error, db.Error.Error())
+            }
+        }
+    }
+
+    callbacks := d.Callback()
+    errs := []error{
+        callbacks.Create().Before("gorm:create").Register("tracing:before_create", before("create")),
+        callbacks.Create().After("gorm:create").Register("tracing:after_create", after("create")),
+        callbacks.Query().Before("gorm:query").Register("tracing:before_query", before("query")),
+        callbacks.Query().After("gorm:query").Register("tracing:after_query", after("query")),
+        callbacks.Update().Before("gorm:update").Register("tracing:before_update", before("update")),
+        callbacks.Update().After("gorm:update").Register("tracing:after_update", after("update")),
+        callbacks.Delete().Before("gorm:delete").Register("tracing:before_delete", before("delete")),
+        callbacks.Delete().After("gorm:delete").Register("tracing:after_delete", after("delete")),
+        callbacks.Row().Before("gorm:row").Register("tracing:before_row", before("row")),
+        callbacks.Row().After("gorm:row").Register("tracing:after_row", after("row")),
+        callbacks.Raw().Before("gorm:raw").Register("tracing:before_raw", before("raw")),
+        callbacks.Raw().After("gorm:raw").Register("tracing:after_raw", after("raw")),
+    }
+    for _, err := range errs {
+        if err != nil {
+            return fmt.Errorf("registering tracing callbacks: %w", err)
+        }
+    }
+    return nil
+}
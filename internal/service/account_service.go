@@ -0,0 +1,299 @@
+// internal/service/account_service.go
+
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+	"github.com/rohan03122001/quizzing/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+    sessionTokenTTL   = 30 * 24 * time.Hour
+    magicLinkTokenTTL = 15 * time.Minute
+
+    minPasswordLength = 8
+    maxUsernameLength = 32
+)
+
+// AccountService manages registered players and their sign-in tokens.
+// Playing as a guest doesn't need an account.
+type AccountService struct {
+    accountRepo *repository.AccountRepository
+}
+
+func NewAccountService(accountRepo *repository.AccountRepository) *AccountService {
+    return &AccountService{
+        accountRepo: accountRepo,
+    }
+}
+
+// Session is a signed-in account and its bearer token
+type Session struct {
+    Account   *models.Account `json:"account"`
+    Token     string          `json:"token"`
+    ExpiresAt time.Time       `json:"expires_at"`
+}
+
+// Register creates an account. It needs a password, an email for magic-link
+// sign-in, or both.
+func (s *AccountService) Register(username string, password string, email string) (*Session, error) {
+    username = strings.TrimSpace(username)
+    email = strings.TrimSpace(email)
+
+    if username == "" || len(username) > maxUsernameLength {
+        return nil, NewError(protocol.CodeInvalidMessage, "username must be 1-32 characters")
+    }
+    if password == "" && email == "" {
+        return nil, NewError(protocol.CodeInvalidMessage, "a password or an email is required")
+    }
+    if password != "" && len(password) < minPasswordLength {
+        return nil, NewError(protocol.CodeInvalidMessage, "password must be at least 8 characters")
+    }
+
+    if _, err := s.accountRepo.GetByUsername(username); err == nil {
+        return nil, ErrUsernameTaken
+    }
+
+    account := &models.Account{Username: username}
+    if email != "" {
+        if _, err := s.accountRepo.GetByEmail(email); err == nil {
+            return nil, ErrEmailTaken
+        }
+        account.Email = &email
+    }
+
+    if password != "" {
+        hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+        if err != nil {
+            return nil, err
+        }
+        account.PasswordHash = string(hash)
+    }
+
+    // Someone may have registered the name or email since the checks above
+    if err := s.accountRepo.CreateAccount(account); err != nil {
+        switch {
+        case errors.Is(err, repository.ErrUsernameExists):
+            return nil, ErrUsernameTaken
+        case errors.Is(err, repository.ErrEmailExists):
+            return nil, ErrEmailTaken
+        }
+        slog.Error("Failed to create account", "username", username, "err", err)
+        return nil, err
+    }
+
+    slog.Info("Registered account", "username", account.Username, "account_id", account.ID)
+    return s.newSession(account)
+}
+
+// Login signs in with a username and password
+func (s *AccountService) Login(username string, password string) (*Session, error) {
+    account, err := s.accountRepo.GetByUsername(strings.TrimSpace(username))
+    if err != nil || account.PasswordHash == "" {
+        return nil, ErrInvalidCredentials
+    }
+
+    if err := bcrypt.CompareHashAndPassword([]byte(account.PasswordHash), []byte(password)); err != nil {
+        return nil, ErrInvalidCredentials
+    }
+
+    if account.BannedAt != nil {
+        return nil, ErrAccountBanned
+    }
+
+    return s.newSession(account)
+}
+
+// RequestMagicLink creates a single-use sign-in token for the account with
+// this email, for the caller to deliver. The bool is false when no account
+// matched.
+func (s *AccountService) RequestMagicLink(email string) (string, bool, error) {
+    account, err := s.accountRepo.GetByEmail(strings.TrimSpace(email))
+    if err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return "", false, nil
+        }
+        return "", false, err
+    }
+
+    token, _, err := s.issueToken(account, "magic_link", magicLinkTokenTTL)
+    if err != nil {
+        return "", false, err
+    }
+
+    slog.Info("Issued magic link", "account_id", account.ID)
+    return token, true, nil
+}
+
+// RedeemMagicLink exchanges a magic-link token for a session. The link
+// only works once.
+func (s *AccountService) RedeemMagicLink(token string) (*Session, error) {
+    hash := hashToken(token)
+    stored, err := s.accountRepo.GetToken(hash, "magic_link")
+    if err != nil {
+        return nil, ErrInvalidToken
+    }
+
+    // Deleting the link is the claim, so it signs in at most once
+    claimed, err := s.accountRepo.ClaimToken(hash, "magic_link")
+    if err != nil {
+        return nil, err
+    }
+    if !claimed {
+        return nil, ErrInvalidToken
+    }
+
+    account, err := s.accountRepo.GetByID(stored.AccountID.String())
+    if err != nil {
+        return nil, ErrInvalidToken
+    }
+    if account.BannedAt != nil {
+        return nil, ErrAccountBanned
+    }
+
+    return s.newSession(account)
+}
+
+// Authenticate returns the account a session token belongs to
+func (s *AccountService) Authenticate(token string) (*models.Account, error) {
+    if token == "" {
+        return nil, ErrInvalidToken
+    }
+
+    stored, err := s.accountRepo.GetToken(hashToken(token), "session")
+    if err != nil {
+        return nil, ErrInvalidToken
+    }
+
+    account, err := s.accountRepo.GetByID(stored.AccountID.String())
+    if err != nil {
+        return nil, ErrInvalidToken
+    }
+    if account.BannedAt != nil {
+        return nil, ErrAccountBanned
+    }
+    return account, nil
+}
+
+// Logout revokes a session token
+func (s *AccountService) Logout(token string) error {
+    return s.accountRepo.DeleteToken(hashToken(token))
+}
+
+// IsAccount checks if a player ID belongs to a registered account
+func (s *AccountService) IsAccount(playerID string) bool {
+    exists, err := s.accountRepo.Exists(playerID)
+    if err != nil {
+        // Player IDs that aren't UUIDs can't be accounts
+        return false
+    }
+    return exists
+}
+
+// Ban stops an account from signing in and revokes its sessions
+func (s *AccountService) Ban(accountID string, reason string) error {
+    if _, err := uuid.Parse(accountID); err != nil {
+        return ErrAccountNotFound
+    }
+    now := time.Now()
+    found, err := s.accountRepo.SetBanned(accountID, &now, reason)
+    if err != nil {
+        return err
+    }
+    if !found {
+        return ErrAccountNotFound
+    }
+    return s.accountRepo.DeleteAccountTokens(accountID)
+}
+
+// Unban lifts an account's ban
+func (s *AccountService) Unban(accountID string) error {
+    if _, err := uuid.Parse(accountID); err != nil {
+        return ErrAccountNotFound
+    }
+    found, err := s.accountRepo.SetBanned(accountID, nil, "")
+    if err != nil {
+        return err
+    }
+    if !found {
+        return ErrAccountNotFound
+    }
+    return nil
+}
+
+// RequireAdmin returns the account a session token belongs to if it is an admin
+func (s *AccountService) RequireAdmin(token string) (*models.Account, error) {
+    account, err := s.Authenticate(token)
+    if err != nil {
+        return nil, err
+    }
+    if !account.IsAdmin {
+        return nil, ErrForbidden
+    }
+    return account, nil
+}
+
+// GrantAdmin makes an existing account an admin
+func (s *AccountService) GrantAdmin(username string) error {
+    found, err := s.accountRepo.SetAdmin(username, true)
+    if err != nil {
+        return err
+    }
+    if !found {
+        return fmt.Errorf("no account named %q", username)
+    }
+    slog.Info("Granted admin", "username", username)
+    return nil
+}
+
+func (s *AccountService) newSession(account *models.Account) (*Session, error) {
+    token, expiresAt, err := s.issueToken(account, "session", sessionTokenTTL)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := s.accountRepo.UpdateLastLogin(account.ID.String()); err != nil {
+        slog.Error("Error updating last login", "account_id", account.ID, "err", err)
+    }
+
+    return &Session{Account: account, Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// issueToken creates a random token and stores its hash
+func (s *AccountService) issueToken(account *models.Account, kind string, ttl time.Duration) (string, time.Time, error) {
+    b := make([]byte, 32)
+    if _, err := rand.Read(b); err != nil {
+        return "", time.Time{}, err
+    }
+    token := hex.EncodeToString(b)
+    expiresAt := time.Now().Add(ttl)
+
+    err := s.accountRepo.SaveToken(&models.AuthToken{
+        TokenHash: hashToken(token),
+        AccountID: account.ID,
+        Kind:      kind,
+        ExpiresAt: expiresAt,
+    })
+    if err != nil {
+        return "", time.Time{}, err
+    }
+    return token, expiresAt, nil
+}
+
+func hashToken(token string) string {
+    sum := sha256.Sum256([]byte(token))
+    return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,225 @@
+// internal/service/account_service_test.go
+
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rohan03122001/quizzing/internal/protocol"
+)
+
+const testPassword = "correct horse"
+
+func newTestAccounts(t *testing.T, stores testStores) *AccountService {
+    t.Helper()
+    if stores.accounts == nil {
+        t.Skip("accounts need a GORM backend")
+    }
+    return NewAccountService(stores.accounts)
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+    forEachBackend(t, func(t *testing.T, stores testStores) {
+        accounts := newTestAccounts(t, stores)
+
+        session, err := accounts.Register(" alice ", testPassword, "alice@example.com")
+        if err != nil {
+            t.Fatal(err)
+        }
+        if session.Account.Username != "alice" || !session.ExpiresAt.After(time.Now()) {
+            t.Fatalf("got %+v, want alice signed in", session)
+        }
+        account, err := accounts.Authenticate(session.Token)
+        if err != nil || account.ID != session.Account.ID {
+            t.Fatalf("got %v, %v for the new session, want alice", account, err)
+        }
+
+        if _, err := accounts.Register("ALICE", testPassword, ""); !errors.Is(err, ErrUsernameTaken) {
+            t.Errorf("got %v for the same username, want ErrUsernameTaken", err)
+        }
+        if _, err := accounts.Register("bob", testPassword, "Alice@Example.com"); !errors.Is(err, ErrEmailTaken) {
+            t.Errorf("got %v for the same email, want ErrEmailTaken", err)
+        }
+        if _, err := accounts.Register("bob", "short", ""); ErrorCodeOf(err) != protocol.CodeInvalidMessage {
+            t.Errorf("got %v for a short password, want INVALID_MESSAGE", err)
+        }
+
+        login, err := accounts.Login("Alice", testPassword)
+        if err != nil || login.Account.ID != session.Account.ID || login.Token == session.Token {
+            t.Fatalf("got %+v, %v logging in, want a new session for alice", login, err)
+        }
+        if _, err := accounts.Login("alice", "wrong horse"); !errors.Is(err, ErrInvalidCredentials) {
+            t.Errorf("got %v for a wrong password, want ErrInvalidCredentials", err)
+        }
+        if _, err := accounts.Login("nobody", testPassword); !errors.Is(err, ErrInvalidCredentials) {
+            t.Errorf("got %v for an unknown user, want ErrInvalidCredentials", err)
+        }
+
+        if err := accounts.Logout(login.Token); err != nil {
+            t.Fatal(err)
+        }
+        if _, err := accounts.Authenticate(login.Token); !errors.Is(err, ErrInvalidToken) {
+            t.Errorf("got %v after logging out, want ErrInvalidToken", err)
+        }
+        if _, err := accounts.Authenticate(session.Token); err != nil {
+            t.Errorf("logging out one session ended another: %v", err)
+        }
+    })
+}
+
+func TestMagicLinks(t *testing.T) {
+    forEachBackend(t, func(t *testing.T, stores testStores) {
+        accounts := newTestAccounts(t, stores)
+        registered, err := accounts.Register("alice", "", "alice@example.com")
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if _, found, err := accounts.RequestMagicLink("nobody@example.com"); found || err != nil {
+            t.Errorf("got %v, %v for an unknown email, want no link", found, err)
+        }
+
+        token, found, err := accounts.RequestMagicLink("ALICE@example.com")
+        if err != nil || !found {
+            t.Fatalf("got %v, %v, want a link", found, err)
+        }
+        session, err := accounts.RedeemMagicLink(token)
+        if err != nil || session.Account.ID != registered.Account.ID {
+            t.Fatalf("got %+v, %v redeeming the link, want alice signed in", session, err)
+        }
+        if _, err := accounts.Authenticate(session.Token); err != nil {
+            t.Errorf("got %v for the link's session", err)
+        }
+
+        // Links work once
+        if _, err := accounts.RedeemMagicLink(token); !errors.Is(err, ErrInvalidToken) {
+            t.Errorf("got %v redeeming the link again, want ErrInvalidToken", err)
+        }
+        // A session token isn't a magic link
+        if _, err := accounts.RedeemMagicLink(session.Token); !errors.Is(err, ErrInvalidToken) {
+            t.Errorf("got %v redeeming a session token, want ErrInvalidToken", err)
+        }
+    })
+}
+
+func TestMagicLinkRedeemedOnce(t *testing.T) {
+    forEachBackend(t, func(t *testing.T, stores testStores) {
+        accounts := newTestAccounts(t, stores)
+        if _, err := accounts.Register("alice", "", "alice@example.com"); err != nil {
+            t.Fatal(err)
+        }
+        token, _, err := accounts.RequestMagicLink("alice@example.com")
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        // Redeem the same link at once from several requests
+        const attempts = 8
+        var wg sync.WaitGroup
+        results := make(chan error, attempts)
+        for i := 0; i < attempts; i++ {
+            wg.Add(1)
+            go func() {
+                defer wg.Done()
+                _, err := accounts.RedeemMagicLink(token)
+                results <- err
+            }()
+        }
+        wg.Wait()
+        close(results)
+
+        signedIn := 0
+        for err := range results {
+            switch {
+            case err == nil:
+                signedIn++
+            case !errors.Is(err, ErrInvalidToken):
+                t.Errorf("got %v, want ErrInvalidToken for the losers", err)
+            }
+        }
+        if signedIn != 1 {
+            t.Errorf("the link signed in %d times, want once", signedIn)
+        }
+    })
+}
+
+func TestExpiredTokens(t *testing.T) {
+    forEachBackend(t, func(t *testing.T, stores testStores) {
+        accounts := newTestAccounts(t, stores)
+        registered, err := accounts.Register("alice", testPassword, "alice@example.com")
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        session, _, err := accounts.issueToken(registered.Account, "session", -time.Minute)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if _, err := accounts.Authenticate(session); !errors.Is(err, ErrInvalidToken) {
+            t.Errorf("got %v for an expired session, want ErrInvalidToken", err)
+        }
+
+        link, _, err := accounts.issueToken(registered.Account, "magic_link", -time.Minute)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if _, err := accounts.RedeemMagicLink(link); !errors.Is(err, ErrInvalidToken) {
+            t.Errorf("got %v for an expired magic link, want ErrInvalidToken", err)
+        }
+    })
+}
+
+func TestBannedAccount(t *testing.T) {
+    forEachBackend(t, func(t *testing.T, stores testStores) {
+        accounts := newTestAccounts(t, stores)
+        registered, err := accounts.Register("alice", testPassword, "alice@example.com")
+        if err != nil {
+            t.Fatal(err)
+        }
+        link, _, err := accounts.RequestMagicLink("alice@example.com")
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        if err := accounts.Ban(registered.Account.ID.String(), "spamming"); err != nil {
+            t.Fatal(err)
+        }
+
+        // Its tokens are revoked
+        if _, err := accounts.Authenticate(registered.Token); !errors.Is(err, ErrInvalidToken) {
+            t.Errorf("got %v for a session from before the ban, want ErrInvalidToken", err)
+        }
+        if _, err := accounts.RedeemMagicLink(link); !errors.Is(err, ErrInvalidToken) {
+            t.Errorf("got %v for a link from before the ban, want ErrInvalidToken", err)
+        }
+
+        // And it can't get new ones
+        if _, err := accounts.Login("alice", testPassword); !errors.Is(err, ErrAccountBanned) {
+            t.Errorf("got %v logging in, want ErrAccountBanned", err)
+        }
+        link, _, err = accounts.RequestMagicLink("alice@example.com")
+        if err != nil {
+            t.Fatal(err)
+        }
+        if _, err := accounts.RedeemMagicLink(link); !errors.Is(err, ErrAccountBanned) {
+            t.Errorf("got %v redeeming a new link, want ErrAccountBanned", err)
+        }
+        session, _, err := accounts.issueToken(registered.Account, "session", time.Hour)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if _, err := accounts.Authenticate(session); !errors.Is(err, ErrAccountBanned) {
+            t.Errorf("got %v for a session issued after the ban, want ErrAccountBanned", err)
+        }
+
+        if err := accounts.Unban(registered.Account.ID.String()); err != nil {
+            t.Fatal(err)
+        }
+        if _, err := accounts.Login("alice", testPassword); err != nil {
+            t.Errorf("got %v logging in after the ban was lifted", err)
+        }
+    })
+}
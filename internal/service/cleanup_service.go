@@ -1,47 +1,53 @@
 package service
 
 import (
-	"log"
+	"log/slog"
 	"time"
 
+	"github.com/rohan03122001/quizzing/internal/metrics"
 	"github.com/rohan03122001/quizzing/internal/repository"
 	"github.com/rohan03122001/quizzing/internal/websocket"
 )
 
-const (
-	inactiveRoomTimeout = 10 * time.Minute
-	cleanupInterval    = 1 * time.Minute
-)
-
 type CleanupService struct {
-	roomRepo *repository.RoomRepository
-	hub      *websocket.Hub
+	roomRepo        repository.RoomStore
+	hub             *websocket.Hub
+	interval        time.Duration // How often to look for inactive rooms
+	inactiveTimeout time.Duration // Idle time before a room is cleaned up
+	metrics         *metrics.Metrics
 }
 
-func NewCleanupService(roomRepo *repository.RoomRepository, hub *websocket.Hub) *CleanupService {
+func NewCleanupService(roomRepo repository.RoomStore, hub *websocket.Hub, interval time.Duration, inactiveTimeout time.Duration) *CleanupService {
 	return &CleanupService{
-		roomRepo: roomRepo,
-		hub:      hub,
+		roomRepo:        roomRepo,
+		hub:             hub,
+		interval:        interval,
+		inactiveTimeout: inactiveTimeout,
 	}
 }
 
+// SetMetrics sets where cleaned up rooms are counted
+func (s *CleanupService) SetMetrics(m *metrics.Metrics) {
+	s.metrics = m
+}
+
 func (s *CleanupService) StartCleanupRoutine() {
-	ticker := time.NewTicker(cleanupInterval)
+	ticker := time.NewTicker(s.interval)
 	go func() {
 		for range ticker.C {
 			s.cleanupInactiveRooms()
 		}
 	}()
-	log.Println("Room cleanup routine started")
+	slog.Info("Room cleanup routine started", "interval", s.interval.String())
 }
 
 func (s *CleanupService) cleanupInactiveRooms() {
-	inactiveTime := time.Now().Add(-inactiveRoomTimeout)
+	inactiveTime := time.Now().Add(-s.inactiveTimeout)
 	
 	// Get rooms that need cleanup
 	rooms, err := s.roomRepo.GetInactiveRooms(inactiveTime)
 	if err != nil {
-		log.Printf("Error fetching inactive rooms: %v", err)
+		slog.Error("Error fetching inactive rooms", "err", err)
 		return
 	}
 
@@ -53,20 +59,22 @@ func (s *CleanupService) cleanupInactiveRooms() {
 			// Delete rooms with no players that haven't had activity
 			if playerCount == 0 {
 				if err := s.roomRepo.DeleteRoom(room.ID.String()); err != nil {
-					log.Printf("Error deleting inactive room %s: %v", room.Code, err)
+					slog.Error("Error deleting inactive room", "room", room.Code, "err", err)
 					continue
 				}
-				log.Printf("Deleted inactive waiting room: %s", room.Code)
+				s.metrics.RoomCleanedUp("deleted")
+				slog.Info("Deleted inactive waiting room", "room", room.Code)
 			}
 
 		case "playing":
 			// If no players in an active game, mark it as abandoned
 			if playerCount == 0 {
 				if err := s.roomRepo.UpdateStatus(room.ID.String(), "abandoned"); err != nil {
-					log.Printf("Error marking room %s as abandoned: %v", room.Code, err)
+					slog.Error("Error marking room as abandoned", "room", room.Code, "err", err)
 					continue
 				}
-				log.Printf("Marked empty game room as abandoned: %s", room.Code)
+				s.metrics.RoomCleanedUp("abandoned")
+				slog.Info("Marked empty game room as abandoned", "room", room.Code)
 			}
 		}
 	}
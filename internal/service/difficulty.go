@@ -0,0 +1,123 @@
+// internal/service/difficulty.go
+
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/repository"
+)
+
+const (
+    difficultyEasy   = 1
+    difficultyMedium = 2
+    difficultyHard   = 3
+
+    // Players needed before a question's difficulty is derived from answers
+    minAnswersForDifficulty = 10
+)
+
+// pickQuestion chooses the question for a round, from the room's pack if it
+// has one or else the shared bank. Questions already asked in the game are
+// avoided while there are others left. With the room's difficulty ramp on,
+// the question's tier follows the round number.
+func (s *GameService) pickQuestion(ctx context.Context, room *models.Room, roundNumber int) (*models.Question, error) {
+    var asked []string
+    if rounds, err := s.roundRepo.WithContext(ctx).GetRoomRounds(room.ID.String()); err == nil {
+        for _, round := range rounds {
+            asked = append(asked, round.QuestionID.String())
+        }
+    }
+
+    if room.DifficultyRamp {
+        target := rampDifficulty(roundNumber, room.MaxRounds)
+        for _, difficulty := range difficultyFallbacks(target) {
+            question, err := s.questionRepo.WithContext(ctx).GetRandomOfDifficulty(room.PackID, difficulty, asked)
+            if err != nil {
+                continue
+            }
+            if difficulty != target {
+                slog.Info("No questions of the target difficulty left", "room", room.Code,
+                    "target", target, "difficulty", difficulty)
+            }
+            return question, nil
+        }
+    }
+
+    if question, err := s.questionRepo.WithContext(ctx).GetRandomExcluding(room.PackID, asked); err == nil {
+        return question, nil
+    }
+
+    // Every question has been asked, so repeats are unavoidable
+    return s.questionRepo.WithContext(ctx).GetRandom(room.PackID)
+}
+
+// rampDifficulty splits a game into thirds: easy, medium, then hard
+func rampDifficulty(roundNumber int, maxRounds int) int {
+    if maxRounds < 1 || roundNumber < 1 {
+        return difficultyEasy
+    }
+    difficulty := difficultyEasy + (roundNumber-1)*3/maxRounds
+    if difficulty > difficultyHard {
+        difficulty = difficultyHard
+    }
+    return difficulty
+}
+
+// difficultyFallbacks lists the tiers to try for a target difficulty, the
+// closest first. Unrated questions (0) are treated as roughly medium.
+func difficultyFallbacks(target int) []int {
+    switch target {
+    case difficultyEasy:
+        return []int{difficultyEasy, difficultyMedium, 0, difficultyHard}
+    case difficultyHard:
+        return []int{difficultyHard, difficultyMedium, 0, difficultyEasy}
+    }
+    return []int{difficultyMedium, 0, difficultyEasy, difficultyHard}
+}
+
+// deriveDifficulty rates a question by the share of players who got it
+// right, or 0 if too few have answered it
+func deriveDifficulty(attempts int64, correct int64) int {
+    if attempts < minAnswersForDifficulty {
+        return 0
+    }
+
+    rate := float64(correct) / float64(attempts)
+    switch {
+    case rate >= 0.7:
+        return difficultyEasy
+    case rate >= 0.35:
+        return difficultyMedium
+    }
+    return difficultyHard
+}
+
+// refreshDerivedDifficulty recomputes a question's derived difficulty from
+// its answers in archived games, which outlive the rooms they were played in
+func refreshDerivedDifficulty(historyRepo repository.HistoryStore, questionRepo repository.QuestionStore, questionID string) error {
+    attempts, correct, err := historyRepo.AnswerStats(questionID)
+    if err != nil {
+        return err
+    }
+    return questionRepo.SetDerivedDifficulty(questionID, deriveDifficulty(attempts, correct))
+}
+
+// RefreshDerivedDifficulties recomputes the derived difficulty of every
+// question that has been played. It reports how many were updated.
+func (s *QuestionService) RefreshDerivedDifficulties() (int, error) {
+    ids, err := s.historyRepo.PlayedQuestionIDs()
+    if err != nil {
+        return 0, err
+    }
+
+    for _, id := range ids {
+        if err := refreshDerivedDifficulty(s.historyRepo, s.questionRepo, id); err != nil {
+            return 0, err
+        }
+    }
+    slog.Info("Refreshed derived difficulty", "questions", len(ids))
+    return len(ids), nil
+}
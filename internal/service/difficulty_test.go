@@ -0,0 +1,102 @@
+// internal/service/difficulty_test.go
+
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rohan03122001/quizzing/internal/models"
+)
+
+func TestRampDifficulty(t *testing.T) {
+    cases := map[int][]int{
+        1:  {1},
+        2:  {1, 2},
+        3:  {1, 2, 3},
+        5:  {1, 1, 2, 2, 3},
+        10: {1, 1, 1, 1, 2, 2, 2, 3, 3, 3},
+    }
+
+    for maxRounds, want := range cases {
+        var got []int
+        for round := 1; round <= maxRounds; round++ {
+            got = append(got, rampDifficulty(round, maxRounds))
+        }
+        if !reflect.DeepEqual(got, want) {
+            t.Errorf("%d rounds: got %v, want %v", maxRounds, got, want)
+        }
+    }
+}
+
+func TestDifficultyFallbacks(t *testing.T) {
+    for target := difficultyEasy; target <= difficultyHard; target++ {
+        tiers := difficultyFallbacks(target)
+        if tiers[0] != target || len(tiers) != 4 {
+            t.Errorf("target %d: got %v, want it first and every tier tried", target, tiers)
+        }
+    }
+}
+
+func TestDeriveDifficulty(t *testing.T) {
+    cases := []struct {
+        attempts, correct int64
+        want              int
+    }{
+        {5, 5, 0}, // Too few answers
+        {10, 8, difficultyEasy},
+        {20, 10, difficultyMedium},
+        {40, 4, difficultyHard},
+    }
+
+    for _, c := range cases {
+        if got := deriveDifficulty(c.attempts, c.correct); got != c.want {
+            t.Errorf("%d/%d correct: got %d, want %d", c.correct, c.attempts, got, c.want)
+        }
+    }
+}
+
+// The rating comes from archived games, which outlive the live rounds that
+// are deleted on restart and with idle rooms
+func TestDerivedDifficultyFromArchive(t *testing.T) {
+    forEachBackend(t, func(t *testing.T, stores testStores) {
+        question := &models.Question{Content: "Capital of Australia?", Answer: "Canberra"}
+        if err := stores.questions.CreateQuestion(question); err != nil {
+            t.Fatal(err)
+        }
+
+        // 2 of 10 players got it right; the skipped round doesn't count
+        played := models.SessionRound{RoundNumber: 1, QuestionID: question.ID, StartTime: time.Now()}
+        skipped := models.SessionRound{RoundNumber: 2, QuestionID: question.ID, Voided: true, StartTime: time.Now()}
+        for i := 0; i < 10; i++ {
+            player := fmt.Sprintf("player%d", i)
+            order := 0
+            if i < 2 {
+                order = i + 1
+            }
+            played.Answers = append(played.Answers, models.SessionAnswer{PlayerID: player, Answer: "Sydney"})
+            if order > 0 {
+                played.Answers = append(played.Answers, models.SessionAnswer{PlayerID: player, Answer: "Canberra", AnswerOrder: order})
+            }
+            skipped.Answers = append(skipped.Answers, models.SessionAnswer{PlayerID: player, Answer: "Canberra", AnswerOrder: i + 1})
+        }
+        session := &models.GameSession{RoomCode: "ABC123", EndedAt: time.Now(), Rounds: []models.SessionRound{played, skipped}}
+        if err := stores.history.SaveSession(session); err != nil {
+            t.Fatal(err)
+        }
+
+        count, err := NewQuestionService(stores.questions, stores.history).RefreshDerivedDifficulties()
+        if err != nil || count != 1 {
+            t.Fatalf("got %d, %v, want one question refreshed", count, err)
+        }
+        rated, err := stores.questions.GetByID(question.ID.String())
+        if err != nil {
+            t.Fatal(err)
+        }
+        if rated.DerivedDifficulty != difficultyHard {
+            t.Errorf("got derived difficulty %d, want hard", rated.DerivedDifficulty)
+        }
+    })
+}
@@ -0,0 +1,171 @@
+// internal/service/drain.go
+
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/rohan03122001/quizzing/internal/protocol"
+	"github.com/rohan03122001/quizzing/internal/websocket"
+)
+
+// How often WaitForGames looks for games still running
+const drainPollInterval = 500 * time.Millisecond
+
+// RunningGames returns the codes of rooms with a round counting down or an
+// intermission underway. Paused games aren't running; they're already
+// saved with the time they had left.
+func (s *GameService) RunningGames() []string {
+    running := make(map[string]bool)
+
+    s.timerMutex.RLock()
+    for roomCode, rt := range s.roundTimers {
+        if !rt.paused {
+            running[roomCode] = true
+        }
+    }
+    s.timerMutex.RUnlock()
+
+    s.intermissionMutex.Lock()
+    for roomCode := range s.intermissions {
+        running[roomCode] = true
+    }
+    s.intermissionMutex.Unlock()
+
+    codes := make([]string, 0, len(running))
+    for roomCode := range running {
+        codes = append(codes, roomCode)
+    }
+    sort.Strings(codes)
+    return codes
+}
+
+// WaitForGames blocks until no game is running, or ctx is done
+func (s *GameService) WaitForGames(ctx context.Context) error {
+    ticker := time.NewTicker(drainPollInterval)
+    defer ticker.Stop()
+
+    for {
+        running := s.RunningGames()
+        if len(running) == 0 {
+            return nil
+        }
+        slog.Debug("Waiting for games to finish", "rooms", running)
+
+        select {
+        case <-ctx.Done():
+            return ctx.Err()
+        case <-ticker.C:
+        }
+    }
+}
+
+// Checkpoint stops every running game so it can be picked up after a
+// restart. Rounds are paused and the pause saved, as if the host had paused
+// them; intermissions are stopped and the room marked, leaving the next
+// round to resume_game. It returns the codes of the rooms it stopped.
+func (s *GameService) Checkpoint(ctx context.Context) []string {
+    running := s.RunningGames()
+    for _, roomCode := range running {
+        s.cancelIntermission(roomCode)
+
+        room, round, err := s.activeRound(ctx, roomCode)
+        if err == ErrNoActiveRound {
+            s.checkpointBetweenRounds(ctx, roomCode)
+            continue
+        }
+        if err != nil {
+            continue
+        }
+        remaining, err := s.pauseRoundTimer(room.Code)
+        if err != nil {
+            continue
+        }
+        if err := s.roundRepo.WithContext(ctx).PauseRound(round.ID.String(), time.Now()); err != nil {
+            slog.Error("Error saving checkpoint", "room", room.Code, "round_id", round.ID, "err", err)
+            continue
+        }
+
+        s.hub.BroadcastToRoom(room.Code, websocket.GameEvent{
+            Type: protocol.EventGamePaused,
+            Data: protocol.GamePausedData{
+                RoundNumber: round.RoundNumber,
+                Remaining:   int(remaining.Round(time.Second) / time.Second),
+            },
+        })
+    }
+
+    if len(running) > 0 {
+        slog.Warn("Checkpointed games still running at shutdown", "rooms", running)
+    }
+    return running
+}
+
+// restorePausedTimer puts back the timer of a round that was paused by an
+// earlier run of the server, so it can be resumed
+func (s *GameService) restorePausedTimer(roomCode string, remaining time.Duration, tickUpdates bool) {
+    s.timerMutex.Lock()
+    defer s.timerMutex.Unlock()
+
+    if _, exists := s.roundTimers[roomCode]; exists {
+        return
+    }
+    s.roundTimers[roomCode] = &roundTimer{
+        tickUpdates: tickUpdates,
+        done:        make(chan struct{}),
+        paused:      true,
+        remaining:   remaining,
+    }
+}
+
+// inIntermission reports whether a room is between rounds
+func (s *GameService) inIntermission(roomCode string) bool {
+    s.intermissionMutex.Lock()
+    defer s.intermissionMutex.Unlock()
+
+    _, exists := s.intermissions[roomCode]
+    return exists
+}
+
+// checkpointBetweenRounds marks a game stopped during an intermission, so
+// resume_game knows to start its next round
+func (s *GameService) checkpointBetweenRounds(ctx context.Context, roomCode string) {
+    room, err := s.roomRepo.WithContext(ctx).GetByCode(roomCode)
+    if err != nil {
+        return
+    }
+    if err := s.roomRepo.WithContext(ctx).SetCheckpoint(room.ID.String(), time.Now()); err != nil {
+        slog.Error("Error saving checkpoint", "room", room.Code, "err", err)
+    }
+}
+
+// resumeBetweenRounds starts the next round of a game that was stopped
+// during an intermission, or ends it if that was the last round. Games
+// without a checkpoint are left alone: they're between rounds because this
+// server is still moving them on.
+func (s *GameService) resumeBetweenRounds(ctx context.Context, roomCode string) error {
+    room, err := s.roomRepo.WithContext(ctx).GetByCode(roomCode)
+    if err != nil {
+        return ErrRoomNotFound
+    }
+
+    claimed, err := s.roomRepo.WithContext(ctx).ClearCheckpoint(room.ID.String())
+    if err != nil {
+        return err
+    }
+    if !claimed {
+        return ErrGameNotPaused
+    }
+
+    if room.CurrentRound >= room.MaxRounds {
+        s.endGame(ctx, room.Code)
+        return nil
+    }
+
+    slog.Info("Resuming checkpointed game", "room", room.Code, "next_round", room.CurrentRound+1)
+    _, err = s.StartRound(ctx, room.Code)
+    return err
+}
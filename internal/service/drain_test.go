@@ -0,0 +1,152 @@
+// internal/service/drain_test.go
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+)
+
+func TestDrainRefusesNewGames(t *testing.T) {
+    forEachBackend(t, func(t *testing.T, stores testStores) {
+        g := newTestGame(t, stores, capitals...)
+        room := g.createRoom(t, nil)
+        g.join(t, room.Code, "alice", false)
+        g.join(t, room.Code, "bob", false)
+
+        g.rooms.Drain()
+        if _, err := g.rooms.CreateRoom(context.Background(), nil); !errors.Is(err, ErrServerDraining) {
+            t.Errorf("got %v creating a room, want ErrServerDraining", err)
+        }
+        if _, err := g.rooms.JoinRoom(context.Background(), room.Code, "carol"); !errors.Is(err, ErrServerDraining) {
+            t.Errorf("got %v joining, want ErrServerDraining", err)
+        }
+        if err := g.rooms.StartGame(context.Background(), room.Code); !errors.Is(err, ErrServerDraining) {
+            t.Errorf("got %v starting, want ErrServerDraining", err)
+        }
+    })
+}
+
+func TestCheckpointSurvivesRestart(t *testing.T) {
+    forEachBackend(t, func(t *testing.T, stores testStores) {
+        g := newTestGame(t, stores, capitals...)
+        room := g.createRoom(t, &models.GameSettings{MaxRounds: 2})
+        alice := g.join(t, room.Code, "alice", false)
+        g.join(t, room.Code, "bob", false)
+        g.start(t, room.Code)
+
+        if running := g.games.RunningGames(); len(running) != 1 || running[0] != room.Code {
+            t.Fatalf("got running games %v, want %s", running, room.Code)
+        }
+        ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+        defer cancel()
+        if err := g.games.WaitForGames(ctx); !errors.Is(err, context.DeadlineExceeded) {
+            t.Fatalf("got %v waiting on a running round, want the deadline", err)
+        }
+
+        if stopped := g.games.Checkpoint(context.Background()); len(stopped) != 1 {
+            t.Fatalf("got checkpointed rooms %v", stopped)
+        }
+        paused := waitFor(t, alice, protocol.EventGamePaused).Data.(protocol.GamePausedData)
+        if paused.RoundNumber != 1 || paused.Remaining < 28 {
+            t.Errorf("got game_paused %+v", paused)
+        }
+        if running := g.games.RunningGames(); len(running) != 0 {
+            t.Errorf("got running games %v after the checkpoint", running)
+        }
+
+        // A new server over the same database picks the round up where it
+        // was paused
+        restarted := NewGameService(g.roomRepo, stores.questions, g.roundRepo, g.historyRepo, g.leaderboards, g.hub)
+        if _, err := restarted.ProcessAnswer(context.Background(), room.Code, "alice", "Paris"); !errors.Is(err, ErrGamePaused) {
+            t.Errorf("got %v answering a checkpointed round, want ErrGamePaused", err)
+        }
+        if err := restarted.ResumeGame(context.Background(), room.Code); err != nil {
+            t.Fatalf("couldn't resume: %v", err)
+        }
+        resumed := waitFor(t, alice, protocol.EventGameResumed).Data.(protocol.GameResumedData)
+        if resumed.RoundNumber != 1 || resumed.Remaining < 28 {
+            t.Errorf("got game_resumed %+v", resumed)
+        }
+        restarted.stopRoundTimer(room.Code)
+    })
+}
+
+func TestCheckpointBetweenRounds(t *testing.T) {
+    forEachBackend(t, func(t *testing.T, stores testStores) {
+        g := newTestGame(t, stores, capitals...)
+        room := g.createRoom(t, &models.GameSettings{MaxRounds: 2, IntermissionTime: 30})
+        alice := g.join(t, room.Code, "alice", false)
+        g.join(t, room.Code, "bob", false)
+        first := g.start(t, room.Code)
+
+        for _, player := range []string{"alice", "bob"} {
+            if _, err := g.games.ProcessAnswer(context.Background(), room.Code, player, first.Answer); err != nil {
+                t.Fatal(err)
+            }
+        }
+        waitFor(t, alice, protocol.EventIntermission)
+
+        if stopped := g.games.Checkpoint(context.Background()); len(stopped) != 1 {
+            t.Fatalf("got checkpointed rooms %v", stopped)
+        }
+
+        restarted := NewGameService(g.roomRepo, stores.questions, g.roundRepo, g.historyRepo, g.leaderboards, g.hub)
+        if err := restarted.ResumeGame(context.Background(), room.Code); err != nil {
+            t.Fatalf("couldn't resume: %v", err)
+        }
+        started := waitFor(t, alice, protocol.EventRoundStarted).Data.(protocol.RoundStartedData)
+        if started.RoundNumber != 2 {
+            t.Errorf("got round_started %+v, want round 2", started)
+        }
+        restarted.stopRoundTimer(room.Code)
+
+        // Resuming clears the checkpoint, so it can't start a round twice
+        stored, err := g.roomRepo.GetByCode(room.Code)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if stored.CheckpointedAt != nil {
+            t.Errorf("the checkpoint is still set after resuming")
+        }
+    })
+}
+
+func TestResumeBetweenRoundsNeedsCheckpoint(t *testing.T) {
+    forEachBackend(t, func(t *testing.T, stores testStores) {
+        g := newTestGame(t, stores, capitals...)
+        room := g.createRoom(t, &models.GameSettings{MaxRounds: 2, IntermissionTime: 30})
+        alice := g.join(t, room.Code, "alice", false)
+        g.join(t, room.Code, "bob", false)
+        first := g.start(t, room.Code)
+
+        for _, player := range []string{"alice", "bob"} {
+            if _, err := g.games.ProcessAnswer(context.Background(), room.Code, player, first.Answer); err != nil {
+                t.Fatal(err)
+            }
+        }
+        waitFor(t, alice, protocol.EventIntermission)
+        defer g.games.cancelIntermission(room.Code)
+
+        // Between rounds without a checkpoint, e.g. in the moment between a
+        // round ending and its intermission starting, or another server
+        // still running the game
+        other := NewGameService(g.roomRepo, stores.questions, g.roundRepo, g.historyRepo, g.leaderboards, g.hub)
+        if err := other.ResumeGame(context.Background(), room.Code); !errors.Is(err, ErrGameNotPaused) {
+            t.Fatalf("got %v resuming an unpaused game, want ErrGameNotPaused", err)
+        }
+
+        stored, err := g.roomRepo.GetByCode(room.Code)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if stored.CurrentRound != 1 {
+            t.Errorf("got round %d, want the game left on round 1", stored.CurrentRound)
+        }
+    })
+}
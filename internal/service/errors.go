@@ -0,0 +1,67 @@
+// internal/service/errors.go
+
+package service
+
+import (
+	"errors"
+
+	"github.com/rohan03122001/quizzing/internal/protocol"
+)
+
+// Error is an error that carries a code clients can act on
+type Error struct {
+    Code    protocol.ErrorCode
+    Message string
+}
+
+func (e *Error) Error() string {
+    return e.Message
+}
+
+// NewError creates a coded error
+func NewError(code protocol.ErrorCode, message string) *Error {
+    return &Error{Code: code, Message: message}
+}
+
+// Errors returned by the services
+var (
+    ErrRoomNotFound       = NewError(protocol.CodeRoomNotFound, "room not found")
+    ErrRoomFull           = NewError(protocol.CodeRoomFull, "room is full")
+    ErrGameInProgress     = NewError(protocol.CodeGameInProgress, "game already in progress")
+    ErrGameAlreadyStarted = NewError(protocol.CodeGameInProgress, "game already started")
+    ErrGameNotInProgress  = NewError(protocol.CodeGameNotInProgress, "game not in progress")
+    ErrNotEnoughPlayers   = NewError(protocol.CodeNotEnoughPlayers, "need at least 2 players to start")
+    ErrNotHost            = NewError(protocol.CodeNotHost, "only the host can do that")
+    ErrNoActiveRound      = NewError(protocol.CodeRoundNotActive, "no active round")
+    ErrRoundNotActive     = NewError(protocol.CodeRoundNotActive, "round not active")
+    ErrGamePaused         = NewError(protocol.CodeGamePaused, "game is paused")
+    ErrGameNotPaused      = NewError(protocol.CodeGameNotPaused, "game is not paused")
+    ErrNotInIntermission  = NewError(protocol.CodeNotInIntermission, "not between rounds")
+    ErrAlreadyAnswered    = NewError(protocol.CodeAlreadyAnswered, "you already answered this round")
+    ErrNoQuestion         = NewError(protocol.CodeQuestionUnavailable, "failed to get question")
+    ErrQuestionNotFound   = NewError(protocol.CodeQuestionUnavailable, "question not found")
+    ErrGameNotFound       = NewError(protocol.CodeGameNotFound, "game not found")
+    ErrUsernameTaken      = NewError(protocol.CodeUsernameTaken, "username already registered")
+    ErrEmailTaken         = NewError(protocol.CodeEmailTaken, "email already registered")
+    ErrInvalidCredentials = NewError(protocol.CodeInvalidCredentials, "invalid username or password")
+    ErrInvalidToken       = NewError(protocol.CodeUnauthorized, "invalid or expired token")
+    ErrAccountBanned      = NewError(protocol.CodeAccountBanned, "account is banned")
+    ErrAccountNotFound    = NewError(protocol.CodeAccountNotFound, "account not found")
+    ErrAuthRequired       = NewError(protocol.CodeAuthRequired, "sign in to play as this account")
+    ErrForbidden          = NewError(protocol.CodeForbidden, "admin access required")
+    ErrUnknownQuestion    = NewError(protocol.CodeQuestionNotFound, "question not found")
+    ErrDuplicateQuestion  = NewError(protocol.CodeDuplicateQuestion, "a question with the same content already exists")
+    ErrPackNotFound       = NewError(protocol.CodePackNotFound, "question pack not found")
+    ErrPackInUse          = NewError(protocol.CodeGameInProgress, "question pack is in use by a game in progress")
+    ErrInvalidPlayer      = NewError(protocol.CodeInvalidPlayer, "Invalid player ID or player was not in this room")
+    ErrServerDraining     = NewError(protocol.CodeServerDraining, "server is shutting down, try again shortly")
+)
+
+// ErrorCodeOf returns the code of a coded error, or CodeInternal for anything else
+func ErrorCodeOf(err error) protocol.ErrorCode {
+    var coded *Error
+    if errors.As(err, &coded) {
+        return coded.Code
+    }
+    return protocol.CodeInternal
+}
@@ -0,0 +1,459 @@
+// internal/service/game_flow_test.go
+
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/config"
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+	"github.com/rohan03122001/quizzing/internal/repository"
+	"github.com/rohan03122001/quizzing/internal/repository/memory"
+	"github.com/rohan03122001/quizzing/internal/websocket"
+)
+
+// testStores is one backend's repositories
+type testStores struct {
+    rooms        repository.RoomStore
+    questions    repository.QuestionStore
+    rounds       repository.RoundStore
+    history      repository.HistoryStore
+    leaderboards repository.LeaderboardStore
+    accounts     *repository.AccountRepository // Only the GORM backends have accounts
+}
+
+// testBackends open a fresh, empty backend. The GORM repositories run on an
+// in-memory SQLite database, and on Postgres too when TEST_POSTGRES is set.
+// That uses the DB_* settings and empties the database, so point it at a
+// scratch one.
+var testBackends = map[string]func(t *testing.T) testStores{
+    "memory": func(t *testing.T) testStores {
+        db := memory.NewDatabase()
+        return testStores{
+            rooms:        memory.NewRoomRepository(db),
+            questions:    memory.NewQuestionRepository(db),
+            rounds:       memory.NewGameRoundRepository(db),
+            history:      memory.NewHistoryRepository(db),
+            leaderboards: memory.NewLeaderboardRepository(db),
+        }
+    },
+    "sqlite": func(t *testing.T) testStores {
+        return openTestDatabase(t, &repository.DBConfig{Driver: repository.DriverSQLite, Path: ":memory:"})
+    },
+    "postgres": func(t *testing.T) testStores {
+        if os.Getenv("TEST_POSTGRES") == "" {
+            t.Skip("set TEST_POSTGRES to run against Postgres")
+        }
+        cfg, _, err := config.Load(nil)
+        if err != nil {
+            t.Fatal(err)
+        }
+        return openTestDatabase(t, &repository.DBConfig{
+            Driver:   repository.DriverPostgres,
+            Host:     cfg.Database.Host,
+            Port:     strconv.Itoa(cfg.Database.Port),
+            User:     cfg.Database.User,
+            Password: cfg.Database.Password,
+            DBName:   cfg.Database.DBName,
+            SSLMode:  cfg.Database.SSLMode,
+        })
+    },
+}
+
+func openTestDatabase(t *testing.T, dbConfig *repository.DBConfig) testStores {
+    t.Helper()
+    db, err := repository.NewDatabase(dbConfig)
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { db.Close() })
+    if _, err := db.MigrateUp(); err != nil {
+        t.Fatal(err)
+    }
+
+    if dbConfig.Driver == repository.DriverPostgres {
+        err := db.Exec(`TRUNCATE rooms, accounts, auth_tokens, questions, question_packs, game_rounds,
+            player_answers, game_sessions, session_players, session_rounds, session_answers,
+            leaderboard_entries CASCADE`).Error
+        if err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    return testStores{
+        rooms:        repository.NewRoomRepository(db),
+        questions:    repository.NewQuestionRepository(db),
+        rounds:       repository.NewGameRoundRepository(db),
+        history:      repository.NewHistoryRepository(db),
+        leaderboards: repository.NewLeaderboardRepository(db),
+        accounts:     repository.NewAccountRepository(db),
+    }
+}
+
+// forEachBackend runs a test once per backend
+func forEachBackend(t *testing.T, test func(t *testing.T, stores testStores)) {
+    for _, name := range []string{"memory", "sqlite", "postgres"} {
+        open := testBackends[name]
+        t.Run(name, func(t *testing.T) {
+            test(t, open(t))
+        })
+    }
+}
+
+// testGame runs the game services against a backend and a real hub, with
+// players connected as event stream clients
+type testGame struct {
+    hub          *websocket.Hub
+    rooms        *RoomService
+    games        *GameService
+    roomRepo     repository.RoomStore
+    roundRepo    repository.RoundStore
+    historyRepo  repository.HistoryStore
+    leaderboards repository.LeaderboardStore
+}
+
+func newTestGame(t *testing.T, stores testStores, questions ...models.Question) *testGame {
+    t.Helper()
+
+    g := &testGame{
+        hub:          websocket.NewHub(),
+        roomRepo:     stores.rooms,
+        roundRepo:    stores.rounds,
+        historyRepo:  stores.history,
+        leaderboards: stores.leaderboards,
+    }
+    go g.hub.Run()
+
+    for i := range questions {
+        // Each backend gets its own copies
+        question := questions[i]
+        if err := stores.questions.CreateQuestion(&question); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    g.rooms = NewRoomService(g.roomRepo, g.hub)
+    g.games = NewGameService(g.roomRepo, stores.questions, g.roundRepo, g.historyRepo, g.leaderboards, g.hub)
+    return g
+}
+
+var capitals = []models.Question{
+    {Content: "What is the capital of France?", Answer: "Paris", Category: "Geography"},
+    {Content: "What is the capital of India?", Answer: "New Delhi", Aliases: models.StringList{"Delhi"}, Category: "Geography"},
+}
+
+func (g *testGame) createRoom(t *testing.T, settings *models.GameSettings) *models.Room {
+    t.Helper()
+    room, err := g.rooms.CreateRoom(context.Background(), settings)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return room
+}
+
+// join joins a player to a room and connects them, as the join_room handler
+// does. Signed-in players get their account ID as the player ID.
+func (g *testGame) join(t *testing.T, roomCode string, username string, signedIn bool) *websocket.Client {
+    t.Helper()
+
+    playerID := username
+    if signedIn {
+        playerID = uuid.NewString()
+    }
+    if _, err := g.rooms.JoinRoom(context.Background(), roomCode, playerID); err != nil {
+        t.Fatalf("%s couldn't join: %v", username, err)
+    }
+
+    client := websocket.NewEventStreamClient(g.hub, playerID)
+    client.RoomID = roomCode
+    client.Username = username
+    if signedIn {
+        client.AccountID = playerID
+    }
+    g.hub.Register <- client
+
+    // Registration finishes on the hub's goroutine
+    for deadline := time.Now().Add(time.Second); !g.hub.IsPlayerConnected(roomCode, playerID); {
+        if time.Now().After(deadline) {
+            t.Fatalf("%s never registered with the hub", username)
+        }
+        time.Sleep(time.Millisecond)
+    }
+    return client
+}
+
+// start starts the game and its first round, as the start_game handler does
+func (g *testGame) start(t *testing.T, roomCode string) *models.Question {
+    t.Helper()
+    if err := g.rooms.StartGame(context.Background(), roomCode); err != nil {
+        t.Fatalf("couldn't start: %v", err)
+    }
+    question, err := g.games.StartRound(context.Background(), roomCode)
+    if err != nil {
+        t.Fatalf("couldn't start the first round: %v", err)
+    }
+    return question
+}
+
+// waitFor reads a client's events until one of the given type arrives
+func waitFor(t *testing.T, client *websocket.Client, eventType string) *websocket.GameEvent {
+    t.Helper()
+    timeout := time.After(5 * time.Second)
+    for {
+        select {
+        case event, ok := <-client.Events():
+            if !ok {
+                t.Fatalf("%s was dropped waiting for %s", client.ID, eventType)
+            }
+            if event.Type == eventType {
+                return event
+            }
+        case <-timeout:
+            t.Fatalf("%s got no %s event", client.ID, eventType)
+        }
+    }
+}
+
+func TestJoinRoom(t *testing.T) {
+    forEachBackend(t, func(t *testing.T, stores testStores) {
+        g := newTestGame(t, stores, capitals...)
+        room := g.createRoom(t, &models.GameSettings{MaxPlayers: 2})
+
+        g.join(t, room.Code, "alice", false)
+        g.join(t, room.Code, "bob", false)
+
+        joined, err := g.rooms.GetRoom(room.Code)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if joined.HostID != "alice" {
+            t.Errorf("got host %q, want the first player to join", joined.HostID)
+        }
+        if err := g.rooms.RequireHost(context.Background(), room.Code, "bob"); !errors.Is(err, ErrNotHost) {
+            t.Errorf("got %v for bob, want ErrNotHost", err)
+        }
+
+        if _, err := g.rooms.JoinRoom(context.Background(), room.Code, "carol"); !errors.Is(err, ErrRoomFull) {
+            t.Errorf("got %v joining a full room, want ErrRoomFull", err)
+        }
+        if _, err := g.rooms.JoinRoom(context.Background(), "NOPE42", "carol"); !errors.Is(err, ErrRoomNotFound) {
+            t.Errorf("got %v joining a missing room, want ErrRoomNotFound", err)
+        }
+    })
+}
+
+func TestStartGame(t *testing.T) {
+    forEachBackend(t, func(t *testing.T, stores testStores) {
+        g := newTestGame(t, stores, capitals...)
+        room := g.createRoom(t, nil)
+
+        alice := g.join(t, room.Code, "alice", false)
+        if err := g.rooms.StartGame(context.Background(), room.Code); !errors.Is(err, ErrNotEnoughPlayers) {
+            t.Fatalf("got %v starting alone, want ErrNotEnoughPlayers", err)
+        }
+
+        g.join(t, room.Code, "bob", false)
+        question := g.start(t, room.Code)
+
+        started := waitFor(t, alice, protocol.EventRoundStarted).Data.(protocol.RoundStartedData)
+        if started.RoundNumber != 1 || started.Question.ID != question.ID.String() || started.TimeLimit != 30 {
+            t.Errorf("got round_started %+v", started)
+        }
+
+        playing, _ := g.rooms.GetRoom(room.Code)
+        if playing.Status != "playing" || playing.CurrentRound != 1 {
+            t.Errorf("got room status %q round %d, want playing round 1", playing.Status, playing.CurrentRound)
+        }
+        if err := g.rooms.StartGame(context.Background(), room.Code); !errors.Is(err, ErrGameAlreadyStarted) {
+            t.Errorf("got %v starting twice, want ErrGameAlreadyStarted", err)
+        }
+        if _, err := g.rooms.JoinRoom(context.Background(), room.Code, "carol"); !errors.Is(err, ErrGameInProgress) {
+            t.Errorf("got %v joining mid-game, want ErrGameInProgress", err)
+        }
+    })
+}
+
+func TestProcessAnswer(t *testing.T) {
+    forEachBackend(t, func(t *testing.T, stores testStores) {
+        g := newTestGame(t, stores, capitals[1])
+        room := g.createRoom(t, nil)
+        g.join(t, room.Code, "alice", false)
+        g.join(t, room.Code, "bob", false)
+        g.join(t, room.Code, "carol", false)
+        g.start(t, room.Code)
+
+        result, err := g.games.ProcessAnswer(context.Background(), room.Code, "alice", "Mumbai")
+        if err != nil || result.Correct {
+            t.Fatalf("got %+v, %v for a wrong answer", result, err)
+        }
+
+        // An alias counts, as does different case and punctuation
+        result, err = g.games.ProcessAnswer(context.Background(), room.Code, "bob", "delhi!")
+        if err != nil || !result.Correct || result.Order != 1 || result.Score != 1000 {
+            t.Fatalf("got %+v, %v for the first right answer", result, err)
+        }
+        result, err = g.games.ProcessAnswer(context.Background(), room.Code, "alice", "New Delhi")
+        if err != nil || !result.Correct || result.Order != 2 || result.Score != 750 {
+            t.Fatalf("got %+v, %v for the second right answer", result, err)
+        }
+
+        if _, err := g.games.ProcessAnswer(context.Background(), room.Code, "bob", "New Delhi"); !errors.Is(err, ErrAlreadyAnswered) {
+            t.Errorf("got %v answering twice, want ErrAlreadyAnswered", err)
+        }
+        if _, err := g.games.ProcessAnswer(context.Background(), "NOPE42", "bob", "Delhi"); !errors.Is(err, ErrRoomNotFound) {
+            t.Errorf("got %v for a missing room, want ErrRoomNotFound", err)
+        }
+    })
+}
+
+func TestConcurrentCorrectAnswers(t *testing.T) {
+    forEachBackend(t, func(t *testing.T, stores testStores) {
+        g := newTestGame(t, stores, capitals...)
+        room := g.createRoom(t, &models.GameSettings{MaxRounds: 1})
+        g.join(t, room.Code, "alice", false)
+        g.join(t, room.Code, "bob", false)
+        first := g.start(t, room.Code)
+        defer g.games.stopRoundTimer(room.Code)
+
+        // The same player submits the right answer many times at once
+        const attempts = 8
+        var wg sync.WaitGroup
+        results := make(chan error, attempts)
+        for i := 0; i < attempts; i++ {
+            wg.Add(1)
+            go func() {
+                defer wg.Done()
+                _, err := g.games.ProcessAnswer(context.Background(), room.Code, "alice", first.Answer)
+                results <- err
+            }()
+        }
+        wg.Wait()
+        close(results)
+
+        scored := 0
+        for err := range results {
+            switch {
+            case err == nil:
+                scored++
+            case !errors.Is(err, ErrAlreadyAnswered):
+                t.Errorf("got %v, want ErrAlreadyAnswered for the repeats", err)
+            }
+        }
+        if scored != 1 {
+            t.Errorf("alice scored %d times, want once", scored)
+        }
+
+        round, err := g.roundRepo.GetCurrentRound(room.ID.String())
+        if err != nil {
+            t.Fatal(err)
+        }
+        if round.AnswerCount != 1 {
+            t.Errorf("got answer count %d, want 1", round.AnswerCount)
+        }
+    })
+}
+
+func TestRoundEndsWhenEveryoneAnswers(t *testing.T) {
+    forEachBackend(t, func(t *testing.T, stores testStores) {
+        g := newTestGame(t, stores, capitals...)
+        room := g.createRoom(t, &models.GameSettings{MaxRounds: 2, IntermissionTime: 1})
+        alice := g.join(t, room.Code, "alice", false)
+        g.join(t, room.Code, "bob", false)
+        first := g.start(t, room.Code)
+
+        for _, player := range []string{"alice", "bob"} {
+            if _, err := g.games.ProcessAnswer(context.Background(), room.Code, player, first.Answer); err != nil {
+                t.Fatal(err)
+            }
+        }
+
+        result := waitFor(t, alice, protocol.EventRoundResult).Data.(protocol.RoundResultData)
+        if result.RoundNumber != 1 || result.CorrectAnswer != first.Answer || len(result.Answers) != 2 {
+            t.Errorf("got round_result %+v", result)
+        }
+        if _, err := g.games.ProcessAnswer(context.Background(), room.Code, "alice", first.Answer); !errors.Is(err, ErrNoActiveRound) {
+            t.Errorf("got %v answering after the round, want ErrNoActiveRound", err)
+        }
+
+        intermission := waitFor(t, alice, protocol.EventIntermission).Data.(protocol.IntermissionData)
+        if intermission.NextRound != 2 {
+            t.Errorf("got intermission before round %d, want 2", intermission.NextRound)
+        }
+
+        // The second round asks the question that hasn't been asked yet
+        started := waitFor(t, alice, protocol.EventRoundStarted).Data.(protocol.RoundStartedData)
+        if started.RoundNumber != 2 || started.Question.ID == first.ID.String() {
+            t.Errorf("got round_started %+v after asking %s", started, first.ID)
+        }
+    })
+}
+
+func TestGameEnd(t *testing.T) {
+    forEachBackend(t, func(t *testing.T, stores testStores) {
+        g := newTestGame(t, stores, capitals...)
+        room := g.createRoom(t, &models.GameSettings{MaxRounds: 1})
+        alice := g.join(t, room.Code, "alice", true)
+        g.join(t, room.Code, "bob", false)
+        question := g.start(t, room.Code)
+
+        if _, err := g.games.ProcessAnswer(context.Background(), room.Code, alice.ID, question.Answer); err != nil {
+            t.Fatal(err)
+        }
+        if _, err := g.games.ProcessAnswer(context.Background(), room.Code, "bob", "no idea"); err != nil {
+            t.Fatal(err)
+        }
+
+        // Bob hasn't got it, so the round would run to its deadline. End it
+        // now, the way the timer does.
+        if !g.games.stopRoundTimer(room.Code) {
+            t.Fatal("the round should have a timer running")
+        }
+        g.games.handleRoundEnd(context.Background(), room.Code)
+
+        end := waitFor(t, alice, protocol.EventGameEnd).Data.(protocol.GameEndData)
+        if end.TotalRounds != 1 || len(end.FinalResults) != 2 {
+            t.Fatalf("got game_end %+v", end)
+        }
+        winner, loser := end.FinalResults[0], end.FinalResults[1]
+        if winner.PlayerID != alice.ID || winner.TotalScore != 1000 || winner.Rank != 1 {
+            t.Errorf("got winner %+v", winner)
+        }
+        if loser.PlayerID != "bob" || loser.TotalScore != 0 || loser.Rank != 2 {
+            t.Errorf("got runner-up %+v", loser)
+        }
+
+        finished, _ := g.rooms.GetRoom(room.Code)
+        if finished.Status != "finished" {
+            t.Errorf("got room status %q, want finished", finished.Status)
+        }
+
+        // The game is archived, and only the signed-in player is ranked
+        games, total, err := g.historyRepo.ListByRoomCode(room.Code, 10, 0)
+        if err != nil || total != 1 {
+            t.Fatalf("got %d archived games, %v", total, err)
+        }
+        session, err := g.historyRepo.GetSession(games[0].ID.String())
+        if err != nil {
+            t.Fatal(err)
+        }
+        if len(session.Rounds) != 1 || session.Rounds[0].Answer != question.Answer || len(session.Rounds[0].Answers) != 2 {
+            t.Errorf("got archived rounds %+v", session.Rounds)
+        }
+
+        entries, _, err := g.leaderboards.List(BoardAllTime, 10, 0)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if len(entries) != 1 || entries[0].Username != "alice" || entries[0].Score != 1000 || entries[0].Wins != 1 {
+            t.Errorf("got all-time leaderboard %+v", entries)
+        }
+    })
+}
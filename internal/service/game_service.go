@@ -3,225 +3,253 @@
 package service
 
 import (
+	"context"
 	"errors"
-	"log"
+	"log/slog"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/metrics"
 	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/protocol"
 	"github.com/rohan03122001/quizzing/internal/repository"
+	"github.com/rohan03122001/quizzing/internal/tracing"
 	"github.com/rohan03122001/quizzing/internal/websocket"
 )
 
 type GameService struct {
-    roomRepo     *repository.RoomRepository
-    questionRepo *repository.QuestionRepository
-    roundRepo    *repository.GameRoundRepository
-    hub          *websocket.Hub
-    roundTimers  map[string]*time.Timer  // tracks room timers
-    timerMutex   sync.RWMutex           // protects roundTimers map
+    roomRepo        repository.RoomStore
+    questionRepo    repository.QuestionStore
+    roundRepo       repository.RoundStore
+    historyRepo     repository.HistoryStore
+    leaderboardRepo repository.LeaderboardStore
+    hub             *websocket.Hub
+    roundTimers     map[string]*roundTimer  // tracks room timers
+    timerMutex      sync.RWMutex           // protects roundTimers map
+
+    intermissions     map[string]*intermission // rooms between rounds
+    intermissionMutex sync.Mutex               // protects intermissions and their ready sets
+
+    metrics *metrics.Metrics // answer and round timings, nil when not collected
 }
 
-type RoundResult struct {
-    Correct bool `json:"correct"`
-    Score   int  `json:"score"`
-    Order   int  `json:"order"`
-    Message string  `json:"message"`
+// How long before the deadline the timer_warning event is sent
+const timerWarningBefore = 5 * time.Second
+
+// roundTimer ends a room's current round at its deadline. A paused timer
+// stays in the map with the time that was left when it was paused.
+type roundTimer struct {
+    timer       *time.Timer
+    deadline    time.Time
+    tickUpdates bool
+    done        chan struct{} // closed when the timer is stopped or paused
+    paused      bool
+    remaining   time.Duration
 }
 
-// Add this new struct for final results
-type PlayerResult struct {
-    PlayerID   string         `json:"player_id"`
-    Username   string         `json:"username"`
-    TotalScore int           `json:"total_score"`
-    Rank       int           `json:"rank"`
-    Rounds     []RoundResult `json:"rounds"`
-}
+// RoundResult is a player's outcome for a single round
+type RoundResult = protocol.RoundResult
+
+// PlayerResult is a player's final standing in a game
+type PlayerResult = protocol.PlayerResult
 
 func NewGameService(
-    roomRepo *repository.RoomRepository,
-    questionRepo *repository.QuestionRepository,
-    roundRepo *repository.GameRoundRepository,
+    roomRepo repository.RoomStore,
+    questionRepo repository.QuestionStore,
+    roundRepo repository.RoundStore,
+    historyRepo repository.HistoryStore,
+    leaderboardRepo repository.LeaderboardStore,
     hub *websocket.Hub,
 ) *GameService {
     return &GameService{
-        roomRepo:     roomRepo,
-        questionRepo: questionRepo,
-        roundRepo:    roundRepo,
-        hub:         hub,
-        roundTimers: make(map[string]*time.Timer),
+        roomRepo:        roomRepo,
+        questionRepo:    questionRepo,
+        roundRepo:       roundRepo,
+        historyRepo:     historyRepo,
+        leaderboardRepo: leaderboardRepo,
+        hub:             hub,
+        roundTimers:     make(map[string]*roundTimer),
+        intermissions:   make(map[string]*intermission),
     }
 }
 
+// SetMetrics sets where answer and round timings are recorded
+func (s *GameService) SetMetrics(m *metrics.Metrics) {
+    s.metrics = m
+}
+
 // InitializeGame sets up a new game
-func (s *GameService) InitializeGame(roomCode string) error {
-    room, err := s.roomRepo.GetByCode(roomCode)
+func (s *GameService) InitializeGame(ctx context.Context, roomCode string) error {
+    ctx, span := startSpan(ctx, "GameService.InitializeGame", roomCode)
+    defer span.End()
+
+    room, err := s.roomRepo.WithContext(ctx).GetByCode(roomCode)
     if err != nil {
-        log.Printf("Failed to get room %s: %v", roomCode, err)
-        return errors.New("room not found")
+        slog.Debug("Room not found", "room", roomCode, "err", err)
+        return ErrRoomNotFound
     }
 
     room.CurrentRound = 0
     room.Status = "playing"
 
-    if err := s.roomRepo.UpdateStatus(room.ID.String(), "playing"); err != nil {
-        log.Printf("Failed to update room status: %v", err)
+    if err := s.roomRepo.WithContext(ctx).UpdateStatus(room.ID.String(), "playing"); err != nil {
+        slog.Error("Failed to update room status", "room", roomCode, "err", err)
         return err
     }
 
-    log.Printf("Game initialized in room %s", roomCode)
+    slog.Info("Game initialized", "room", roomCode)
     return nil
 }
 
 // StartRound begins a new round for a room
-func (s *GameService) StartRound(roomCode string) (*models.Question, error) {
-    room, err := s.roomRepo.GetByCode(roomCode)
+func (s *GameService) StartRound(ctx context.Context, roomCode string) (*models.Question, error) {
+    ctx, span := startSpan(ctx, "GameService.StartRound", roomCode)
+    defer span.End()
+
+    room, err := s.roomRepo.WithContext(ctx).GetByCode(roomCode)
     if err != nil {
-        log.Printf("Failed to get room %s: %v", roomCode, err)
-        return nil, errors.New("room not found")
+        slog.Debug("Room not found", "room", roomCode, "err", err)
+        return nil, ErrRoomNotFound
     }
 
     if room.Status != "playing" {
-        return nil, errors.New("game not in progress")
+        return nil, ErrGameNotInProgress
     }
 
-    // Get random question
-    question, err := s.questionRepo.GetRandom()
+    question, err := s.beginRound(ctx, room, room.CurrentRound+1)
+    if err != nil {
+        return nil, err
+    }
+
+    // Update room's current round
+    if err := s.roomRepo.WithContext(ctx).UpdateCurrentRound(room.ID.String()); err != nil {
+        slog.Error("Failed to update current round", "room", roomCode, "err", err)
+        return nil, err
+    }
+
+    return question, nil
+}
+
+// beginRound asks a new question as the given round number, starts its
+// timer and broadcasts it
+func (s *GameService) beginRound(ctx context.Context, room *models.Room, roundNumber int) (*models.Question, error) {
+    question, err := s.pickQuestion(ctx, room, roundNumber)
     if err != nil {
-        log.Printf("Failed to get question: %v", err)
-        return nil, errors.New("failed to get question")
+        slog.Error("Failed to get question", "room", room.Code, "round", roundNumber, "err", err)
+        return nil, ErrNoQuestion
     }
 
     // Create new round
+    now := time.Now()
     round := &models.GameRound{
         RoomID:      room.ID,
         QuestionID:  question.ID,
-        StartTime:   time.Now(),
-        EndTime:     time.Now().Add(time.Duration(room.RoundTime) * time.Second),
-        RoundNumber: room.CurrentRound + 1,
+        StartTime:   now,
+        EndTime:     now.Add(time.Duration(room.RoundTime) * time.Second),
+        RoundNumber: roundNumber,
         State:       "active",
     }
 
-    if err := s.roundRepo.CreateRound(round); err != nil {
-        log.Printf("Failed to create round: %v", err)
+    if err := s.roundRepo.WithContext(ctx).CreateRound(round); err != nil {
+        slog.Error("Failed to create round", "room", room.Code, "round", roundNumber, "err", err)
         return nil, err
     }
 
     // Start round timer
-    s.startRoundTimer(roomCode, room.RoundTime)
-
-    // Update room's current round
-    if err := s.roomRepo.UpdateCurrentRound(room.ID.String()); err != nil {
-        log.Printf("Failed to update current round: %v", err)
-        return nil, err
-    }
+    s.startRoundTimer(room.Code, round.EndTime, room.TimerUpdates)
+
+    slog.Info("Started round", "room", room.Code, "round", round.RoundNumber, "question_id", question.ID)
+
+    s.hub.BroadcastToRoom(room.Code, websocket.GameEvent{
+        Type: protocol.EventRoundStarted,
+        Data: protocol.RoundStartedData{
+            Question:    toProtocolQuestion(question),
+            RoundNumber: round.RoundNumber,
+            TimeLimit:   room.RoundTime,
+            Deadline:    round.EndTime.UnixMilli(),
+            ServerTime:  time.Now().UnixMilli(),
+        },
+    })
 
-    log.Printf("Started round %d in room %s with question ID %s", 
-        round.RoundNumber, roomCode, question.ID)
     return question, nil
 }
 
 // ProcessAnswer handles a player's answer submission
-func (s *GameService) ProcessAnswer(roomCode string, playerID string, answer string) (*RoundResult, error) {
-    room, err := s.roomRepo.GetByCode(roomCode)
+func (s *GameService) ProcessAnswer(ctx context.Context, roomCode string, playerID string, answer string) (*RoundResult, error) {
+    ctx, span := startSpan(ctx, "GameService.ProcessAnswer", roomCode, tracing.PlayerKey.String(playerID))
+    defer span.End()
+
+    room, err := s.roomRepo.WithContext(ctx).GetByCode(roomCode)
     if err != nil {
-        return nil, errors.New("room not found")
+        return nil, ErrRoomNotFound
     }
 
-    round, err := s.roundRepo.GetCurrentRound(room.ID.String())
+    round, err := s.roundRepo.WithContext(ctx).GetCurrentRound(room.ID.String())
     if err != nil {
-        log.Printf("No active round found for room %s: %v", roomCode, err)
-        return nil, errors.New("no active round")
+        slog.Debug("No active round", "room", roomCode, "player", playerID, "err", err)
+        return nil, ErrNoActiveRound
     }
 
     if round.State != "active" {
-        return nil, errors.New("round not active")
+        return nil, ErrRoundNotActive
     }
 
-    question, err := s.questionRepo.GetByID(round.QuestionID.String())
-    if err != nil {
-        return nil, errors.New("question not found")
+    if round.PausedAt != nil {
+        return nil, ErrGamePaused
     }
 
-    // Add debugging for answer comparison
-    submitted := strings.TrimSpace(answer)
-    correct := strings.TrimSpace(question.Answer)
-    
-    log.Printf("Answer comparison - Submitted: '%s' (len: %d), Correct: '%s' (len: %d)", 
-        submitted, len(submitted), correct, len(correct))
-    
-    // Try multiple comparison strategies
-    isCorrect := false
-    
-    // 1. Case-insensitive with trimming (original method)
-    if strings.EqualFold(submitted, correct) {
-        isCorrect = true
-        log.Printf("Answer matched using EqualFold")
+    // A player only scores once per round
+    answered, err := s.roundRepo.WithContext(ctx).HasCorrectAnswer(round.ID.String(), playerID)
+    if err != nil {
+        return nil, err
     }
-    
-    // 2. Normalized comparison
-    if !isCorrect {
-        // Convert to lowercase and trim
-        submittedNorm := strings.ToLower(submitted)
-        correctNorm := strings.ToLower(correct)
-        if submittedNorm == correctNorm {
-            isCorrect = true
-            log.Printf("Answer matched using lowercase normalization")
-        }
+    if answered {
+        return nil, ErrAlreadyAnswered
     }
-    
-    // 3. Fuzzy matching (more lenient)
-    if !isCorrect {
-        // Remove punctuation, extra spaces, and compare
-        submittedClean := cleanStringForComparison(submitted)
-        correctClean := cleanStringForComparison(correct)
-        
-        log.Printf("Cleaned for comparison - Submitted: '%s', Correct: '%s'", 
-            submittedClean, correctClean)
-            
-        if submittedClean == correctClean {
-            isCorrect = true
-            log.Printf("Answer matched using cleaned comparison")
-        }
+
+    question, err := s.questionRepo.WithContext(ctx).GetByID(round.QuestionID.String())
+    if err != nil {
+        return nil, ErrQuestionNotFound
     }
-    
-    if isCorrect {
-        // Increment answer count
-        if err := s.roundRepo.UpdateAnswerCount(round.ID.String()); err != nil {
-            return nil, err
-        }
-        round.AnswerCount++
 
-        // Calculate score based on answer order
-        score := s.calculateScore(round.AnswerCount)
+    isCorrect := answerMatches(answer, question)
+    slog.Debug("Compared answer", "room", roomCode, "player", playerID,
+        "submitted", strings.TrimSpace(answer), "correct", question.Answer, "matched", isCorrect)
 
-        // Save answer
+    if isCorrect {
         playerAnswer := &models.PlayerAnswer{
-            RoundID:     round.ID,
-            PlayerID:    playerID,
-            Answer:      answer,
-            Score:       score,
-            AnswerOrder: round.AnswerCount,
-            AnsweredAt:  time.Now(),
+            RoundID:    round.ID,
+            PlayerID:   playerID,
+            Answer:     answer,
+            AnsweredAt: time.Now(),
         }
 
-        if err := s.roundRepo.SaveAnswer(playerAnswer); err != nil {
+        // Counts the answer and scores it by its order, all at once so two
+        // submissions from the same player can't both score
+        err := s.roundRepo.WithContext(ctx).SaveCorrectAnswer(playerAnswer, s.calculateScore)
+        if errors.Is(err, repository.ErrAlreadyAnswered) {
+            return nil, ErrAlreadyAnswered
+        }
+        if err != nil {
             return nil, err
         }
+        round.AnswerCount = playerAnswer.AnswerOrder
+        score := playerAnswer.Score
+        s.metrics.Answer(true, playerAnswer.AnsweredAt.Sub(round.StartTime))
 
-        log.Printf("Player %s submitted correct answer in room %s (order: %d, score: %d)", 
-            playerID, roomCode, round.AnswerCount, score)
+        slog.Info("Correct answer", "room", roomCode, "player", playerID, "order", round.AnswerCount, "score", score)
 
         // Check if all players have answered
         playerCount := s.hub.GetPlayerCount(roomCode)
         if round.AnswerCount >= playerCount {
-            // Stop the timer before handling round end
-            s.stopRoundTimer(roomCode)
-            
-            // Handle round end
-            go s.handleRoundEnd(roomCode)
+            // Stop the timer before handling round end. If it already
+            // fired, the timer is ending the round itself.
+            if s.stopRoundTimer(roomCode) {
+                go s.handleRoundEnd(context.WithoutCancel(ctx), roomCode)
+            }
         }
 
         return &RoundResult{
@@ -231,8 +259,7 @@ func (s *GameService) ProcessAnswer(roomCode string, playerID string, answer str
         }, nil
     }
 
-    // Log incorrect answer
-    log.Printf("Player %s submitted incorrect answer in room %s", playerID, roomCode)
+    slog.Info("Incorrect answer", "room", roomCode, "player", playerID)
 
     // Save incorrect answer
     playerAnswer := &models.PlayerAnswer{
@@ -243,7 +270,8 @@ func (s *GameService) ProcessAnswer(roomCode string, playerID string, answer str
         AnswerOrder: 0,
         AnsweredAt:  time.Now(),
     }
-    s.roundRepo.SaveAnswer(playerAnswer)
+    s.roundRepo.WithContext(ctx).SaveAnswer(playerAnswer)
+    s.metrics.Answer(false, playerAnswer.AnsweredAt.Sub(round.StartTime))
 
     return &RoundResult{
         Correct: false,
@@ -253,6 +281,27 @@ func (s *GameService) ProcessAnswer(roomCode string, playerID string, answer str
     }, nil
 }
 
+// answerMatches checks a submitted answer against the question's answer
+// and its aliases
+func answerMatches(answer string, question *models.Question) bool {
+    submitted := strings.TrimSpace(answer)
+    for _, accepted := range append([]string{question.Answer}, question.Aliases...) {
+        if matchesAnswer(submitted, strings.TrimSpace(accepted)) {
+            return true
+        }
+    }
+    return false
+}
+
+// matchesAnswer compares a submitted answer with one accepted answer,
+// ignoring case, punctuation and extra spaces
+func matchesAnswer(submitted string, correct string) bool {
+    if strings.EqualFold(submitted, correct) {
+        return true
+    }
+    return cleanStringForComparison(submitted) == cleanStringForComparison(correct)
+}
+
 // Helper function to clean strings for comparison
 func cleanStringForComparison(s string) string {
     // Convert to lowercase
@@ -273,136 +322,367 @@ func cleanStringForComparison(s string) string {
     return strings.TrimSpace(s)
 }
 
-// Add new method to safely stop timer
-func (s *GameService) stopRoundTimer(roomCode string) {
+// stopRoundTimer stops a room's timer. It reports whether there was one
+// running, so only one caller goes on to end the round.
+func (s *GameService) stopRoundTimer(roomCode string) bool {
     s.timerMutex.Lock()
     defer s.timerMutex.Unlock()
     
-    if timer, exists := s.roundTimers[roomCode]; exists {
-        timer.Stop()
-        delete(s.roundTimers, roomCode)
-        log.Printf("Stopped timer for room %s", roomCode)
+    rt, exists := s.roundTimers[roomCode]
+    if !exists {
+        return false
+    }
+
+    if !rt.paused {
+        rt.timer.Stop()
+        close(rt.done)
     }
+    delete(s.roundTimers, roomCode)
+    slog.Debug("Stopped round timer", "room", roomCode)
+    return true
 }
 
-// Update startRoundTimer to be more robust
-func (s *GameService) startRoundTimer(roomCode string, duration int) {
-    s.timerMutex.Lock()
+// startRoundTimer ends the round at the deadline. Clients count down
+// locally from round_started, so by default only a single timer_warning is
+// broadcast; tickUpdates adds a timer_update every second.
+func (s *GameService) startRoundTimer(roomCode string, deadline time.Time, tickUpdates bool) {
     // Stop any existing timer first
-    if existingTimer, exists := s.roundTimers[roomCode]; exists {
-        existingTimer.Stop()
-        delete(s.roundTimers, roomCode)
-        log.Printf("Stopped existing timer for room %s", roomCode)
-    }
-    
-    // Create new timer
-    timer := time.NewTimer(time.Duration(duration) * time.Second)
-    s.roundTimers[roomCode] = timer
+    s.stopRoundTimer(roomCode)
+
+    s.timerMutex.Lock()
+    s.runRoundTimer(roomCode, deadline, tickUpdates)
     s.timerMutex.Unlock()
 
-    log.Printf("Started new timer for room %s with duration %d seconds", roomCode, duration)
+    slog.Debug("Started round timer", "room", roomCode, "deadline", deadline)
+}
+
+// runRoundTimer installs a timer for the room. Must be called with
+// timerMutex held.
+func (s *GameService) runRoundTimer(roomCode string, deadline time.Time, tickUpdates bool) *roundTimer {
+    rt := &roundTimer{
+        timer:       time.NewTimer(time.Until(deadline)),
+        deadline:    deadline,
+        tickUpdates: tickUpdates,
+        done:        make(chan struct{}),
+    }
+    s.roundTimers[roomCode] = rt
+
+    go s.watchRoundTimer(roomCode, rt)
+    return rt
+}
 
-    // Start time update goroutine
-    go func() {
-        remaining := duration
+// pauseRoundTimer freezes a room's timer and returns the time that was left
+func (s *GameService) pauseRoundTimer(roomCode string) (time.Duration, error) {
+    s.timerMutex.Lock()
+    defer s.timerMutex.Unlock()
+
+    rt, exists := s.roundTimers[roomCode]
+    if !exists {
+        return 0, ErrNoActiveRound
+    }
+    if rt.paused {
+        return 0, ErrGamePaused
+    }
+
+    rt.timer.Stop()
+    close(rt.done)
+    rt.paused = true
+    rt.remaining = time.Until(rt.deadline)
+    if rt.remaining < 0 {
+        rt.remaining = 0
+    }
+
+    slog.Debug("Paused round timer", "room", roomCode, "remaining", rt.remaining)
+    return rt.remaining, nil
+}
+
+// resumeRoundTimer restarts a paused timer with the time it had left and
+// returns the new deadline
+func (s *GameService) resumeRoundTimer(roomCode string) (time.Time, error) {
+    s.timerMutex.Lock()
+    defer s.timerMutex.Unlock()
+
+    paused, exists := s.roundTimers[roomCode]
+    if !exists {
+        return time.Time{}, ErrNoActiveRound
+    }
+    if !paused.paused {
+        return time.Time{}, ErrGameNotPaused
+    }
+
+    rt := s.runRoundTimer(roomCode, time.Now().Add(paused.remaining), paused.tickUpdates)
+
+    slog.Debug("Resumed round timer", "room", roomCode, "deadline", rt.deadline)
+    return rt.deadline, nil
+}
+
+// expireRoundTimer claims a timer that fired. It fails if the timer was
+// stopped or paused in the meantime.
+func (s *GameService) expireRoundTimer(roomCode string, rt *roundTimer) bool {
+    s.timerMutex.Lock()
+    defer s.timerMutex.Unlock()
+
+    if s.roundTimers[roomCode] != rt || rt.paused {
+        return false
+    }
+
+    close(rt.done)
+    delete(s.roundTimers, roomCode)
+    return true
+}
+
+func (s *GameService) watchRoundTimer(roomCode string, rt *roundTimer) {
+    var ticks <-chan time.Time
+    if rt.tickUpdates {
         ticker := time.NewTicker(1 * time.Second)
         defer ticker.Stop()
+        ticks = ticker.C
+    }
 
-        for {
-            select {
-            case <-timer.C:
-                s.stopRoundTimer(roomCode)  // Ensure timer is cleaned up
-                s.handleRoundEnd(roomCode)
-                return
-            case <-ticker.C:
-                s.timerMutex.RLock()
-                _, timerExists := s.roundTimers[roomCode]
-                s.timerMutex.RUnlock()
-                
-                if !timerExists {
-                    return // Exit if timer was stopped
-                }
-                
-                remaining--
-                if remaining >= 0 {
-                    s.hub.BroadcastToRoom(roomCode, websocket.GameEvent{
-                        Type: "timer_update",
-                        Data: map[string]interface{}{
-                            "remaining": remaining,
-                            "warning":   remaining <= 5,
-                        },
-                    })
-                }
+    var warning <-chan time.Time
+    if untilWarning := time.Until(rt.deadline) - timerWarningBefore; untilWarning > 0 {
+        warningTimer := time.NewTimer(untilWarning)
+        defer warningTimer.Stop()
+        warning = warningTimer.C
+    }
+
+    for {
+        select {
+        case <-rt.done:
+            return
+
+        case <-rt.timer.C:
+            // Claim the timer so a concurrent stop doesn't also end the round
+            if s.expireRoundTimer(roomCode, rt) {
+                ctx, span := startTimerSpan("round timer", roomCode)
+                s.handleRoundEnd(ctx, roomCode)
+                span.End()
             }
+            return
+
+        case <-warning:
+            s.hub.BroadcastToRoom(roomCode, websocket.GameEvent{
+                Type: protocol.EventTimerWarning,
+                Data: protocol.TimerWarningData{
+                    Remaining: secondsUntil(rt.deadline),
+                    Deadline:  rt.deadline.UnixMilli(),
+                },
+            })
+
+        case <-ticks:
+            remaining := secondsUntil(rt.deadline)
+            s.hub.BroadcastToRoom(roomCode, websocket.GameEvent{
+                Type: protocol.EventTimerUpdate,
+                Data: protocol.TimerUpdateData{
+                    Remaining: remaining,
+                    Warning:   remaining <= int(timerWarningBefore/time.Second),
+                },
+            })
         }
-    }()
+    }
+}
+
+// secondsUntil rounds the time left before t to whole seconds
+func secondsUntil(t time.Time) int {
+    remaining := time.Until(t).Round(time.Second)
+    if remaining < 0 {
+        return 0
+    }
+    return int(remaining / time.Second)
 }
 
 // handleRoundEnd processes the end of a round
-func (s *GameService) handleRoundEnd(roomCode string) {
-    room, err := s.roomRepo.GetByCode(roomCode)
+func (s *GameService) handleRoundEnd(ctx context.Context, roomCode string) {
+    room, err := s.roomRepo.WithContext(ctx).GetByCode(roomCode)
     if err != nil {
-        log.Printf("Error getting room %s: %v", roomCode, err)
+        slog.Error("Error getting room", "room", roomCode, "err", err)
         return
     }
 
-    round, err := s.roundRepo.GetCurrentRound(room.ID.String())
+    round, err := s.roundRepo.WithContext(ctx).GetCurrentRound(room.ID.String())
     if err != nil {
-        log.Printf("Error getting current round: %v", err)
+        slog.Error("Error getting current round", "room", roomCode, "err", err)
         return
     }
 
     // Get round results
-    answers, err := s.roundRepo.GetRoundAnswers(round.ID.String())
+    answers, err := s.roundRepo.WithContext(ctx).GetRoundAnswers(round.ID.String())
     if err != nil {
-        log.Printf("Error getting round answers: %v", err)
+        slog.Error("Error getting round answers", "room", roomCode, "round_id", round.ID, "err", err)
         return
     }
 
     // Update round state
-    if err := s.roundRepo.UpdateRoundState(round.ID.String(), "finished"); err != nil {
-        log.Printf("Error updating round state: %v", err)
+    if err := s.roundRepo.WithContext(ctx).UpdateRoundState(round.ID.String(), "finished"); err != nil {
+        slog.Error("Error updating round state", "room", roomCode, "round_id", round.ID, "err", err)
         return
     }
+    s.metrics.RoundEnded(time.Since(round.StartTime))
 
-    // Get question for results
-    question, _ := s.questionRepo.GetByID(round.QuestionID.String())
+    s.broadcastRoundResult(ctx, roomCode, round, answers)
 
-    // Broadcast round results
-    s.hub.BroadcastToRoom(roomCode, websocket.GameEvent{
-        Type: "round_result",
-        Data: map[string]interface{}{
-            "round_number":    round.RoundNumber,
-            "answers":         answers,
-            "question":        question,
-            "correct_answer":  question.Answer,
-        },
-    })
-
-    log.Printf("Round %d ended in room %s", round.RoundNumber, roomCode)
+    slog.Info("Round ended", "room", roomCode, "round", round.RoundNumber)
 
     // Check if game should end
     if round.RoundNumber >= room.MaxRounds {
-        s.endGame(roomCode)
+        s.endGame(ctx, roomCode)
         return
     }
 
-    // Start next round after delay
-    time.Sleep(5 * time.Second)
-    question, err = s.StartRound(roomCode)
+    // The next round starts after the intermission
+    s.startIntermission(room, round.RoundNumber+1)
+}
+
+// PauseGame freezes the current round's countdown
+func (s *GameService) PauseGame(ctx context.Context, roomCode string, playerID string) error {
+    ctx, span := startSpan(ctx, "GameService.PauseGame", roomCode, tracing.PlayerKey.String(playerID))
+    defer span.End()
+
+    room, round, err := s.activeRound(ctx, roomCode)
     if err != nil {
-        log.Printf("Error starting next round: %v", err)
-        return
+        return err
     }
 
-    s.hub.BroadcastToRoom(roomCode, websocket.GameEvent{
-        Type: "round_started",
-        Data: map[string]interface{}{
-            "question":     question,
-            "round_number": round.RoundNumber + 1,
-            "time_limit":   room.RoundTime,
+    remaining, err := s.pauseRoundTimer(room.Code)
+    if err != nil {
+        return err
+    }
+
+    // Remember when it was paused so the remaining time survives a reconnect
+    if err := s.roundRepo.WithContext(ctx).PauseRound(round.ID.String(), time.Now()); err != nil {
+        slog.Error("Error saving pause", "room", room.Code, "round_id", round.ID, "err", err)
+    }
+
+    s.hub.BroadcastToRoom(room.Code, websocket.GameEvent{
+        Type: protocol.EventGamePaused,
+        Data: protocol.GamePausedData{
+            RoundNumber: round.RoundNumber,
+            Remaining:   int(remaining.Round(time.Second) / time.Second),
+            PausedBy:    playerID,
+        },
+    })
+
+    slog.Info("Game paused", "room", room.Code, "player", playerID)
+    return nil
+}
+
+// ResumeGame restarts a paused round with the time it had left. A game
+// checkpointed between rounds at shutdown goes on with its next round.
+func (s *GameService) ResumeGame(ctx context.Context, roomCode string) error {
+    ctx, span := startSpan(ctx, "GameService.ResumeGame", roomCode)
+    defer span.End()
+
+    room, round, err := s.activeRound(ctx, roomCode)
+    if err == ErrNoActiveRound && !s.inIntermission(roomCode) {
+        return s.resumeBetweenRounds(ctx, roomCode)
+    }
+    if err != nil {
+        return err
+    }
+
+    // A round paused before a restart has no timer yet
+    if round.PausedAt != nil {
+        s.restorePausedTimer(room.Code, round.EndTime.Sub(*round.PausedAt), room.TimerUpdates)
+    }
+
+    deadline, err := s.resumeRoundTimer(room.Code)
+    if err != nil {
+        return err
+    }
+
+    if err := s.roundRepo.WithContext(ctx).ResumeRound(round.ID.String(), deadline); err != nil {
+        slog.Error("Error saving resume", "room", room.Code, "round_id", round.ID, "err", err)
+    }
+
+    s.hub.BroadcastToRoom(room.Code, websocket.GameEvent{
+        Type: protocol.EventGameResumed,
+        Data: protocol.GameResumedData{
+            RoundNumber: round.RoundNumber,
+            Remaining:   secondsUntil(deadline),
+            Deadline:    deadline.UnixMilli(),
+            ServerTime:  time.Now().UnixMilli(),
         },
     })
+
+    slog.Info("Game resumed", "room", room.Code)
+    return nil
+}
+
+// SkipQuestion throws out the current question. Its points are voided and
+// a new question is asked for the same round number.
+func (s *GameService) SkipQuestion(ctx context.Context, roomCode string) error {
+    ctx, span := startSpan(ctx, "GameService.SkipQuestion", roomCode)
+    defer span.End()
+
+    room, round, err := s.activeRound(ctx, roomCode)
+    if err != nil {
+        return err
+    }
+
+    // Claim the round so the timer doesn't end it as well
+    if !s.stopRoundTimer(room.Code) {
+        return ErrRoundNotActive
+    }
+
+    if err := s.roundRepo.WithContext(ctx).VoidRound(round.ID.String()); err != nil {
+        return err
+    }
+
+    skipped := protocol.QuestionSkippedData{RoundNumber: round.RoundNumber}
+    if question, err := s.questionRepo.WithContext(ctx).GetByID(round.QuestionID.String()); err == nil {
+        skipped.Question = toProtocolQuestion(question)
+        skipped.CorrectAnswer = question.Answer
+    }
+
+    s.hub.BroadcastToRoom(room.Code, websocket.GameEvent{
+        Type: protocol.EventQuestionSkipped,
+        Data: skipped,
+    })
+
+    slog.Info("Skipped question", "room", room.Code, "round", round.RoundNumber)
+
+    _, err = s.beginRound(ctx, room, round.RoundNumber)
+    return err
+}
+
+// activeRound returns a room that is playing and its current round
+func (s *GameService) activeRound(ctx context.Context, roomCode string) (*models.Room, *models.GameRound, error) {
+    room, err := s.roomRepo.WithContext(ctx).GetByCode(roomCode)
+    if err != nil {
+        return nil, nil, ErrRoomNotFound
+    }
+
+    if room.Status != "playing" {
+        return nil, nil, ErrGameNotInProgress
+    }
+
+    round, err := s.roundRepo.WithContext(ctx).GetCurrentRound(room.ID.String())
+    if err != nil {
+        return nil, nil, ErrNoActiveRound
+    }
+
+    return room, round, nil
+}
+
+// broadcastRoundResult sends the answers and correct answer for a finished round
+func (s *GameService) broadcastRoundResult(ctx context.Context, roomCode string, round *models.GameRound, answers []models.PlayerAnswer) {
+    result := protocol.RoundResultData{
+        RoundNumber: round.RoundNumber,
+        Answers:     toProtocolAnswers(answers),
+    }
+
+    question, err := s.questionRepo.WithContext(ctx).GetByID(round.QuestionID.String())
+    if err != nil {
+        slog.Error("Error getting question for round result", "room", roomCode, "round_id", round.ID, "err", err)
+    } else {
+        result.Question = toProtocolQuestion(question)
+        result.CorrectAnswer = question.Answer
+    }
+
+    s.hub.BroadcastToRoom(roomCode, websocket.GameEvent{
+        Type: protocol.EventRoundResult,
+        Data: result,
+    })
 }
 
 // calculateScore determines points based on answer order
@@ -420,29 +700,37 @@ func (s *GameService) calculateScore(answerOrder int) int {
 }
 
 // endGame handles game completion
-func (s *GameService) endGame(roomCode string) {
-    room, err := s.roomRepo.GetByCode(roomCode)
+func (s *GameService) endGame(ctx context.Context, roomCode string) {
+    room, err := s.roomRepo.WithContext(ctx).GetByCode(roomCode)
     if err != nil {
-        log.Printf("Error getting room for game end: %v", err)
+        slog.Error("Error getting room for game end", "room", roomCode, "err", err)
         return
     }
 
     // Get all rounds for this room
-    rounds, err := s.roundRepo.GetRoomRounds(room.ID.String())
+    allRounds, err := s.roundRepo.WithContext(ctx).GetRoomRounds(room.ID.String())
     if err != nil {
-        log.Printf("Error getting room rounds: %v", err)
+        slog.Error("Error getting room rounds", "room", roomCode, "err", err)
         return
     }
 
+    // Skipped questions don't count
+    rounds := make([]models.GameRound, 0, len(allRounds))
+    for _, round := range allRounds {
+        if round.State != "voided" {
+            rounds = append(rounds, round)
+        }
+    }
+
     // Get all players in the room
     players := s.hub.GetPlayersInRoom(roomCode)
     playerResults := make(map[string]*PlayerResult)
 
     // Initialize results for all players
     for _, player := range players {
-        playerResults[player["id"]] = &PlayerResult{
-            PlayerID:   player["id"],
-            Username:   player["username"],
+        playerResults[player.ID] = &PlayerResult{
+            PlayerID:   player.ID,
+            Username:   player.Username,
             TotalScore: 0,
             Rounds:     make([]RoundResult, len(rounds)),
         }
@@ -451,9 +739,9 @@ func (s *GameService) endGame(roomCode string) {
     // Process each round
     for i, round := range rounds {
         // Get answers for this round
-        answers, err := s.roundRepo.GetRoundAnswers(round.ID.String())
+        answers, err := s.roundRepo.WithContext(ctx).GetRoundAnswers(round.ID.String())
         if err != nil {
-            log.Printf("Error getting round answers: %v", err)
+            slog.Error("Error getting round answers", "room", roomCode, "round_id", round.ID, "err", err)
             continue
         }
 
@@ -506,118 +794,177 @@ func (s *GameService) endGame(roomCode string) {
     }
 
     // Update room status
-    if err := s.roomRepo.UpdateStatus(room.ID.String(), "finished"); err != nil {
-        log.Printf("Error updating room status: %v", err)
+    if err := s.roomRepo.WithContext(ctx).UpdateStatus(room.ID.String(), "finished"); err != nil {
+        slog.Error("Error updating room status", "room", roomCode, "err", err)
     }
 
     // Cancel any existing timer
-    s.timerMutex.Lock()
-    if timer, exists := s.roundTimers[roomCode]; exists {
-        timer.Stop()
-        delete(s.roundTimers, roomCode)
+    s.stopRoundTimer(roomCode)
+
+    // Keep a record of the game and update the leaderboards; the rounds are
+    // deleted on restart and the room when it goes idle
+    if err := s.archiveGame(ctx, room, allRounds, finalResults); err != nil {
+        slog.Error("Error recording game", "room", roomCode, "err", err)
+    } else {
+        go s.refreshDifficulties(ctx, allRounds)
     }
-    s.timerMutex.Unlock()
 
     // Broadcast final results
     s.hub.BroadcastToRoom(roomCode, websocket.GameEvent{
-        Type: "game_end",
-        Data: map[string]interface{}{
-            "final_results": finalResults,
-            "total_rounds": len(rounds),
-            "room_code":    roomCode,
+        Type: protocol.EventGameEnd,
+        Data: protocol.GameEndData{
+            FinalResults: finalResults,
+            TotalRounds:  len(rounds),
+            RoomCode:     roomCode,
         },
     })
 
-    log.Printf("Game ended in room %s with %d players", roomCode, len(players))
+    slog.Info("Game ended", "room", roomCode, "players", len(players))
 }
 
-// ShouldEndRound determines if the round should end
-func (s *GameService) ShouldEndRound(roomID string) bool {
-    round, err := s.roundRepo.GetCurrentRound(roomID)
-    if err != nil {
-        return false
+// refreshDifficulties folds an archived game's answers into the derived
+// difficulty of its questions
+func (s *GameService) refreshDifficulties(ctx context.Context, rounds []models.GameRound) {
+    done := make(map[uuid.UUID]bool)
+    for _, round := range rounds {
+        if round.State == "voided" || done[round.QuestionID] {
+            continue
+        }
+        done[round.QuestionID] = true
+
+        err := refreshDerivedDifficulty(s.historyRepo.WithContext(ctx), s.questionRepo.WithContext(ctx), round.QuestionID.String())
+        if err != nil {
+            slog.Error("Error updating question difficulty", "question_id", round.QuestionID, "err", err)
+        }
     }
+}
 
-    playerCount := s.hub.GetPlayerCount(roomID)
-    timeExpired := time.Now().After(round.EndTime)
-    allAnswered := round.AnswerCount >= playerCount
+// archiveGame saves a finished game to the history tables
+func (s *GameService) archiveGame(ctx context.Context, room *models.Room, rounds []models.GameRound, results []*PlayerResult) error {
+    session := &models.GameSession{
+        RoomID:      room.ID,
+        RoomCode:    room.Code,
+        StartedAt:   time.Now(),
+        EndedAt:     time.Now(),
+        PlayerCount: len(results),
+    }
 
-    return timeExpired || allAnswered
-}
+    registered := make(map[string]bool)
+    for _, player := range s.hub.GetPlayersInRoom(room.Code) {
+        registered[player.ID] = player.Registered
+    }
 
-// EndRound finishes the current round
-func (s *GameService) EndRound(roomID string) error {
-    round, err := s.roundRepo.GetCurrentRound(roomID)
-    if err != nil {
-        return err
+    for _, result := range results {
+        player := models.SessionPlayer{
+            PlayerID:   result.PlayerID,
+            Username:   result.Username,
+            TotalScore: result.TotalScore,
+            Rank:       result.Rank,
+        }
+        if registered[result.PlayerID] {
+            if accountID, err := uuid.Parse(result.PlayerID); err == nil {
+                player.AccountID = &accountID
+            }
+        }
+        session.Players = append(session.Players, player)
     }
 
-    if err := s.roundRepo.UpdateRoundState(round.ID.String(), "finished"); err != nil {
-        return err
+    for i, round := range rounds {
+        if i == 0 {
+            session.StartedAt = round.StartTime
+        }
+
+        archived := models.SessionRound{
+            RoundNumber: round.RoundNumber,
+            QuestionID:  round.QuestionID,
+            Voided:      round.State == "voided",
+            StartTime:   round.StartTime,
+            EndTime:     round.EndTime,
+        }
+        if !archived.Voided {
+            session.TotalRounds++
+        }
+
+        if question, err := s.questionRepo.WithContext(ctx).GetByID(round.QuestionID.String()); err == nil {
+            archived.Question = question.Content
+            archived.Answer = question.Answer
+            archived.Category = question.Category
+        }
+
+        answers, err := s.roundRepo.WithContext(ctx).GetRoundAnswers(round.ID.String())
+        if err != nil {
+            return err
+        }
+        for _, answer := range answers {
+            archived.Answers = append(archived.Answers, models.SessionAnswer{
+                PlayerID:    answer.PlayerID,
+                Answer:      answer.Answer,
+                Score:       answer.Score,
+                AnswerOrder: answer.AnswerOrder,
+                AnsweredAt:  answer.AnsweredAt,
+            })
+        }
+
+        session.Rounds = append(session.Rounds, archived)
     }
 
-    // Get and broadcast results
-    answers, err := s.roundRepo.GetRoundAnswers(round.ID.String())
-    if err != nil {
+    if err := s.historyRepo.WithContext(ctx).SaveSession(session); err != nil {
         return err
     }
 
-    s.hub.BroadcastToRoom(roomID, websocket.GameEvent{
-        Type: "round_result",
-        Data: answers,
-    })
-
-    log.Printf("Ended round in room %s", roomID)
-    return nil
+    // Leaderboards are kept as running totals, added to as each game ends
+    return s.leaderboardRepo.WithContext(ctx).AddResults(leaderboardEntries(session))
 }
 
 // RestartGame resets the game with the same players
-func (s *GameService) RestartGame(roomCode string, settings *models.GameSettings) error {
-    room, err := s.roomRepo.GetByCode(roomCode)
+func (s *GameService) RestartGame(ctx context.Context, roomCode string, settings *models.GameSettings) error {
+    ctx, span := startSpan(ctx, "GameService.RestartGame", roomCode)
+    defer span.End()
+
+    room, err := s.roomRepo.WithContext(ctx).GetByCode(roomCode)
     if err != nil {
-        return errors.New("room not found")
+        return ErrRoomNotFound
     }
 
+    // Stop whatever is still running from the previous game
+    s.stopRoundTimer(room.Code)
+    s.cancelIntermission(room.Code)
+
     // Clear previous game data to prevent score aggregation
-    if err := s.clearPreviousGameData(room.ID.String()); err != nil {
-        log.Printf("Warning: couldn't clear previous game data: %v", err)
+    if err := s.clearPreviousGameData(ctx, room.ID.String()); err != nil {
+        slog.Warn("Couldn't clear previous game data", "room", roomCode, "err", err)
         // Continue anyway - this is not a fatal error
     }
 
     // Update room settings if provided
     if settings != nil {
-        room.MaxRounds = settings.MaxRounds
-        room.RoundTime = settings.RoundTime
+        settings.Apply(room)
     }
 
     // Reset room state
     room.CurrentRound = 0
     room.Status = "waiting"
 
-    if err := s.roomRepo.UpdateRoom(room); err != nil {
+    if err := s.roomRepo.WithContext(ctx).UpdateRoom(room); err != nil {
         return err
     }
 
     // Broadcast restart event
     s.hub.BroadcastToRoom(roomCode, websocket.GameEvent{
-        Type: "game_restart",
-        Data: map[string]interface{}{
-            "settings": map[string]interface{}{
-                "max_rounds":  room.MaxRounds,
-                "round_time": room.RoundTime,
-            },
+        Type: protocol.EventGameRestart,
+        Data: protocol.GameRestartData{
+            Settings: SettingsOf(room),
         },
     })
 
-    log.Printf("Game restarted in room %s with %d rounds, %d seconds per round", 
-        roomCode, room.MaxRounds, room.RoundTime)
+    slog.Info("Game restarted", "room", roomCode, "rounds", room.MaxRounds, "round_time", room.RoundTime)
     return nil
 }
 
 // clearPreviousGameData removes all rounds and player answers from previous games in this room
-func (s *GameService) clearPreviousGameData(roomID string) error {
+func (s *GameService) clearPreviousGameData(ctx context.Context, roomID string) error {
     // Get all rounds for this room
-    rounds, err := s.roundRepo.GetRoomRounds(roomID)
+    rounds, err := s.roundRepo.WithContext(ctx).GetRoomRounds(roomID)
     if err != nil {
         return err
     }
@@ -625,48 +972,35 @@ func (s *GameService) clearPreviousGameData(roomID string) error {
     // Delete player answers and rounds
     for _, round := range rounds {
         // Delete all answers for this round
-        if err := s.roundRepo.DeleteRoundAnswers(round.ID.String()); err != nil {
-            log.Printf("Error deleting answers for round %s: %v", round.ID.String(), err)
+        if err := s.roundRepo.WithContext(ctx).DeleteRoundAnswers(round.ID.String()); err != nil {
+            slog.Error("Error deleting round answers", "room_id", roomID, "round_id", round.ID, "err", err)
             continue
         }
         
         // Delete the round itself
-        if err := s.roundRepo.DeleteRound(round.ID.String()); err != nil {
-            log.Printf("Error deleting round %s: %v", round.ID.String(), err)
+        if err := s.roundRepo.WithContext(ctx).DeleteRound(round.ID.String()); err != nil {
+            slog.Error("Error deleting round", "room_id", roomID, "round_id", round.ID, "err", err)
         }
     }
     
-    log.Printf("Cleared previous game data for room %s", roomID)
+    slog.Debug("Cleared previous game data", "room_id", roomID)
     return nil
 }
 
 // GetGameState returns the current game state
-func (s *GameService) GetGameState(roomCode string, playerID string) (map[string]interface{}, error) {
-    room, err := s.roomRepo.GetByCode(roomCode)
-    if err != nil {
-        return nil, errors.New("room not found")
-    }
+func (s *GameService) GetGameState(ctx context.Context, roomCode string, playerID string) (*protocol.GameStateData, error) {
+    ctx, span := startSpan(ctx, "GameService.GetGameState", roomCode, tracing.PlayerKey.String(playerID))
+    defer span.End()
 
-    // Get current round if game is in progress
-    var currentRound *models.GameRound
-    var currentQuestion *models.Question
-    var roundTimeRemaining int
-    if room.Status == "playing" {
-        currentRound, err = s.roundRepo.GetCurrentRound(room.ID.String())
-        if err == nil && currentRound != nil {
-            currentQuestion, _ = s.questionRepo.GetByID(currentRound.QuestionID.String())
-            
-            // Calculate remaining time
-            if time.Now().Before(currentRound.EndTime) {
-                roundTimeRemaining = int(time.Until(currentRound.EndTime).Seconds())
-            }
-        }
+    room, err := s.roomRepo.WithContext(ctx).GetByCode(roomCode)
+    if err != nil {
+        return nil, ErrRoomNotFound
     }
 
     // Get player's answers and scores
-    playerAnswers, err := s.roundRepo.GetPlayerAnswers(room.ID.String(), playerID)
+    playerAnswers, err := s.roundRepo.WithContext(ctx).GetPlayerAnswers(room.ID.String(), playerID)
     if err != nil {
-        log.Printf("Error getting player answers: %v", err)
+        slog.Error("Error getting player answers", "room", roomCode, "player", playerID, "err", err)
     }
     
     // Calculate player's total score
@@ -675,43 +1009,67 @@ func (s *GameService) GetGameState(roomCode string, playerID string) (map[string
         totalScore += answer.Score
     }
 
-    // Get all players in room
-    players := s.hub.GetPlayersInRoom(roomCode)
-    
     // Get all rounds for this room to provide complete game context
-    rounds, err := s.roundRepo.GetRoomRounds(room.ID.String())
-    
-    gameState := map[string]interface{}{
-        "room_code":     roomCode,
-        "game_status":   room.Status,
-        "current_round": room.CurrentRound,
-        "max_rounds":    room.MaxRounds,
-        "round_time":    room.RoundTime,
-        "players":       players,
-        "your_answers":  playerAnswers,
-        "your_score":    totalScore,
-        "rounds":        rounds,
+    rounds, err := s.roundRepo.WithContext(ctx).GetRoomRounds(room.ID.String())
+    if err != nil {
+        slog.Error("Error getting room rounds", "room", roomCode, "err", err)
+    }
+
+    gameState := &protocol.GameStateData{
+        RoomCode:     roomCode,
+        GameStatus:   room.Status,
+        CurrentRound: room.CurrentRound,
+        MaxRounds:    room.MaxRounds,
+        RoundTime:    room.RoundTime,
+        Players:      s.hub.GetPlayersInRoom(roomCode),
+        YourAnswers:  toProtocolAnswers(playerAnswers),
+        YourScore:    totalScore,
+        Rounds:       toProtocolRounds(rounds),
+        ServerTime:   time.Now().UnixMilli(),
     }
 
+    gameState.Intermission = s.currentIntermission(room.Code)
+
     // Include current question if game is in progress
-    if currentQuestion != nil {
-        gameState["current_question"] = currentQuestion
-        gameState["round_end_time"] = currentRound.EndTime
-        gameState["time_remaining"] = roundTimeRemaining
+    if room.Status == "playing" {
+        currentRound, err := s.roundRepo.WithContext(ctx).GetCurrentRound(room.ID.String())
+        if err == nil && currentRound != nil {
+            if question, err := s.questionRepo.WithContext(ctx).GetByID(currentRound.QuestionID.String()); err == nil {
+                current := toProtocolQuestion(question)
+                endTime := currentRound.EndTime
+                gameState.CurrentQuestion = &current
+                gameState.RoundEndTime = &endTime
+
+                // Calculate remaining time. A paused round keeps what it
+                // had left when it was paused and has no deadline yet.
+                if currentRound.PausedAt != nil {
+                    gameState.Paused = true
+                    gameState.TimeRemaining = int(currentRound.EndTime.Sub(*currentRound.PausedAt).Seconds())
+                } else {
+                    gameState.Deadline = currentRound.EndTime.UnixMilli()
+                    if time.Now().Before(currentRound.EndTime) {
+                        gameState.TimeRemaining = int(time.Until(currentRound.EndTime).Seconds())
+                    }
+                }
+            }
+        }
     }
 
     return gameState, nil
 }
 
-func (s *GameService) GetPlayerAnswers(roomCode string, playerID string) ([]models.PlayerAnswer, error) {
-    room, err := s.roomRepo.GetByCode(roomCode)
+func (s *GameService) GetPlayerAnswers(ctx context.Context, roomCode string, playerID string) ([]models.PlayerAnswer, error) {
+    ctx, span := startSpan(ctx, "GameService.GetPlayerAnswers", roomCode, tracing.PlayerKey.String(playerID))
+    defer span.End()
+
+    room, err := s.roomRepo.WithContext(ctx).GetByCode(roomCode)
     if err != nil {
-        return nil, errors.New("room not found")
+        return nil, ErrRoomNotFound
     }
     
-    answers, err := s.roundRepo.GetPlayerAnswers(room.ID.String(), playerID)
+    answers, err := s.roundRepo.WithContext(ctx).GetPlayerAnswers(room.ID.String(), playerID)
     if err != nil {
-        log.Printf("Error retrieving player answers: %v", err)
+        slog.Error("Error getting player answers", "room", roomCode, "player", playerID, "err", err)
         return nil, err
     }
     
@@ -725,7 +1083,7 @@ func (s *GameService) ValidatePlayerInRoom(roomCode string, playerID string) boo
     // Check if there's a client with this ID currently or previously in the room
     players := s.hub.GetPlayersInRoom(roomCode)
     for _, player := range players {
-        if player["id"] == playerID {
+        if player.ID == playerID {
             return true
         }
     }
@@ -735,4 +1093,60 @@ func (s *GameService) ValidatePlayerInRoom(roomCode string, playerID string) boo
     // or a dedicated "known players" table in the database
     
     return false
-}
\ No newline at end of file
+}
+
+func toProtocolQuestion(question *models.Question) protocol.Question {
+    return protocol.Question{
+        ID:        question.ID.String(),
+        Content:   question.Content,
+        Category:  question.Category,
+        MediaURL:  question.MediaURL,
+        MediaType: question.MediaType,
+    }
+}
+
+func toProtocolAnswers(answers []models.PlayerAnswer) []protocol.Answer {
+    result := make([]protocol.Answer, len(answers))
+    for i, answer := range answers {
+        result[i] = protocol.Answer{
+            PlayerID:    answer.PlayerID,
+            Answer:      answer.Answer,
+            Score:       answer.Score,
+            AnswerOrder: answer.AnswerOrder,
+            AnsweredAt:  answer.AnsweredAt,
+        }
+    }
+    return result
+}
+
+func toProtocolRounds(rounds []models.GameRound) []protocol.Round {
+    result := make([]protocol.Round, len(rounds))
+    for i, round := range rounds {
+        result[i] = protocol.Round{
+            ID:          round.ID.String(),
+            RoundNumber: round.RoundNumber,
+            State:       round.State,
+            StartTime:   round.StartTime,
+            EndTime:     round.EndTime,
+            AnswerCount: round.AnswerCount,
+        }
+    }
+    return result
+}
+
+// SettingsOf returns the settings of a room as sent to clients
+func SettingsOf(room *models.Room) protocol.RoomSettings {
+    settings := protocol.RoomSettings{
+        MaxPlayers:       room.MaxPlayers,
+        RoundTime:        room.RoundTime,
+        MaxRounds:        room.MaxRounds,
+        TimerUpdates:     room.TimerUpdates,
+        IntermissionTime: room.IntermissionTime,
+        ReadyCheck:       room.ReadyCheck,
+        DifficultyRamp:   room.DifficultyRamp,
+    }
+    if room.PackID != nil {
+        settings.PackID = room.PackID.String()
+    }
+    return settings
+}
@@ -0,0 +1,141 @@
+// internal/service/history_service.go
+
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/repository"
+)
+
+// HistoryService reads archived games
+type HistoryService struct {
+    historyRepo repository.HistoryStore
+}
+
+func NewHistoryService(historyRepo repository.HistoryStore) *HistoryService {
+    return &HistoryService{
+        historyRepo: historyRepo,
+    }
+}
+
+// ListRoomGames returns a page of the games played in a room, newest first,
+// and the total number of games
+func (s *HistoryService) ListRoomGames(roomCode string, limit int, offset int) ([]models.GameSession, int64, error) {
+    return s.historyRepo.ListByRoomCode(roomCode, limit, offset)
+}
+
+// GetGame returns one archived game with its rounds and answers
+func (s *HistoryService) GetGame(id string) (*models.GameSession, error) {
+    if _, err := uuid.Parse(id); err != nil {
+        return nil, ErrGameNotFound
+    }
+
+    session, err := s.historyRepo.GetSession(id)
+    if err != nil {
+        return nil, ErrGameNotFound
+    }
+    return session, nil
+}
+
+// PlayerStats summarises a player's archived games
+type PlayerStats struct {
+    PlayerID          string  `json:"player_id"`
+    Username          string  `json:"username"`
+    GamesPlayed       int     `json:"games_played"`
+    Wins              int     `json:"wins"`
+    TotalScore        int     `json:"total_score"`
+    RoundsPlayed      int     `json:"rounds_played"`
+    CorrectAnswers    int     `json:"correct_answers"`
+    Accuracy          float64 `json:"accuracy"`               // Share of rounds answered correctly, 0-1
+    AverageAnswerTime float64 `json:"average_answer_seconds"` // From question to correct answer
+    BestCategory      string  `json:"best_category,omitempty"`
+    LongestStreak     int     `json:"longest_streak"`         // Rounds in a row answered correctly
+    FirstAnswers      int     `json:"first_answers"`          // Rounds where they were first to answer correctly
+}
+
+// GetPlayerStats computes a player's stats from their archived games. The
+// player ID is the account ID for signed-in players.
+func (s *HistoryService) GetPlayerStats(playerID string) (*PlayerStats, error) {
+    games, err := s.historyRepo.GetPlayerGames(playerID)
+    if err != nil {
+        return nil, err
+    }
+
+    rounds, err := s.historyRepo.GetPlayerRounds(playerID)
+    if err != nil {
+        return nil, err
+    }
+
+    return computePlayerStats(playerID, games, rounds), nil
+}
+
+// computePlayerStats works out the stats from a player's standings and the
+// rounds they played, in order
+func computePlayerStats(playerID string, games []models.SessionPlayer, rounds []models.SessionRound) *PlayerStats {
+    stats := &PlayerStats{PlayerID: playerID}
+
+    for _, game := range games {
+        stats.GamesPlayed++
+        stats.TotalScore += game.TotalScore
+        if game.Rank == 1 {
+            stats.Wins++
+        }
+        stats.Username = game.Username // Most recent name
+    }
+
+    categoryScores := make(map[string]int)
+    var answerTime time.Duration
+    streak := 0
+
+    for _, round := range rounds {
+        stats.RoundsPlayed++
+
+        var correct *models.SessionAnswer
+        for i := range round.Answers {
+            if round.Answers[i].AnswerOrder > 0 {
+                correct = &round.Answers[i]
+                break
+            }
+        }
+
+        if correct == nil {
+            streak = 0
+            continue
+        }
+
+        stats.CorrectAnswers++
+        answerTime += correct.AnsweredAt.Sub(round.StartTime)
+        if correct.AnswerOrder == 1 {
+            stats.FirstAnswers++
+        }
+        if round.Category != "" {
+            categoryScores[strings.ToLower(round.Category)] += correct.Score
+        }
+
+        streak++
+        if streak > stats.LongestStreak {
+            stats.LongestStreak = streak
+        }
+    }
+
+    if stats.RoundsPlayed > 0 {
+        stats.Accuracy = float64(stats.CorrectAnswers) / float64(stats.RoundsPlayed)
+    }
+    if stats.CorrectAnswers > 0 {
+        stats.AverageAnswerTime = (answerTime / time.Duration(stats.CorrectAnswers)).Seconds()
+    }
+
+    bestScore := 0
+    for category, score := range categoryScores {
+        if score > bestScore || (score == bestScore && score > 0 && category < stats.BestCategory) {
+            stats.BestCategory = category
+            bestScore = score
+        }
+    }
+
+    return stats
+}
@@ -0,0 +1,62 @@
+// internal/service/history_service_test.go
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rohan03122001/quizzing/internal/models"
+)
+
+func TestComputePlayerStats(t *testing.T) {
+    start := time.Date(2024, 1, 16, 10, 0, 0, 0, time.UTC)
+    round := func(category string, answers ...models.SessionAnswer) models.SessionRound {
+        r := models.SessionRound{Category: category, StartTime: start, Answers: answers}
+        start = start.Add(time.Minute)
+        return r
+    }
+    correct := func(order int, score int, after time.Duration) models.SessionAnswer {
+        return models.SessionAnswer{PlayerID: "p1", AnswerOrder: order, Score: score, AnsweredAt: start.Add(after)}
+    }
+    wrong := models.SessionAnswer{PlayerID: "p1"}
+
+    games := []models.SessionPlayer{
+        {PlayerID: "p1", Username: "old name", TotalScore: 1750, Rank: 1},
+        {PlayerID: "p1", Username: "new name", TotalScore: 1000, Rank: 2},
+    }
+    rounds := []models.SessionRound{
+        round("science", correct(1, 1000, 2*time.Second)),
+        round("science", wrong, correct(2, 750, 4*time.Second)),
+        round("history"),
+        round("History", correct(1, 1000, 6*time.Second)),
+        round("", wrong),
+    }
+
+    stats := computePlayerStats("p1", games, rounds)
+
+    want := PlayerStats{
+        PlayerID:          "p1",
+        Username:          "new name",
+        GamesPlayed:       2,
+        Wins:              1,
+        TotalScore:        2750,
+        RoundsPlayed:      5,
+        CorrectAnswers:    3,
+        Accuracy:          0.6,
+        AverageAnswerTime: 4,
+        BestCategory:      "science",
+        LongestStreak:     2,
+        FirstAnswers:      2,
+    }
+    if *stats != want {
+        t.Errorf("got  %+v\nwant %+v", *stats, want)
+    }
+}
+
+func TestComputePlayerStatsWithoutGames(t *testing.T) {
+    stats := computePlayerStats("nobody", nil, nil)
+    if stats.GamesPlayed != 0 || stats.Accuracy != 0 || stats.AverageAnswerTime != 0 || stats.BestCategory != "" {
+        t.Errorf("got %+v, want zero stats", *stats)
+    }
+}
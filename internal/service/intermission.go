@@ -0,0 +1,187 @@
+// internal/service/intermission.go
+
+package service
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+	"github.com/rohan03122001/quizzing/internal/websocket"
+)
+
+// intermission is the pause between two rounds of a room
+type intermission struct {
+    nextRound  int
+    deadline   time.Time
+    readyCheck bool
+    ready      map[string]bool // player ID -> sent ready
+    readied    chan struct{}   // signalled when a player gets ready
+    cancel     chan struct{}   // closed when the intermission is cancelled
+}
+
+// startIntermission counts down to the next round. With the room's ready
+// check on, the round starts early once every connected player is ready.
+func (s *GameService) startIntermission(room *models.Room, nextRound int) {
+    duration := time.Duration(room.IntermissionTime) * time.Second
+    im := &intermission{
+        nextRound:  nextRound,
+        deadline:   time.Now().Add(duration),
+        readyCheck: room.ReadyCheck,
+        ready:      make(map[string]bool),
+        readied:    make(chan struct{}, 1),
+        cancel:     make(chan struct{}),
+    }
+
+    s.intermissionMutex.Lock()
+    if previous, exists := s.intermissions[room.Code]; exists {
+        close(previous.cancel)
+    }
+    s.intermissions[room.Code] = im
+    s.intermissionMutex.Unlock()
+
+    s.hub.BroadcastToRoom(room.Code, websocket.GameEvent{
+        Type: protocol.EventIntermission,
+        Data: intermissionData(im),
+    })
+
+    slog.Info("Intermission started", "room", room.Code, "next_round", nextRound,
+        "duration", duration, "ready_check", room.ReadyCheck)
+
+    go s.runIntermission(room.Code, im)
+}
+
+func (s *GameService) runIntermission(roomCode string, im *intermission) {
+    timer := time.NewTimer(time.Until(im.deadline))
+    defer timer.Stop()
+
+    ticker := time.NewTicker(1 * time.Second)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-im.cancel:
+            return
+
+        case <-timer.C:
+            s.finishIntermission(roomCode, im)
+            return
+
+        case <-ticker.C:
+            if s.broadcastCountdown(roomCode, im) {
+                s.finishIntermission(roomCode, im)
+                return
+            }
+
+        case <-im.readied:
+            if s.broadcastCountdown(roomCode, im) {
+                s.finishIntermission(roomCode, im)
+                return
+            }
+        }
+    }
+}
+
+// broadcastCountdown sends the intermission_countdown event and reports
+// whether the ready check has passed
+func (s *GameService) broadcastCountdown(roomCode string, im *intermission) bool {
+    players := s.hub.GetPlayersInRoom(roomCode)
+
+    s.intermissionMutex.Lock()
+    readyCount := 0
+    for _, player := range players {
+        if im.ready[player.ID] {
+            readyCount++
+        }
+    }
+    s.intermissionMutex.Unlock()
+
+    s.hub.BroadcastToRoom(roomCode, websocket.GameEvent{
+        Type: protocol.EventIntermissionTick,
+        Data: protocol.IntermissionCountdownData{
+            NextRound:    im.nextRound,
+            Remaining:    secondsUntil(im.deadline),
+            ReadyCount:   readyCount,
+            TotalPlayers: len(players),
+        },
+    })
+
+    return im.readyCheck && len(players) > 0 && readyCount == len(players)
+}
+
+// finishIntermission starts the next round, unless the intermission was
+// cancelled in the meantime
+func (s *GameService) finishIntermission(roomCode string, im *intermission) {
+    s.intermissionMutex.Lock()
+    if s.intermissions[roomCode] != im {
+        s.intermissionMutex.Unlock()
+        return
+    }
+    delete(s.intermissions, roomCode)
+    s.intermissionMutex.Unlock()
+
+    ctx, span := startTimerSpan("intermission end", roomCode)
+    defer span.End()
+    if _, err := s.StartRound(ctx, roomCode); err != nil {
+        slog.Error("Error starting next round", "room", roomCode, "err", err)
+    }
+}
+
+// cancelIntermission stops a room's intermission without starting the next round
+func (s *GameService) cancelIntermission(roomCode string) {
+    s.intermissionMutex.Lock()
+    defer s.intermissionMutex.Unlock()
+
+    if im, exists := s.intermissions[roomCode]; exists {
+        close(im.cancel)
+        delete(s.intermissions, roomCode)
+        slog.Debug("Cancelled intermission", "room", roomCode)
+    }
+}
+
+// MarkReady records that a player is ready for the next round
+func (s *GameService) MarkReady(roomCode string, playerID string) error {
+    s.intermissionMutex.Lock()
+    im, exists := s.intermissions[roomCode]
+    if exists {
+        im.ready[playerID] = true
+    }
+    s.intermissionMutex.Unlock()
+
+    if !exists {
+        return ErrNotInIntermission
+    }
+
+    // Don't block if a signal is already pending
+    select {
+    case im.readied <- struct{}{}:
+    default:
+    }
+
+    slog.Debug("Player is ready", "room", roomCode, "player", playerID)
+    return nil
+}
+
+// currentIntermission returns the room's intermission as sent to clients, or nil
+func (s *GameService) currentIntermission(roomCode string) *protocol.IntermissionData {
+    s.intermissionMutex.Lock()
+    defer s.intermissionMutex.Unlock()
+
+    im, exists := s.intermissions[roomCode]
+    if !exists {
+        return nil
+    }
+    data := intermissionData(im)
+    return &data
+}
+
+func intermissionData(im *intermission) protocol.IntermissionData {
+    return protocol.IntermissionData{
+        NextRound:  im.nextRound,
+        Duration:   secondsUntil(im.deadline),
+        Deadline:   im.deadline.UnixMilli(),
+        ServerTime: time.Now().UnixMilli(),
+        ReadyCheck: im.readyCheck,
+    }
+}
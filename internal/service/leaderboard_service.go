@@ -0,0 +1,190 @@
+// internal/service/leaderboard_service.go
+
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+	"github.com/rohan03122001/quizzing/internal/repository"
+)
+
+// BoardAllTime is the key of the all-time leaderboard
+const BoardAllTime = "all_time"
+
+// WeeklyBoard returns the key of the leaderboard for the ISO week of t
+func WeeklyBoard(t time.Time) string {
+    year, week := t.UTC().ISOWeek()
+    return fmt.Sprintf("weekly:%d-W%02d", year, week)
+}
+
+// CategoryBoard returns the key of a category's leaderboard
+func CategoryBoard(category string) string {
+    return "category:" + strings.ToLower(category)
+}
+
+// LeaderboardService reads the leaderboards. They are only written by
+// GameService as games end; only signed-in players are ranked.
+type LeaderboardService struct {
+    leaderboardRepo repository.LeaderboardStore
+}
+
+func NewLeaderboardService(leaderboardRepo repository.LeaderboardStore) *LeaderboardService {
+    return &LeaderboardService{
+        leaderboardRepo: leaderboardRepo,
+    }
+}
+
+// RankedEntry is a leaderboard entry with its position
+type RankedEntry struct {
+    Rank int `json:"rank"`
+    models.LeaderboardEntry
+}
+
+// AllTime returns a page of the all-time leaderboard
+func (s *LeaderboardService) AllTime(limit int, offset int) ([]RankedEntry, int64, error) {
+    return s.list(BoardAllTime, limit, offset)
+}
+
+// Weekly returns a page of a week's leaderboard. week is an ISO week like
+// "2024-W03"; empty means the current week.
+func (s *LeaderboardService) Weekly(week string, limit int, offset int) ([]RankedEntry, int64, error) {
+    board := WeeklyBoard(time.Now())
+    if week != "" {
+        var year, number int
+        if _, err := fmt.Sscanf(week, "%d-W%d", &year, &number); err != nil || number < 1 || number > 53 {
+            return nil, 0, NewError(protocol.CodeInvalidMessage, "week must look like 2024-W03")
+        }
+        board = fmt.Sprintf("weekly:%d-W%02d", year, number)
+    }
+    return s.list(board, limit, offset)
+}
+
+// Category returns a page of a category's leaderboard
+func (s *LeaderboardService) Category(category string, limit int, offset int) ([]RankedEntry, int64, error) {
+    return s.list(CategoryBoard(category), limit, offset)
+}
+
+// Categories lists the categories that have a leaderboard
+func (s *LeaderboardService) Categories() ([]string, error) {
+    boards, err := s.leaderboardRepo.Categories()
+    if err != nil {
+        return nil, err
+    }
+
+    categories := make([]string, len(boards))
+    for i, board := range boards {
+        categories[i] = strings.TrimPrefix(board, "category:")
+    }
+    return categories, nil
+}
+
+func (s *LeaderboardService) list(board string, limit int, offset int) ([]RankedEntry, int64, error) {
+    entries, total, err := s.leaderboardRepo.List(board, limit, offset)
+    if err != nil {
+        return nil, 0, err
+    }
+
+    ranked := make([]RankedEntry, len(entries))
+    for i, entry := range entries {
+        ranked[i] = RankedEntry{Rank: offset + i + 1, LeaderboardEntry: entry}
+    }
+    return ranked, total, nil
+}
+
+// leaderboardEntries works out what an archived game adds to each board
+func leaderboardEntries(session *models.GameSession) []models.LeaderboardEntry {
+    accounts := make(map[string]models.SessionPlayer)
+    for _, player := range session.Players {
+        if player.AccountID != nil {
+            accounts[player.PlayerID] = player
+        }
+    }
+    if len(accounts) == 0 {
+        return nil
+    }
+
+    // Correct answers per player, and points and correct answers per
+    // category and player
+    type categoryResult struct {
+        score   int
+        correct int
+    }
+    correct := make(map[string]int)
+    categories := make(map[string]map[string]*categoryResult)
+
+    for _, round := range session.Rounds {
+        if round.Voided {
+            continue
+        }
+
+        category := strings.ToLower(strings.TrimSpace(round.Category))
+        if category != "" && categories[category] == nil {
+            categories[category] = make(map[string]*categoryResult)
+        }
+
+        for _, answer := range round.Answers {
+            if _, ok := accounts[answer.PlayerID]; !ok {
+                continue
+            }
+            if answer.AnswerOrder > 0 {
+                correct[answer.PlayerID]++
+            }
+            if category == "" {
+                continue
+            }
+
+            result := categories[category][answer.PlayerID]
+            if result == nil {
+                result = &categoryResult{}
+                categories[category][answer.PlayerID] = result
+            }
+            result.score += answer.Score
+            if answer.AnswerOrder > 0 {
+                result.correct++
+            }
+        }
+    }
+
+    var entries []models.LeaderboardEntry
+    for playerID, player := range accounts {
+        wins := 0
+        if player.Rank == 1 {
+            wins = 1
+        }
+
+        for _, board := range []string{BoardAllTime, WeeklyBoard(session.EndedAt)} {
+            entries = append(entries, models.LeaderboardEntry{
+                Board:          board,
+                AccountID:      *player.AccountID,
+                Username:       player.Username,
+                Score:          player.TotalScore,
+                GamesPlayed:    1,
+                Wins:           wins,
+                CorrectAnswers: correct[playerID],
+                UpdatedAt:      session.EndedAt,
+            })
+        }
+
+        // Category wins aren't tracked, a game isn't won per category
+        for category, results := range categories {
+            result := results[playerID]
+            if result == nil {
+                result = &categoryResult{}
+            }
+            entries = append(entries, models.LeaderboardEntry{
+                Board:          CategoryBoard(category),
+                AccountID:      *player.AccountID,
+                Username:       player.Username,
+                Score:          result.score,
+                GamesPlayed:    1,
+                CorrectAnswers: result.correct,
+                UpdatedAt:      session.EndedAt,
+            })
+        }
+    }
+    return entries
+}
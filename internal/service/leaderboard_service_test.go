@@ -0,0 +1,64 @@
+// internal/service/leaderboard_service_test.go
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/models"
+)
+
+func TestLeaderboardEntries(t *testing.T) {
+    alice := uuid.New()
+    endedAt := time.Date(2024, 1, 17, 20, 0, 0, 0, time.UTC)
+
+    session := &models.GameSession{
+        EndedAt: endedAt,
+        Players: []models.SessionPlayer{
+            {PlayerID: alice.String(), AccountID: &alice, Username: "alice", TotalScore: 1750, Rank: 1},
+            {PlayerID: "guest", Username: "guest", TotalScore: 1000, Rank: 2},
+        },
+        Rounds: []models.SessionRound{
+            {RoundNumber: 1, Category: "Science", Answers: []models.SessionAnswer{
+                {PlayerID: alice.String(), Score: 1000, AnswerOrder: 1},
+                {PlayerID: "guest", Score: 750, AnswerOrder: 2},
+            }},
+            // Skipped, doesn't count
+            {RoundNumber: 2, Category: "History", Voided: true, Answers: []models.SessionAnswer{
+                {PlayerID: alice.String(), Score: 0, AnswerOrder: 1},
+            }},
+            {RoundNumber: 2, Category: "science", Answers: []models.SessionAnswer{
+                {PlayerID: "guest", Score: 250, AnswerOrder: 1},
+                {PlayerID: alice.String(), Score: 750, AnswerOrder: 2},
+            }},
+        },
+    }
+
+    boards := make(map[string]models.LeaderboardEntry)
+    for _, entry := range leaderboardEntries(session) {
+        if entry.AccountID != alice {
+            t.Fatalf("entry for %s, only signed-in players should be ranked", entry.AccountID)
+        }
+        boards[entry.Board] = entry
+    }
+
+    if len(boards) != 3 {
+        t.Fatalf("got boards %v, want all_time, weekly and category:science", boards)
+    }
+
+    allTime := boards[BoardAllTime]
+    if allTime.Score != 1750 || allTime.Wins != 1 || allTime.GamesPlayed != 1 || allTime.CorrectAnswers != 2 {
+        t.Errorf("all_time = %+v", allTime)
+    }
+
+    if weekly, ok := boards["weekly:2024-W03"]; !ok || weekly.Score != 1750 {
+        t.Errorf("weekly:2024-W03 = %+v", weekly)
+    }
+
+    science := boards[CategoryBoard("Science")]
+    if science.Score != 1750 || science.CorrectAnswers != 2 || science.Wins != 0 {
+        t.Errorf("category:science = %+v", science)
+    }
+}
@@ -0,0 +1,249 @@
+// internal/service/pack_service.go
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+	"github.com/rohan03122001/quizzing/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+    maxPackQuestions   = 500
+    maxPackName        = 100
+    maxPackDescription = 500
+)
+
+// PackService manages hosts' private question packs
+type PackService struct {
+    packRepo *repository.PackRepository
+    roomRepo repository.RoomStore
+}
+
+func NewPackService(packRepo *repository.PackRepository, roomRepo repository.RoomStore) *PackService {
+    return &PackService{
+        packRepo: packRepo,
+        roomRepo: roomRepo,
+    }
+}
+
+// PackUpload reports on an uploaded pack. Nothing is saved unless every
+// row is valid.
+type PackUpload struct {
+    Pack   *models.QuestionPack `json:"pack,omitempty"`
+    Rows   int                  `json:"rows"`
+    Errors []RowError           `json:"errors"`
+}
+
+// Create reads a pack's questions from a CSV or JSON file and saves it for
+// the owner
+func (s *PackService) Create(owner *models.Account, name string, description string, format string, r io.Reader) (*PackUpload, error) {
+    pack := &models.QuestionPack{OwnerID: owner.ID}
+    if err := applyPackDetails(pack, name, description); err != nil {
+        return nil, err
+    }
+
+    upload, questions, err := readPack(format, r)
+    if err != nil || len(upload.Errors) > 0 {
+        return upload, err
+    }
+
+    if err := s.packRepo.CreatePack(pack, questions); err != nil {
+        return nil, err
+    }
+    upload.Pack = pack
+    return upload, nil
+}
+
+// Replace swaps a pack's questions for those of a new file. An empty name
+// or description keeps the current one.
+func (s *PackService) Replace(owner *models.Account, id string, name string, description string, format string, r io.Reader) (*PackUpload, error) {
+    pack, err := s.ownedPack(owner, id)
+    if err != nil {
+        return nil, err
+    }
+    if name == "" {
+        name = pack.Name
+    }
+    if description == "" {
+        description = pack.Description
+    }
+    if err := applyPackDetails(pack, name, description); err != nil {
+        return nil, err
+    }
+
+    upload, questions, err := readPack(format, r)
+    if err != nil || len(upload.Errors) > 0 {
+        return upload, err
+    }
+
+    // Swapping questions mid-game would break the rounds in progress
+    if playing, err := s.packRepo.InPlay(pack.ID.String()); err != nil {
+        return nil, err
+    } else if playing {
+        return nil, ErrPackInUse
+    }
+
+    if err := s.packRepo.ReplaceQuestions(pack, questions); err != nil {
+        return nil, err
+    }
+    upload.Pack = pack
+    return upload, nil
+}
+
+// List returns the owner's packs, without their questions
+func (s *PackService) List(owner *models.Account) ([]models.QuestionPack, error) {
+    return s.packRepo.GetByOwner(owner.ID.String())
+}
+
+// Get returns one of the owner's packs with its questions
+func (s *PackService) Get(owner *models.Account, id string) (*models.QuestionPack, error) {
+    if _, err := s.ownedPack(owner, id); err != nil {
+        return nil, err
+    }
+    return s.packRepo.GetWithQuestions(id)
+}
+
+// Delete removes one of the owner's packs
+func (s *PackService) Delete(owner *models.Account, id string) error {
+    pack, err := s.ownedPack(owner, id)
+    if err != nil {
+        return err
+    }
+
+    if playing, err := s.packRepo.InPlay(pack.ID.String()); err != nil {
+        return err
+    } else if playing {
+        return ErrPackInUse
+    }
+    return s.packRepo.DeletePack(pack.ID.String())
+}
+
+// UseInRoom makes a room draw its questions from one of the account's
+// packs, or from the shared bank again if packID is empty. Only the room's
+// host can, and not during a game.
+func (s *PackService) UseInRoom(account *models.Account, roomCode string, packID string) (*models.Room, error) {
+    room, err := s.roomRepo.GetByCode(roomCode)
+    if err != nil {
+        return nil, ErrRoomNotFound
+    }
+    if room.HostID != account.ID.String() {
+        return nil, ErrNotHost
+    }
+    if room.Status == "playing" {
+        return nil, ErrGameInProgress
+    }
+
+    room.PackID = nil
+    if packID != "" {
+        pack, err := s.ownedPack(account, packID)
+        if err != nil {
+            return nil, err
+        }
+        room.PackID = &pack.ID
+    }
+
+    if err := s.roomRepo.SetPack(room.ID.String(), room.PackID); err != nil {
+        return nil, err
+    }
+    slog.Info("Set room question pack", "room", room.Code, "pack_id", room.PackID)
+    return room, nil
+}
+
+// ownedPack finds a pack that belongs to the account. Other people's packs
+// are reported as not found.
+func (s *PackService) ownedPack(owner *models.Account, id string) (*models.QuestionPack, error) {
+    if _, err := uuid.Parse(id); err != nil {
+        return nil, ErrPackNotFound
+    }
+
+    pack, err := s.packRepo.GetByID(id)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil, ErrPackNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+    if pack.OwnerID != owner.ID {
+        return nil, ErrPackNotFound
+    }
+    return pack, nil
+}
+
+func applyPackDetails(pack *models.QuestionPack, name string, description string) error {
+    name = strings.TrimSpace(name)
+    description = strings.TrimSpace(description)
+
+    switch {
+    case name == "":
+        return NewError(protocol.CodeInvalidMessage, "name is required")
+    case len(name) > maxPackName:
+        return NewError(protocol.CodeInvalidMessage, "name must be at most 100 characters")
+    case len(description) > maxPackDescription:
+        return NewError(protocol.CodeInvalidMessage, "description must be at most 500 characters")
+    }
+
+    pack.Name = name
+    pack.Description = description
+    return nil
+}
+
+// readPack parses an uploaded pack file. SQL is left to the admins' bank
+// import.
+func readPack(format string, r io.Reader) (*PackUpload, []*models.Question, error) {
+    if format != FormatCSV && format != FormatJSON {
+        return nil, nil, NewError(protocol.CodeInvalidMessage, "pack format must be csv or json")
+    }
+
+    rows, err := parseImport(format, r)
+    if err != nil {
+        return nil, nil, NewError(protocol.CodeInvalidMessage, err.Error())
+    }
+    switch {
+    case len(rows) == 0:
+        return nil, nil, NewError(protocol.CodeInvalidMessage, "pack has no questions")
+    case len(rows) > maxPackQuestions:
+        return nil, nil, NewError(protocol.CodeInvalidMessage, "a pack can have at most 500 questions")
+    }
+
+    questions, rowErrors := packQuestions(rows)
+    return &PackUpload{Rows: len(rows), Errors: rowErrors}, questions, nil
+}
+
+// packQuestions validates the rows of a pack. A question that appears
+// twice is an error, as hosts would see it twice in a game.
+func packQuestions(rows []importRow) ([]*models.Question, []RowError) {
+    rowErrors := []RowError{}
+    seen := make(map[string]int) // content key -> first row with it
+    var questions []*models.Question
+
+    for _, row := range rows {
+        if row.err != nil {
+            rowErrors = append(rowErrors, RowError{Row: row.row, Error: row.err.Error()})
+            continue
+        }
+
+        question := &models.Question{}
+        if err := applyQuestionInput(question, row.input); err != nil {
+            rowErrors = append(rowErrors, RowError{Row: row.row, Error: err.Error()})
+            continue
+        }
+
+        key := strings.ToLower(question.Content)
+        if first, exists := seen[key]; exists {
+            rowErrors = append(rowErrors, RowError{Row: row.row, Error: fmt.Sprintf("same question as row %d", first)})
+            continue
+        }
+        seen[key] = row.row
+        questions = append(questions, question)
+    }
+    return questions, rowErrors
+}
@@ -0,0 +1,48 @@
+// internal/service/pack_service_test.go
+
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadPack(t *testing.T) {
+    data := "content,answer,category\n" +
+        "Which floor is the canteen on?,3rd,Office\n" +
+        "Who won last year's Diwali quiz?,,Office\n" +
+        "which floor is the canteen on? ,Third,Office\n" +
+        "What is our wifi called?,guest-net,\n"
+
+    upload, questions, err := readPack(FormatCSV, strings.NewReader(data))
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    if upload.Rows != 4 || len(questions) != 2 {
+        t.Fatalf("got %d rows and %d questions, want 4 and 2", upload.Rows, len(questions))
+    }
+
+    if len(upload.Errors) != 2 {
+        t.Fatalf("got errors %v, want 2", upload.Errors)
+    }
+    if upload.Errors[0].Row != 3 || upload.Errors[0].Error != "answer is required" {
+        t.Errorf("got %+v for the row without an answer", upload.Errors[0])
+    }
+    if upload.Errors[1].Row != 4 || upload.Errors[1].Error != "same question as row 2" {
+        t.Errorf("got %+v for the repeated question", upload.Errors[1])
+    }
+}
+
+func TestReadPackFormats(t *testing.T) {
+    if _, _, err := readPack(FormatSQL, strings.NewReader("INSERT INTO questions (content, answer) VALUES ('a', 'b');")); err == nil {
+        t.Error("SQL packs should be refused")
+    }
+    if _, _, err := readPack(FormatJSON, strings.NewReader("[]")); err == nil {
+        t.Error("an empty pack should be refused")
+    }
+
+    upload, questions, err := readPack(FormatJSON, strings.NewReader(`[{"content": "Who runs the Friday standup?", "answer": "Priya"}]`))
+    if err != nil || len(upload.Errors) != 0 || len(questions) != 1 {
+        t.Errorf("got %+v, %d questions, %v", upload, len(questions), err)
+    }
+}
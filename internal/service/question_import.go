@@ -0,0 +1,615 @@
+// internal/service/question_import.go
+
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+	"github.com/rohan03122001/quizzing/internal/repository"
+)
+
+// Formats the question bank can be imported from and exported to. SQL is
+// import only, for the INSERT statements of the seed files.
+const (
+    FormatCSV  = "csv"
+    FormatJSON = "json"
+    FormatSQL  = "sql"
+)
+
+// csvColumns are the columns of an exported CSV file. Aliases are
+// separated by "|".
+var csvColumns = []string{"content", "answer", "aliases", "category", "difficulty", "media_url", "media_type"}
+
+// RowError is a problem with one row of an import. Row is the line number
+// in CSV and SQL files, and the position in the array (from 1) in JSON.
+type RowError struct {
+    Row   int    `json:"row"`
+    Error string `json:"error"`
+}
+
+// ImportResult reports what an import did, or would do on a dry run.
+// Nothing is added unless every row is valid.
+type ImportResult struct {
+    DryRun     bool       `json:"dry_run"`
+    Applied    bool       `json:"applied"`
+    Rows       int        `json:"rows"`
+    Added      int        `json:"added"`      // New questions, added or to be added
+    Duplicates []RowError `json:"duplicates"` // Rows skipped because the question already exists
+    Errors     []RowError `json:"errors"`
+}
+
+// importRow is a question read from an import file
+type importRow struct {
+    row   int
+    input QuestionInput
+    err   error
+}
+
+// FormatFromFilename guesses an import format from a file's extension
+func FormatFromFilename(name string) string {
+    switch strings.ToLower(filepath.Ext(name)) {
+    case ".csv":
+        return FormatCSV
+    case ".json":
+        return FormatJSON
+    case ".sql":
+        return FormatSQL
+    }
+    return ""
+}
+
+// Import reads questions in the given format, validates every row and
+// skips questions that are already in the bank. Unless dryRun is set, the
+// new questions are added in a single transaction if there were no errors.
+func (s *QuestionService) Import(format string, r io.Reader, dryRun bool) (*ImportResult, error) {
+    rows, err := parseImport(format, r)
+    if err != nil {
+        return nil, NewError(protocol.CodeInvalidMessage, err.Error())
+    }
+
+    existing, err := s.questionRepo.ContentKeys()
+    if err != nil {
+        return nil, err
+    }
+
+    result := &ImportResult{
+        DryRun:     dryRun,
+        Rows:       len(rows),
+        Duplicates: []RowError{},
+        Errors:     []RowError{},
+    }
+
+    inFile := make(map[string]int) // content key -> first row with it
+    var questions []*models.Question
+    for _, row := range rows {
+        if row.err != nil {
+            result.Errors = append(result.Errors, RowError{Row: row.row, Error: row.err.Error()})
+            continue
+        }
+
+        question := &models.Question{}
+        if err := applyQuestionInput(question, row.input); err != nil {
+            result.Errors = append(result.Errors, RowError{Row: row.row, Error: err.Error()})
+            continue
+        }
+
+        key := strings.ToLower(question.Content)
+        if existing[key] {
+            result.Duplicates = append(result.Duplicates, RowError{Row: row.row, Error: "question already exists"})
+            continue
+        }
+        if first, seen := inFile[key]; seen {
+            result.Duplicates = append(result.Duplicates, RowError{Row: row.row, Error: fmt.Sprintf("same question as row %d", first)})
+            continue
+        }
+        inFile[key] = row.row
+        questions = append(questions, question)
+    }
+    result.Added = len(questions)
+
+    if dryRun || len(result.Errors) > 0 || len(questions) == 0 {
+        slog.Info("Checked question import", "rows", result.Rows, "new", result.Added,
+            "duplicates", len(result.Duplicates), "errors", len(result.Errors), "dry_run", dryRun)
+        return result, nil
+    }
+
+    if err := s.questionRepo.CreateQuestions(questions); err != nil {
+        return nil, err
+    }
+    result.Applied = true
+    return result, nil
+}
+
+// Export writes the questions matching the filter as CSV or JSON, in a
+// form Import reads back
+func (s *QuestionService) Export(format string, w io.Writer, filter repository.QuestionFilter) error {
+    questions, err := s.questionRepo.ListAll(filter)
+    if err != nil {
+        return err
+    }
+
+    switch format {
+    case FormatCSV:
+        writer := csv.NewWriter(w)
+        if err := writer.Write(csvColumns); err != nil {
+            return err
+        }
+        for _, q := range questions {
+            record := []string{
+                q.Content,
+                q.Answer,
+                strings.Join(q.Aliases, "|"),
+                q.Category,
+                strconv.Itoa(q.Difficulty),
+                q.MediaURL,
+                q.MediaType,
+            }
+            if err := writer.Write(record); err != nil {
+                return err
+            }
+        }
+        writer.Flush()
+        return writer.Error()
+
+    case FormatJSON:
+        inputs := make([]QuestionInput, len(questions))
+        for i, q := range questions {
+            inputs[i] = QuestionInput{
+                Content:    q.Content,
+                Answer:     q.Answer,
+                Aliases:    q.Aliases,
+                Category:   q.Category,
+                Difficulty: q.Difficulty,
+                MediaURL:   q.MediaURL,
+                MediaType:  q.MediaType,
+            }
+        }
+        encoder := json.NewEncoder(w)
+        encoder.SetIndent("", "  ")
+        return encoder.Encode(inputs)
+    }
+
+    return NewError(protocol.CodeInvalidMessage, "export format must be csv or json")
+}
+
+func parseImport(format string, r io.Reader) ([]importRow, error) {
+    switch format {
+    case FormatCSV:
+        return parseCSVQuestions(r)
+    case FormatJSON:
+        return parseJSONQuestions(r)
+    case FormatSQL:
+        data, err := io.ReadAll(r)
+        if err != nil {
+            return nil, err
+        }
+        return parseSQLQuestions(string(data))
+    }
+    return nil, fmt.Errorf("import format must be csv, json or sql")
+}
+
+// parseCSVQuestions reads a CSV file with a header row. Columns are matched
+// by name, in any order; unknown columns are ignored.
+func parseCSVQuestions(r io.Reader) ([]importRow, error) {
+    reader := csv.NewReader(r)
+    reader.FieldsPerRecord = -1
+    reader.TrimLeadingSpace = true
+
+    header, err := reader.Read()
+    if err == io.EOF {
+        return nil, fmt.Errorf("file is empty")
+    }
+    if err != nil {
+        return nil, fmt.Errorf("invalid CSV: %v", err)
+    }
+    for i, name := range header {
+        header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+    }
+    if !contains(header, "content") || !contains(header, "answer") {
+        return nil, fmt.Errorf("CSV header needs content and answer columns")
+    }
+
+    var rows []importRow
+    for {
+        record, err := reader.Read()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return nil, fmt.Errorf("invalid CSV: %v", err)
+        }
+        line, _ := reader.FieldPos(0)
+
+        fields := make(map[string]string, len(header))
+        for i, name := range header {
+            if i < len(record) {
+                fields[name] = record[i]
+            }
+        }
+        if isBlank(fields) {
+            continue
+        }
+
+        input, err := inputFromFields(fields)
+        rows = append(rows, importRow{row: line, input: input, err: err})
+    }
+    return rows, nil
+}
+
+// parseJSONQuestions reads an array of questions shaped like QuestionInput
+func parseJSONQuestions(r io.Reader) ([]importRow, error) {
+    var items []json.RawMessage
+    if err := json.NewDecoder(r).Decode(&items); err != nil {
+        return nil, fmt.Errorf("invalid JSON: expected an array of questions: %v", err)
+    }
+
+    rows := make([]importRow, len(items))
+    for i, item := range items {
+        var input struct {
+            QuestionInput
+            ImageURL string `json:"image_url"` // As in the seed files
+        }
+        err := json.Unmarshal(item, &input)
+        if err == nil && input.MediaURL == "" {
+            input.MediaURL = input.ImageURL
+        }
+        rows[i] = importRow{row: i + 1, input: input.QuestionInput, err: err}
+    }
+    return rows, nil
+}
+
+// parseSQLQuestions reads the rows of INSERT INTO questions statements, as
+// in the seed files. Other statements are skipped.
+func parseSQLQuestions(data string) ([]importRow, error) {
+    inserts, err := parseSQLInserts(data)
+    if err != nil {
+        return nil, err
+    }
+
+    var rows []importRow
+    for _, insert := range inserts {
+        if insert.table != "questions" {
+            continue
+        }
+        input, err := inputFromFields(insert.fields)
+        rows = append(rows, importRow{row: insert.line, input: input, err: err})
+    }
+    return rows, nil
+}
+
+// inputFromFields builds a question from named text fields, as read from
+// CSV or SQL
+func inputFromFields(fields map[string]string) (QuestionInput, error) {
+    input := QuestionInput{
+        Content:   fields["content"],
+        Answer:    fields["answer"],
+        Category:  fields["category"],
+        MediaURL:  fields["media_url"],
+        MediaType: fields["media_type"],
+    }
+    if input.MediaURL == "" {
+        input.MediaURL = fields["image_url"]
+    }
+
+    if aliases := strings.TrimSpace(fields["aliases"]); aliases != "" {
+        // Either a JSON array or separated by "|"
+        if err := json.Unmarshal([]byte(aliases), &input.Aliases); err != nil {
+            input.Aliases = strings.Split(aliases, "|")
+        }
+    }
+
+    if difficulty := strings.TrimSpace(fields["difficulty"]); difficulty != "" {
+        level, err := strconv.Atoi(difficulty)
+        if err != nil {
+            return input, fmt.Errorf("difficulty must be a number")
+        }
+        input.Difficulty = level
+    }
+    return input, nil
+}
+
+func contains(values []string, value string) bool {
+    for _, v := range values {
+        if v == value {
+            return true
+        }
+    }
+    return false
+}
+
+func isBlank(fields map[string]string) bool {
+    for _, value := range fields {
+        if strings.TrimSpace(value) != "" {
+            return false
+        }
+    }
+    return true
+}
+
+// sqlInsert is one row of an INSERT statement
+type sqlInsert struct {
+    table  string
+    line   int
+    fields map[string]string
+}
+
+// parseSQLInserts reads the rows of every INSERT INTO ... (columns) VALUES
+// statement. String and number literals are read as text; NULL and
+// function calls like NOW() or gen_random_uuid() are read as empty.
+func parseSQLInserts(data string) ([]sqlInsert, error) {
+    p := &sqlParser{data: data}
+    var inserts []sqlInsert
+
+    for {
+        p.skipSpace()
+        if p.done() {
+            return inserts, nil
+        }
+        if !p.keyword("INSERT") {
+            p.skipStatement()
+            continue
+        }
+        if !p.keyword("INTO") {
+            return nil, p.errorf("expected INTO")
+        }
+
+        table := p.identifier()
+        if dot := strings.LastIndex(table, "."); dot >= 0 {
+            table = table[dot+1:]
+        }
+        table = strings.ToLower(table)
+
+        columns, err := p.columns()
+        if err != nil {
+            return nil, err
+        }
+        if !p.keyword("VALUES") {
+            return nil, p.errorf("expected VALUES")
+        }
+
+        for {
+            p.skipSpace()
+            line := p.line()
+            values, err := p.tuple()
+            if err != nil {
+                return nil, err
+            }
+            if len(values) != len(columns) {
+                return nil, fmt.Errorf("line %d: %d values for %d columns", line, len(values), len(columns))
+            }
+
+            fields := make(map[string]string, len(columns))
+            for i, column := range columns {
+                fields[column] = values[i]
+            }
+            inserts = append(inserts, sqlInsert{table: table, line: line, fields: fields})
+
+            p.skipSpace()
+            if !p.consume(',') {
+                break
+            }
+        }
+        // Skips ON CONFLICT clauses and the like
+        p.skipStatement()
+    }
+}
+
+type sqlParser struct {
+    data string
+    pos  int
+}
+
+func (p *sqlParser) done() bool {
+    return p.pos >= len(p.data)
+}
+
+func (p *sqlParser) line() int {
+    return strings.Count(p.data[:p.pos], "\n") + 1
+}
+
+func (p *sqlParser) errorf(format string, args ...interface{}) error {
+    return fmt.Errorf("line %d: %s", p.line(), fmt.Sprintf(format, args...))
+}
+
+// skipSpace skips whitespace and comments
+func (p *sqlParser) skipSpace() {
+    for !p.done() {
+        switch {
+        case strings.HasPrefix(p.data[p.pos:], "--"):
+            if end := strings.IndexByte(p.data[p.pos:], '\n'); end >= 0 {
+                p.pos += end + 1
+            } else {
+                p.pos = len(p.data)
+            }
+        case strings.HasPrefix(p.data[p.pos:], "/*"):
+            if end := strings.Index(p.data[p.pos+2:], "*/"); end >= 0 {
+                p.pos += end + 4
+            } else {
+                p.pos = len(p.data)
+            }
+        case strings.ContainsRune(" \t\r\n", rune(p.data[p.pos])):
+            p.pos++
+        default:
+            return
+        }
+    }
+}
+
+// skipStatement moves past the next semicolon outside a string
+func (p *sqlParser) skipStatement() {
+    for !p.done() {
+        switch p.data[p.pos] {
+        case ';':
+            p.pos++
+            return
+        case '\'':
+            p.str()
+        default:
+            p.pos++
+        }
+    }
+}
+
+func (p *sqlParser) consume(c byte) bool {
+    p.skipSpace()
+    if !p.done() && p.data[p.pos] == c {
+        p.pos++
+        return true
+    }
+    return false
+}
+
+// keyword consumes a keyword, ignoring case
+func (p *sqlParser) keyword(word string) bool {
+    p.skipSpace()
+    end := p.pos + len(word)
+    if end > len(p.data) || !strings.EqualFold(p.data[p.pos:end], word) {
+        return false
+    }
+    if end < len(p.data) && isIdentifierChar(p.data[end]) {
+        return false
+    }
+    p.pos = end
+    return true
+}
+
+// identifier reads a name, possibly quoted or qualified like public.questions
+func (p *sqlParser) identifier() string {
+    p.skipSpace()
+    start := p.pos
+    for !p.done() && (isIdentifierChar(p.data[p.pos]) || p.data[p.pos] == '.' || p.data[p.pos] == '"') {
+        p.pos++
+    }
+    return strings.ReplaceAll(p.data[start:p.pos], `"`, "")
+}
+
+func (p *sqlParser) columns() ([]string, error) {
+    if !p.consume('(') {
+        return nil, p.errorf("expected a column list")
+    }
+    var columns []string
+    for {
+        column := p.identifier()
+        if column == "" {
+            return nil, p.errorf("expected a column name")
+        }
+        columns = append(columns, strings.ToLower(column))
+        if p.consume(')') {
+            return columns, nil
+        }
+        if !p.consume(',') {
+            return nil, p.errorf("expected , or )")
+        }
+    }
+}
+
+func (p *sqlParser) tuple() ([]string, error) {
+    if !p.consume('(') {
+        return nil, p.errorf("expected (")
+    }
+    var values []string
+    for {
+        value, err := p.value()
+        if err != nil {
+            return nil, err
+        }
+        values = append(values, value)
+        if p.consume(')') {
+            return values, nil
+        }
+        if !p.consume(',') {
+            return nil, p.errorf("expected , or )")
+        }
+    }
+}
+
+func (p *sqlParser) value() (string, error) {
+    p.skipSpace()
+    if p.done() {
+        return "", p.errorf("unexpected end of file")
+    }
+
+    c := p.data[p.pos]
+    switch {
+    case c == '\'':
+        return p.str()
+    case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+        start := p.pos
+        p.pos++
+        for !p.done() && (isIdentifierChar(p.data[p.pos]) || p.data[p.pos] == '.') {
+            p.pos++
+        }
+        return p.data[start:p.pos], nil
+    case isIdentifierChar(c):
+        word := p.identifier()
+        p.skipSpace()
+        if !p.done() && p.data[p.pos] == '(' {
+            // A function call, like NOW()
+            return "", p.skipParens()
+        }
+        if strings.EqualFold(word, "NULL") || strings.EqualFold(word, "DEFAULT") {
+            return "", nil
+        }
+        return word, nil
+    }
+    return "", p.errorf("unexpected %q", c)
+}
+
+// str reads a single-quoted string, where '' is a quote
+func (p *sqlParser) str() (string, error) {
+    line := p.line()
+    p.pos++ // Opening quote
+    var b bytes.Buffer
+    for !p.done() {
+        c := p.data[p.pos]
+        p.pos++
+        if c != '\'' {
+            b.WriteByte(c)
+            continue
+        }
+        if !p.done() && p.data[p.pos] == '\'' {
+            b.WriteByte('\'')
+            p.pos++
+            continue
+        }
+        return b.String(), nil
+    }
+    return "", fmt.Errorf("line %d: unterminated string", line)
+}
+
+func (p *sqlParser) skipParens() error {
+    depth := 0
+    for !p.done() {
+        switch p.data[p.pos] {
+        case '(':
+            depth++
+        case ')':
+            depth--
+            if depth == 0 {
+                p.pos++
+                return nil
+            }
+        case '\'':
+            if _, err := p.str(); err != nil {
+                return err
+            }
+            continue
+        }
+        p.pos++
+    }
+    return p.errorf("unbalanced parentheses")
+}
+
+func isIdentifierChar(c byte) bool {
+    return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,142 @@
+// internal/service/question_import_test.go
+
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCSVQuestions(t *testing.T) {
+    data := "Content,Answer,Aliases,Category,Difficulty,image_url\n" +
+        "What is the capital of France?,Paris,,Geography,1,\n" +
+        "\n" +
+        "\"Who is known as the \"\"Little Master\"\"?\",Sachin Tendulkar,Sachin|Tendulkar,Sports,two,\n" +
+        "Identify this actor.,Shah Rukh Khan,SRK,Bollywood,,https://example.com/srk.jpg\n"
+
+    rows, err := parseCSVQuestions(strings.NewReader(data))
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    if len(rows) != 3 {
+        t.Fatalf("got %d rows, want 3 (blank lines skipped)", len(rows))
+    }
+
+    if rows[0].row != 2 || rows[0].input.Answer != "Paris" || rows[0].input.Difficulty != 1 {
+        t.Errorf("got row %+v", rows[0])
+    }
+    if rows[1].row != 4 || rows[1].err == nil {
+        t.Errorf("row 4 has a bad difficulty, got %+v", rows[1])
+    }
+    if rows[1].input.Content != `Who is known as the "Little Master"?` {
+        t.Errorf("got content %q", rows[1].input.Content)
+    }
+    if want := []string{"SRK"}; !reflect.DeepEqual(rows[2].input.Aliases, want) || rows[2].input.MediaURL != "https://example.com/srk.jpg" {
+        t.Errorf("got row %+v", rows[2])
+    }
+}
+
+func TestParseCSVQuestionsNeedsHeader(t *testing.T) {
+    if _, err := parseCSVQuestions(strings.NewReader("question,solution\nA,B\n")); err == nil {
+        t.Error("expected an error without content and answer columns")
+    }
+}
+
+func TestParseJSONQuestions(t *testing.T) {
+    data := `[
+        {"content": "What is 2 + 2?", "answer": "4", "aliases": ["four"], "difficulty": 1},
+        {"content": "Name this actor.", "answer": "Aamir Khan", "image_url": "https://example.com/aamir.jpg"},
+        {"content": "Bad row", "difficulty": "hard"}
+    ]`
+
+    rows, err := parseJSONQuestions(strings.NewReader(data))
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    if len(rows) != 3 {
+        t.Fatalf("got %d rows, want 3", len(rows))
+    }
+    if rows[0].input.Aliases[0] != "four" || rows[0].row != 1 {
+        t.Errorf("got row %+v", rows[0])
+    }
+    if rows[1].input.MediaURL != "https://example.com/aamir.jpg" {
+        t.Errorf("image_url should be read as media_url, got %+v", rows[1])
+    }
+    if rows[2].err == nil {
+        t.Error("row 3 has a bad difficulty")
+    }
+}
+
+func TestParseSQLQuestions(t *testing.T) {
+    data := `-- seed data
+INSERT INTO questions (id, category, content, answer, image_url, created_at) VALUES (1, 'Sports', 'Identify this Indian cricketer known as ''Captain Cool''.', 'MS Dhoni', 'https://example.com/dhoni.jpg', '2025-03-29 13:46:23');
+CREATE INDEX idx ON questions (category);
+INSERT INTO public.questions (id, content, answer, category, created_at) VALUES
+(gen_random_uuid(), 'Which is India"s harvest festival in Punjab?', 'Lohri', 'Culture', NOW()),
+(gen_random_uuid(), 'What is 2 + 2?', '4', NULL, NOW()) ON CONFLICT DO NOTHING;
+INSERT INTO rooms (id, code) VALUES (1, 'ABC123');
+`
+
+    rows, err := parseSQLQuestions(data)
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    if len(rows) != 3 {
+        t.Fatalf("got %d rows, want 3", len(rows))
+    }
+
+    first := rows[0]
+    if first.row != 2 || first.input.Content != "Identify this Indian cricketer known as 'Captain Cool'." ||
+        first.input.MediaURL != "https://example.com/dhoni.jpg" || first.input.Category != "Sports" {
+        t.Errorf("got row %+v", first)
+    }
+    if rows[1].row != 5 || rows[1].input.Content != `Which is India"s harvest festival in Punjab?` {
+        t.Errorf("got row %+v", rows[1])
+    }
+    if rows[2].input.Category != "" || rows[2].input.Answer != "4" {
+        t.Errorf("got row %+v", rows[2])
+    }
+}
+
+func TestParseSQLQuestionsErrors(t *testing.T) {
+    cases := []string{
+        "INSERT INTO questions (content, answer) VALUES ('a');",
+        "INSERT INTO questions (content, answer) VALUES ('a', 'b",
+        "INSERT INTO questions VALUES ('a', 'b');",
+    }
+    for _, data := range cases {
+        if _, err := parseSQLQuestions(data); err == nil {
+            t.Errorf("expected an error for %q", data)
+        }
+    }
+}
+
+// The seed files in the repository should all import cleanly
+func TestParseSeedFiles(t *testing.T) {
+    files, _ := filepath.Glob("../../*.sql")
+    scripts, _ := filepath.Glob("../../init_scripts/*.sql")
+    files = append(files, scripts...)
+    for _, file := range files {
+        data, err := os.ReadFile(file)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        rows, err := parseSQLQuestions(string(data))
+        if err != nil {
+            t.Errorf("%s: %v", file, err)
+            continue
+        }
+        if len(rows) == 0 {
+            t.Errorf("%s: no questions found", file)
+        }
+        for _, row := range rows {
+            if row.err != nil || row.input.Content == "" || row.input.Answer == "" {
+                t.Errorf("%s:%d: got %+v", file, row.row, row)
+            }
+        }
+    }
+}
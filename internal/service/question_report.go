@@ -0,0 +1,255 @@
+// internal/service/question_report.go
+
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/repository"
+)
+
+const (
+    // Players needed before a question's correctness rate is flagged
+    minAnswersForFlags = 10
+
+    // Correctness rates this close to 0% or 100% get flagged
+    flagMargin = 0.05
+
+    // Wrong answers listed per question
+    maxWrongAnswers = 5
+)
+
+// Quality flags
+const (
+    FlagTooHard      = "too_hard"      // Almost nobody gets it right
+    FlagTooEasy      = "too_easy"      // Almost everybody gets it right
+    FlagOftenSkipped = "often_skipped" // Hosts skip it a lot
+)
+
+// WrongAnswer is a wrong answer and how many times it was given
+type WrongAnswer struct {
+    Answer string `json:"answer"`
+    Count  int    `json:"count"`
+}
+
+// QuestionQuality is how a question has fared in archived games
+type QuestionQuality struct {
+    QuestionID         uuid.UUID     `json:"question_id"`
+    Content            string        `json:"content"`
+    Answer             string        `json:"answer,omitempty"`
+    Category           string        `json:"category,omitempty"`
+    Deleted            bool          `json:"deleted"`                 // No longer in the question bank
+    TimesAsked         int           `json:"times_asked"`             // Rounds that counted
+    TimesSkipped       int           `json:"times_skipped"`           // Rounds voided by the host
+    Attempts           int           `json:"attempts"`                // Players who answered, per round
+    Correct            int           `json:"correct"`
+    CorrectnessRate    float64       `json:"correctness_rate"`        // 0-1
+    AverageCorrectTime float64       `json:"average_correct_seconds"` // From question to correct answer
+    WrongAnswers       []WrongAnswer `json:"common_wrong_answers"`    // Most given first
+    Flags              []string      `json:"flags"`
+}
+
+// Report computes the quality of every question asked in archived games,
+// optionally only one category's. Flagged questions come first, then the
+// most answered.
+func (s *QuestionService) Report(category string, flaggedOnly bool) ([]QuestionQuality, error) {
+    records, err := s.historyRepo.GetAnswerRecords(category)
+    if err != nil {
+        return nil, err
+    }
+
+    seen := make(map[uuid.UUID]bool)
+    var ids []string
+    for _, record := range records {
+        if !seen[record.QuestionID] {
+            seen[record.QuestionID] = true
+            ids = append(ids, record.QuestionID.String())
+        }
+    }
+
+    questions, err := s.questionRepo.GetByIDs(ids)
+    if err != nil {
+        return nil, err
+    }
+    current := make(map[uuid.UUID]*models.Question, len(questions))
+    for i := range questions {
+        current[questions[i].ID] = &questions[i]
+    }
+
+    // Questions of private packs are their owners' business
+    public := records[:0]
+    for _, record := range records {
+        if question, ok := current[record.QuestionID]; ok && question.PackID != nil {
+            continue
+        }
+        public = append(public, record)
+    }
+    records = public
+
+    report := buildQuestionReport(records, current)
+    if flaggedOnly {
+        flagged := report[:0]
+        for _, quality := range report {
+            if len(quality.Flags) > 0 {
+                flagged = append(flagged, quality)
+            }
+        }
+        report = flagged
+    }
+    return report, nil
+}
+
+// buildQuestionReport aggregates archived answers per question. Wrong
+// answers the question now accepts, through an alias added since, are left
+// out.
+func buildQuestionReport(records []repository.AnswerRecord, current map[uuid.UUID]*models.Question) []QuestionQuality {
+    type attempt struct {
+        question uuid.UUID
+        round    uuid.UUID
+        player   string
+    }
+    type wrongTally struct {
+        answer string
+        count  int
+        first  int
+    }
+
+    byQuestion := make(map[uuid.UUID]*QuestionQuality)
+    var order []uuid.UUID
+    rounds := make(map[uuid.UUID]bool)
+    attempts := make(map[attempt]bool) // -> answered correctly
+    correctSeconds := make(map[uuid.UUID]float64)
+    wrong := make(map[uuid.UUID]map[string]*wrongTally)
+
+    for _, record := range records {
+        quality, exists := byQuestion[record.QuestionID]
+        if !exists {
+            quality = &QuestionQuality{
+                QuestionID:   record.QuestionID,
+                Content:      record.Question,
+                Category:     record.Category,
+                Deleted:      true,
+                WrongAnswers: []WrongAnswer{},
+                Flags:        []string{},
+            }
+            if question, ok := current[record.QuestionID]; ok {
+                quality.Content = question.Content
+                quality.Answer = question.Answer
+                quality.Category = question.Category
+                quality.Deleted = false
+            }
+            byQuestion[record.QuestionID] = quality
+            order = append(order, record.QuestionID)
+            wrong[record.QuestionID] = make(map[string]*wrongTally)
+        }
+
+        if !rounds[record.RoundID] {
+            rounds[record.RoundID] = true
+            if record.Voided {
+                quality.TimesSkipped++
+            } else {
+                quality.TimesAsked++
+            }
+        }
+        if record.Voided || record.PlayerID == nil {
+            continue
+        }
+
+        key := attempt{record.QuestionID, record.RoundID, *record.PlayerID}
+        correct, answered := attempts[key]
+        if !answered {
+            quality.Attempts++
+            attempts[key] = false
+        }
+
+        isCorrect := record.AnswerOrder != nil && *record.AnswerOrder > 0
+        if isCorrect && !correct {
+            attempts[key] = true
+            quality.Correct++
+            if record.AnsweredAt != nil {
+                correctSeconds[record.QuestionID] += record.AnsweredAt.Sub(record.StartTime).Seconds()
+            }
+            continue
+        }
+        if isCorrect || record.Answer == nil {
+            continue
+        }
+
+        // A wrong answer
+        answer := strings.TrimSpace(*record.Answer)
+        if answer == "" {
+            continue
+        }
+        if question, ok := current[record.QuestionID]; ok && answerMatches(answer, question) {
+            continue
+        }
+        normalized := cleanStringForComparison(answer)
+        tally, exists := wrong[record.QuestionID][normalized]
+        if !exists {
+            tally = &wrongTally{answer: answer, first: len(wrong[record.QuestionID])}
+            wrong[record.QuestionID][normalized] = tally
+        }
+        tally.count++
+    }
+
+    report := make([]QuestionQuality, 0, len(order))
+    for _, id := range order {
+        quality := byQuestion[id]
+
+        if quality.Attempts > 0 {
+            quality.CorrectnessRate = float64(quality.Correct) / float64(quality.Attempts)
+        }
+        if quality.Correct > 0 {
+            quality.AverageCorrectTime = correctSeconds[id] / float64(quality.Correct)
+        }
+
+        var tallies []*wrongTally
+        for _, tally := range wrong[id] {
+            tallies = append(tallies, tally)
+        }
+        sort.Slice(tallies, func(i, j int) bool {
+            if tallies[i].count != tallies[j].count {
+                return tallies[i].count > tallies[j].count
+            }
+            return tallies[i].first < tallies[j].first
+        })
+        for i, tally := range tallies {
+            if i == maxWrongAnswers {
+                break
+            }
+            quality.WrongAnswers = append(quality.WrongAnswers, WrongAnswer{Answer: tally.answer, Count: tally.count})
+        }
+
+        quality.Flags = qualityFlags(quality)
+        report = append(report, *quality)
+    }
+
+    sort.SliceStable(report, func(i, j int) bool {
+        flaggedI, flaggedJ := len(report[i].Flags) > 0, len(report[j].Flags) > 0
+        if flaggedI != flaggedJ {
+            return flaggedI
+        }
+        return report[i].Attempts > report[j].Attempts
+    })
+    return report
+}
+
+func qualityFlags(quality *QuestionQuality) []string {
+    flags := []string{}
+    if quality.Attempts >= minAnswersForFlags {
+        if quality.CorrectnessRate <= flagMargin {
+            flags = append(flags, FlagTooHard)
+        }
+        if quality.CorrectnessRate >= 1-flagMargin {
+            flags = append(flags, FlagTooEasy)
+        }
+    }
+    // Skipped in at least a quarter of the games it came up in
+    if quality.TimesSkipped >= 2 && quality.TimesSkipped*4 >= quality.TimesAsked+quality.TimesSkipped {
+        flags = append(flags, FlagOftenSkipped)
+    }
+    return flags
+}
@@ -0,0 +1,104 @@
+// internal/service/question_report_test.go
+
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/repository"
+)
+
+func answerRecord(questionID uuid.UUID, roundID uuid.UUID, start time.Time, player string, answer string, order int, seconds int) repository.AnswerRecord {
+    answeredAt := start.Add(time.Duration(seconds) * time.Second)
+    return repository.AnswerRecord{
+        QuestionID:  questionID,
+        RoundID:     roundID,
+        Question:    "Which festival celebrates the burning of Ravana?",
+        StartTime:   start,
+        PlayerID:    &player,
+        Answer:      &answer,
+        AnswerOrder: &order,
+        AnsweredAt:  &answeredAt,
+    }
+}
+
+func TestBuildQuestionReport(t *testing.T) {
+    questionID := uuid.New()
+    roundID := uuid.New()
+    start := time.Date(2024, 1, 17, 20, 0, 0, 0, time.UTC)
+
+    records := []repository.AnswerRecord{
+        answerRecord(questionID, roundID, start, "alice", "Diwali", 0, 3),
+        answerRecord(questionID, roundID, start, "alice", "Dussehra", 1, 5),
+        answerRecord(questionID, roundID, start, "bob", "diwali!", 0, 4),
+        answerRecord(questionID, roundID, start, "carol", "Vijayadashami", 0, 6),
+        answerRecord(questionID, roundID, start, "dave", "Holi", 0, 7),
+        answerRecord(questionID, roundID, start, "erin", "Dussehra", 2, 9),
+        // Skipped by the host
+        {QuestionID: questionID, RoundID: uuid.New(), Voided: true},
+    }
+    current := map[uuid.UUID]*models.Question{
+        questionID: {ID: questionID, Content: "Which festival celebrates the burning of Ravana?", Answer: "Dussehra",
+            Aliases: models.StringList{"Vijayadashami"}},
+    }
+
+    report := buildQuestionReport(records, current)
+    if len(report) != 1 {
+        t.Fatalf("got %d questions, want 1", len(report))
+    }
+    quality := report[0]
+
+    if quality.TimesAsked != 1 || quality.TimesSkipped != 1 {
+        t.Errorf("got asked %d skipped %d, want 1 and 1", quality.TimesAsked, quality.TimesSkipped)
+    }
+    if quality.Attempts != 5 || quality.Correct != 2 || quality.CorrectnessRate != 0.4 {
+        t.Errorf("got %d/%d correct (%v), want 2/5", quality.Correct, quality.Attempts, quality.CorrectnessRate)
+    }
+    if quality.AverageCorrectTime != 7 {
+        t.Errorf("got average correct time %v, want 7", quality.AverageCorrectTime)
+    }
+
+    // Vijayadashami is an alias now, so it no longer counts as wrong
+    want := []WrongAnswer{{Answer: "Diwali", Count: 2}, {Answer: "Holi", Count: 1}}
+    if !reflect.DeepEqual(quality.WrongAnswers, want) {
+        t.Errorf("got wrong answers %v, want %v", quality.WrongAnswers, want)
+    }
+    if len(quality.Flags) != 0 || quality.Deleted {
+        t.Errorf("got flags %v deleted %v, want none", quality.Flags, quality.Deleted)
+    }
+}
+
+func TestBuildQuestionReportFlags(t *testing.T) {
+    easy, hard := uuid.New(), uuid.New()
+    start := time.Now()
+
+    var records []repository.AnswerRecord
+    for i := 0; i < minAnswersForFlags; i++ {
+        player := fmt.Sprintf("player%d", i)
+        records = append(records,
+            answerRecord(easy, uuid.New(), start, player, "Paris", 1, 2),
+            answerRecord(hard, uuid.New(), start, player, "no idea", 0, 2),
+        )
+    }
+
+    report := buildQuestionReport(records, nil)
+    flags := map[uuid.UUID][]string{}
+    for _, quality := range report {
+        flags[quality.QuestionID] = quality.Flags
+        if !quality.Deleted {
+            t.Errorf("question %s isn't in the bank, should be marked deleted", quality.QuestionID)
+        }
+    }
+
+    if !reflect.DeepEqual(flags[easy], []string{FlagTooEasy}) {
+        t.Errorf("got flags %v for the easy question", flags[easy])
+    }
+    if !reflect.DeepEqual(flags[hard], []string{FlagTooHard}) {
+        t.Errorf("got flags %v for the hard question", flags[hard])
+    }
+}
@@ -0,0 +1,204 @@
+// internal/service/question_service.go
+
+package service
+
+import (
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+	"github.com/rohan03122001/quizzing/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+    maxDifficulty    = 3
+    maxContentLength = 1000
+    maxAnswerLength  = 200
+)
+
+// mediaTypes are the kinds of media a question can show
+var mediaTypes = map[string]bool{"image": true, "audio": true, "video": true}
+
+// QuestionService manages the question bank
+type QuestionService struct {
+    questionRepo repository.QuestionStore
+    historyRepo  repository.HistoryStore
+}
+
+func NewQuestionService(questionRepo repository.QuestionStore, historyRepo repository.HistoryStore) *QuestionService {
+    return &QuestionService{
+        questionRepo: questionRepo,
+        historyRepo:  historyRepo,
+    }
+}
+
+// QuestionInput is a question as written by an editor
+type QuestionInput struct {
+    Content    string   `json:"content"`
+    Answer     string   `json:"answer"`
+    Aliases    []string `json:"aliases"`
+    Category   string   `json:"category"`
+    Difficulty int      `json:"difficulty"`
+    MediaURL   string   `json:"media_url"`
+    MediaType  string   `json:"media_type"`
+}
+
+// Search returns a page of questions matching the filter and the total
+// number of matches
+func (s *QuestionService) Search(filter repository.QuestionFilter, limit int, offset int) ([]models.Question, int64, error) {
+    return s.questionRepo.Search(filter, limit, offset)
+}
+
+// Get returns one question of the bank. Questions of private packs aren't
+// part of it.
+func (s *QuestionService) Get(id string) (*models.Question, error) {
+    if _, err := uuid.Parse(id); err != nil {
+        return nil, ErrUnknownQuestion
+    }
+
+    question, err := s.questionRepo.GetByID(id)
+    if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && question.PackID != nil) {
+        return nil, ErrUnknownQuestion
+    }
+    return question, err
+}
+
+// Create validates and adds a question
+func (s *QuestionService) Create(input QuestionInput) (*models.Question, error) {
+    question := &models.Question{}
+    if err := applyQuestionInput(question, input); err != nil {
+        return nil, err
+    }
+    if err := s.checkDuplicate(question); err != nil {
+        return nil, err
+    }
+
+    if err := s.questionRepo.CreateQuestion(question); err != nil {
+        return nil, err
+    }
+    return question, nil
+}
+
+// Update replaces a question's fields
+func (s *QuestionService) Update(id string, input QuestionInput) (*models.Question, error) {
+    question, err := s.Get(id)
+    if err != nil {
+        return nil, err
+    }
+    if err := applyQuestionInput(question, input); err != nil {
+        return nil, err
+    }
+    if err := s.checkDuplicate(question); err != nil {
+        return nil, err
+    }
+
+    if err := s.questionRepo.UpdateQuestion(question); err != nil {
+        return nil, err
+    }
+    return question, nil
+}
+
+// Delete removes a question. Archived games keep their copy of it.
+func (s *QuestionService) Delete(id string) error {
+    if _, err := uuid.Parse(id); err != nil {
+        return ErrUnknownQuestion
+    }
+
+    found, err := s.questionRepo.DeleteQuestion(id)
+    if err != nil {
+        return err
+    }
+    if !found {
+        return ErrUnknownQuestion
+    }
+    return nil
+}
+
+// Categories returns every category with its number of questions
+func (s *QuestionService) Categories() ([]repository.CategoryCount, error) {
+    return s.questionRepo.Categories()
+}
+
+// checkDuplicate rejects a question whose content matches another question
+func (s *QuestionService) checkDuplicate(question *models.Question) error {
+    existing, err := s.questionRepo.FindByContent(question.Content)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+    if existing.ID != question.ID {
+        slog.Debug("Rejected duplicate question", "question_id", existing.ID)
+        return ErrDuplicateQuestion
+    }
+    return nil
+}
+
+// applyQuestionInput validates an editor's input and copies it onto a question
+func applyQuestionInput(question *models.Question, input QuestionInput) error {
+    content := strings.TrimSpace(input.Content)
+    answer := strings.TrimSpace(input.Answer)
+    mediaURL := strings.TrimSpace(input.MediaURL)
+    mediaType := strings.ToLower(strings.TrimSpace(input.MediaType))
+
+    switch {
+    case content == "":
+        return NewError(protocol.CodeInvalidMessage, "content is required")
+    case len(content) > maxContentLength:
+        return NewError(protocol.CodeInvalidMessage, "content must be at most 1000 characters")
+    case answer == "":
+        return NewError(protocol.CodeInvalidMessage, "answer is required")
+    case len(answer) > maxAnswerLength:
+        return NewError(protocol.CodeInvalidMessage, "answer must be at most 200 characters")
+    case input.Difficulty < 0 || input.Difficulty > maxDifficulty:
+        return NewError(protocol.CodeInvalidMessage, "difficulty must be 1 (easy) to 3 (hard), or 0 for unrated")
+    }
+
+    if mediaURL != "" {
+        parsed, err := url.Parse(mediaURL)
+        if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+            return NewError(protocol.CodeInvalidMessage, "media_url must be an http(s) URL")
+        }
+        if mediaType == "" {
+            mediaType = "image"
+        }
+    }
+    if mediaType != "" {
+        if !mediaTypes[mediaType] {
+            return NewError(protocol.CodeInvalidMessage, "media_type must be image, audio or video")
+        }
+        if mediaURL == "" {
+            return NewError(protocol.CodeInvalidMessage, "media_type needs a media_url")
+        }
+    }
+
+    // Drop blank aliases, repeats and copies of the answer
+    seen := map[string]bool{strings.ToLower(answer): true}
+    var aliases models.StringList
+    for _, alias := range input.Aliases {
+        alias = strings.TrimSpace(alias)
+        if alias == "" || seen[strings.ToLower(alias)] {
+            continue
+        }
+        if len(alias) > maxAnswerLength {
+            return NewError(protocol.CodeInvalidMessage, "aliases must be at most 200 characters")
+        }
+        seen[strings.ToLower(alias)] = true
+        aliases = append(aliases, alias)
+    }
+
+    question.Content = content
+    question.Answer = answer
+    question.Aliases = aliases
+    question.Category = strings.TrimSpace(input.Category)
+    question.Difficulty = input.Difficulty
+    question.MediaURL = mediaURL
+    question.MediaType = mediaType
+    return nil
+}
@@ -0,0 +1,68 @@
+// internal/service/question_service_test.go
+
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rohan03122001/quizzing/internal/models"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+)
+
+func TestApplyQuestionInput(t *testing.T) {
+    var question models.Question
+    err := applyQuestionInput(&question, QuestionInput{
+        Content:    "  Who composed 'Jai Ho'? ",
+        Answer:     "A. R. Rahman",
+        Aliases:    []string{"AR Rahman", " ", "a. r. rahman", "ar rahman", "Rahman"},
+        Category:   " Music ",
+        Difficulty: 2,
+        MediaURL:   "https://example.com/rahman.jpg",
+    })
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+
+    if question.Content != "Who composed 'Jai Ho'?" || question.Category != "Music" {
+        t.Errorf("got content %q category %q, want them trimmed", question.Content, question.Category)
+    }
+    if want := (models.StringList{"AR Rahman", "Rahman"}); !reflect.DeepEqual(question.Aliases, want) {
+        t.Errorf("got aliases %v, want %v", question.Aliases, want)
+    }
+    if question.MediaType != "image" {
+        t.Errorf("got media type %q, want image by default", question.MediaType)
+    }
+}
+
+func TestApplyQuestionInputRejects(t *testing.T) {
+    cases := map[string]QuestionInput{
+        "empty content":    {Answer: "Paris"},
+        "empty answer":     {Content: "What is the capital of France?", Answer: "   "},
+        "difficulty":       {Content: "What is the capital of France?", Answer: "Paris", Difficulty: 4},
+        "media url":        {Content: "What is the capital of France?", Answer: "Paris", MediaURL: "ftp://example.com/a.png"},
+        "media type":       {Content: "What is the capital of France?", Answer: "Paris", MediaURL: "https://example.com/a.png", MediaType: "gif"},
+        "type without url": {Content: "What is the capital of France?", Answer: "Paris", MediaType: "audio"},
+    }
+
+    for name, input := range cases {
+        var question models.Question
+        err := applyQuestionInput(&question, input)
+        if ErrorCodeOf(err) != protocol.CodeInvalidMessage {
+            t.Errorf("%s: got %v, want INVALID_MESSAGE", name, err)
+        }
+    }
+}
+
+func TestAnswerMatchesAliases(t *testing.T) {
+    question := &models.Question{Answer: "Mahendra Singh Dhoni", Aliases: models.StringList{"MS Dhoni", "Dhoni"}}
+
+    for _, answer := range []string{"mahendra singh dhoni", "M.S. Dhoni", " dhoni "} {
+        if !answerMatches(answer, question) {
+            t.Errorf("%q should match", answer)
+        }
+    }
+    if answerMatches("Kohli", question) {
+        t.Error("Kohli should not match")
+    }
+}
@@ -3,28 +3,51 @@
 package service
 
 import (
+	"context"
 	"errors"
-	"log"
+	"log/slog"
 	"math/rand"
+	"sync/atomic"
 	"time"
 
 	"github.com/rohan03122001/quizzing/internal/models"
 	"github.com/rohan03122001/quizzing/internal/repository"
+	"github.com/rohan03122001/quizzing/internal/tracing"
 	"github.com/rohan03122001/quizzing/internal/websocket"
 )
 
 type RoomService struct {
-    roomRepo *repository.RoomRepository
+    roomRepo repository.RoomStore
     hub      *websocket.Hub  // For real-time updates
+    defaults models.GameSettings // Configured settings for new rooms
+    draining atomic.Bool         // Set on shutdown; no new rooms, players or games
 }
 
-func NewRoomService(roomRepo *repository.RoomRepository, hub *websocket.Hub) *RoomService {
+func NewRoomService(roomRepo repository.RoomStore, hub *websocket.Hub) *RoomService {
     return &RoomService{
         roomRepo: roomRepo,
         hub:      hub,
     }
 }
 
+// SetDefaults sets the settings new rooms get when the host doesn't pick
+// them. Unset fields keep the built-in defaults.
+func (s *RoomService) SetDefaults(defaults models.GameSettings) {
+    s.defaults = defaults
+}
+
+// Drain stops new rooms, joins and games, so the server can shut down once
+// the games underway are over
+func (s *RoomService) Drain() {
+    s.draining.Store(true)
+    slog.Info("Draining: no longer accepting new rooms or players")
+}
+
+// Draining reports whether Drain has been called
+func (s *RoomService) Draining() bool {
+    return s.draining.Load()
+}
+
 // generateRoomCode creates a unique 6-character room code
 func (s *RoomService) generateRoomCode() string {
     const charset = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // Removed similar looking characters
@@ -35,13 +58,20 @@ func (s *RoomService) generateRoomCode() string {
     return string(code)
 }
 
-// CreateRoom creates a new game room
-func (s *RoomService) CreateRoom() (*models.Room, error) {
+// CreateRoom creates a new game room. Settings may be nil for defaults.
+func (s *RoomService) CreateRoom(ctx context.Context, settings *models.GameSettings) (*models.Room, error) {
+    ctx, span := tracing.Tracer().Start(ctx, "RoomService.CreateRoom")
+    defer span.End()
+
+    if s.Draining() {
+        return nil, ErrServerDraining
+    }
+
     // Generate unique room code
     var roomCode string
     for i := 0; i < 5; i++ { // Try 5 times to generate unique code
         roomCode = s.generateRoomCode()
-        existing, _ := s.roomRepo.GetByCode(roomCode)
+        existing, _ := s.roomRepo.WithContext(ctx).GetByCode(roomCode)
         if existing == nil {
             break
         }
@@ -49,91 +79,115 @@ func (s *RoomService) CreateRoom() (*models.Room, error) {
 
     // Create new room
     room := &models.Room{
-        Code:         roomCode,
-        Status:       "waiting",
-        MaxPlayers:   10,    // Default settings
-        RoundTime:    30,    // 30 seconds per round
-        MaxRounds:    5,     // 5 rounds per game
-        LastActivity: time.Now(), // Explicitly set last activity time
+        Code:             roomCode,
+        Status:           "waiting",
+        MaxPlayers:       10,    // Default settings
+        RoundTime:        30,    // 30 seconds per round
+        MaxRounds:        5,     // 5 rounds per game
+        IntermissionTime: 5,     // 5 seconds between rounds
+        LastActivity:     time.Now(), // Explicitly set last activity time
+    }
+
+    s.defaults.Apply(room)
+    if settings != nil {
+        settings.Apply(room)
     }
 
-    if err := s.roomRepo.CreateRoom(room); err != nil {
-        log.Printf("Failed to create room: %v", err)
+    if err := s.roomRepo.WithContext(ctx).CreateRoom(room); err != nil {
+        slog.Error("Failed to create room", "room", roomCode, "err", err)
         return nil, errors.New("failed to create room")
     }
 
-    log.Printf("Created room with code: %s", roomCode)
+    slog.Info("Created room", "room", roomCode)
     return room, nil
 }
 
 // internal/service/room_service.go
 
 // Update JoinRoom method
-func (s *RoomService) JoinRoom(roomCode string, playerID string) (*models.Room, error) {
-    log.Printf("Player %s trying to join room %s", playerID, roomCode)
-    
-    room, err := s.roomRepo.GetByCode(roomCode)
+func (s *RoomService) JoinRoom(ctx context.Context, roomCode string, playerID string) (*models.Room, error) {
+    ctx, span := startSpan(ctx, "RoomService.JoinRoom", roomCode, tracing.PlayerKey.String(playerID))
+    defer span.End()
+
+    if s.Draining() {
+        return nil, ErrServerDraining
+    }
+
+    room, err := s.roomRepo.WithContext(ctx).GetByCode(roomCode)
     if err != nil {
-        log.Printf("Room not found: %s", roomCode)
-        return nil, errors.New("room not found")
+        slog.Debug("Room not found", "room", roomCode, "player", playerID)
+        return nil, ErrRoomNotFound
     }
 
     if room.Status != "waiting" {
-        log.Printf("Room %s is not accepting players (status: %s)", roomCode, room.Status)
-        return nil, errors.New("game already in progress")
+        slog.Debug("Room isn't accepting players", "room", roomCode, "player", playerID, "status", room.Status)
+        return nil, ErrGameInProgress
     }
 
     // Check room capacity
     currentPlayers := s.hub.GetPlayerCount(room.Code)  // Changed from ID to Code
     if currentPlayers >= room.MaxPlayers {
-        log.Printf("Room %s is full (%d/%d players)", roomCode, currentPlayers, room.MaxPlayers)
-        return nil, errors.New("room is full")
+        slog.Debug("Room is full", "room", roomCode, "player", playerID, "players", currentPlayers, "max_players", room.MaxPlayers)
+        return nil, ErrRoomFull
+    }
+
+    // First player to join becomes the host
+    if room.HostID == "" {
+        room.HostID = playerID
+        if err := s.roomRepo.WithContext(ctx).UpdateHost(room.ID.String(), playerID); err != nil {
+            slog.Error("Error setting room host", "room", roomCode, "player", playerID, "err", err)
+        }
     }
 
     // Update last activity
-    if err := s.roomRepo.UpdateLastActivity(room.ID.String()); err != nil {
-        log.Printf("Error updating room activity: %v", err)
+    if err := s.roomRepo.WithContext(ctx).UpdateLastActivity(room.ID.String()); err != nil {
+        slog.Error("Error updating room activity", "room", roomCode, "err", err)
     }
 
-    log.Printf("Player %s successfully joined room %s", playerID, roomCode)
+    slog.Info("Player joined room", "room", roomCode, "player", playerID)
     return room, nil
 }
 
 // StartGame updated to use room code
-func (s *RoomService) StartGame(roomCode string) error {
-    room, err := s.roomRepo.GetByCode(roomCode)
+func (s *RoomService) StartGame(ctx context.Context, roomCode string) error {
+    ctx, span := startSpan(ctx, "RoomService.StartGame", roomCode)
+    defer span.End()
+
+    if s.Draining() {
+        return ErrServerDraining
+    }
+
+    room, err := s.roomRepo.WithContext(ctx).GetByCode(roomCode)
     if err != nil {
-        return errors.New("room not found")
+        return ErrRoomNotFound
     }
 
     if room.Status != "waiting" {
-        return errors.New("game already started")
+        return ErrGameAlreadyStarted
     }
 
     currentPlayers := s.hub.GetPlayerCount(room.Code)  // Changed from ID to Code
-    log.Printf("Current players in room %s: %d", roomCode, currentPlayers)
     
     if currentPlayers < 2 {
-        return errors.New("need at least 2 players to start")
+        return ErrNotEnoughPlayers
     }
 
-    err = s.roomRepo.UpdateStatus(room.ID.String(), "playing")
+    err = s.roomRepo.WithContext(ctx).UpdateStatus(room.ID.String(), "playing")
     if err != nil {
         return errors.New("failed to start game")
     }
 
     // Update last activity
-    if err := s.roomRepo.UpdateLastActivity(room.ID.String()); err != nil {
-        log.Printf("Error updating room activity: %v", err)
+    if err := s.roomRepo.WithContext(ctx).UpdateLastActivity(room.ID.String()); err != nil {
+        slog.Error("Error updating room activity", "room", roomCode, "err", err)
     }
 
-    log.Printf("Started game in room %s with %d players", roomCode, currentPlayers)
+    slog.Info("Started game", "room", roomCode, "players", currentPlayers)
     return nil
 }
 
 // GetActiveRooms returns all rooms waiting for players
 func (s *RoomService) GetActiveRooms() ([]models.Room, error) {
-    log.Println("Fetching active rooms")
     return s.roomRepo.GetActive()
 }
 
@@ -141,10 +195,10 @@ func (s *RoomService) GetActiveRooms() ([]models.Room, error) {
 func (s *RoomService) EndGame(roomCode string) error {
     room, err := s.roomRepo.GetByCode(roomCode)
     if err != nil {
-        return errors.New("room not found")
+        return ErrRoomNotFound
     }
 
-    log.Printf("Ending game in room %s", roomCode)
+    slog.Info("Ending game", "room", roomCode)
     return s.roomRepo.EndGame(room.ID.String())
 }
 
@@ -156,22 +210,51 @@ func (s *RoomService) GetRoom(roomCode string) (*models.Room, error) {
 func (s *RoomService) ValidateRoom(roomCode string) (*models.Room, error) {
     room, err := s.roomRepo.GetByCode(roomCode)
     if err != nil {
-        return nil, errors.New("room not found")
+        return nil, ErrRoomNotFound
     }
 
     if room.Status != "waiting" {
-        return nil, errors.New("game already in progress")
+        return nil, ErrGameInProgress
     }
 
     // CHECKING CAPACITY
     currentPlayers := s.hub.GetPlayerCount(room.Code)
     if currentPlayers >= room.MaxPlayers {
-        return nil, errors.New("room is full")
+        return nil, ErrRoomFull
     }
 
     return room, nil
 }
 
+// RequireHost checks that playerID is the room's host. If the host is no
+// longer connected, the requesting player takes over as host.
+func (s *RoomService) RequireHost(ctx context.Context, roomCode string, playerID string) error {
+    ctx, span := startSpan(ctx, "RoomService.RequireHost", roomCode, tracing.PlayerKey.String(playerID))
+    defer span.End()
+
+    room, err := s.roomRepo.WithContext(ctx).GetByCode(roomCode)
+    if err != nil {
+        return ErrRoomNotFound
+    }
+
+    if room.HostID == playerID {
+        return nil
+    }
+
+    if room.HostID != "" && s.hub.IsPlayerConnected(room.Code, room.HostID) {
+        slog.Debug("Player isn't the host", "room", roomCode, "player", playerID)
+        return ErrNotHost
+    }
+
+    if err := s.roomRepo.WithContext(ctx).UpdateHost(room.ID.String(), playerID); err != nil {
+        slog.Error("Error transferring host", "room", roomCode, "player", playerID, "err", err)
+        return err
+    }
+
+    slog.Info("Player is now host", "room", roomCode, "player", playerID)
+    return nil
+}
+
 func (s *RoomService) GetPlayerCount(roomCode string) int {
     return s.hub.GetPlayerCount(roomCode)
 }
@@ -180,12 +263,12 @@ func (s *RoomService) GetPlayerCount(roomCode string) int {
 func (s *RoomService) UpdateRoomActivity(roomCode string) error {
     room, err := s.roomRepo.GetByCode(roomCode)
     if err != nil {
-        log.Printf("Error finding room %s to update activity: %v", roomCode, err)
+        slog.Debug("Room not found to update activity", "room", roomCode, "err", err)
         return err
     }
     
     if err := s.roomRepo.UpdateLastActivity(room.ID.String()); err != nil {
-        log.Printf("Error updating room %s activity: %v", roomCode, err)
+        slog.Error("Error updating room activity", "room", roomCode, "err", err)
         return err
     }
     
@@ -0,0 +1,28 @@
+// internal/service/tracing.go
+
+package service
+
+import (
+	"context"
+
+	"github.com/rohan03122001/quizzing/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSpan starts a span for a service call about a room. Pass the player
+// when there is one.
+func startSpan(ctx context.Context, name string, roomCode string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+    attrs = append(attrs, tracing.RoomKey.String(roomCode))
+    return tracing.Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// startTimerSpan starts the trace for work a timer kicks off rather than a
+// player, such as a round running out of time
+func startTimerSpan(name string, roomCode string) (context.Context, trace.Span) {
+    return tracing.Tracer().Start(context.Background(), name,
+        trace.WithNewRoot(),
+        trace.WithSpanKind(trace.SpanKindInternal),
+        trace.WithAttributes(tracing.RoomKey.String(roomCode)),
+    )
+}
@@ -0,0 +1,83 @@
+// internal/service/tracing_test.go
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rohan03122001/quizzing/internal/repository"
+	"github.com/rohan03122001/quizzing/internal/tracing"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestAnswerTrace(t *testing.T) {
+    recorder := tracetest.NewSpanRecorder()
+    otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+    db, err := repository.NewDatabase(&repository.DBConfig{Driver: repository.DriverSQLite, Path: ":memory:"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { db.Close() })
+    if _, err := db.MigrateUp(); err != nil {
+        t.Fatal(err)
+    }
+    if err := db.SetTracing(tracing.Tracer()); err != nil {
+        t.Fatal(err)
+    }
+
+    g := newTestGame(t, testStores{
+        rooms:        repository.NewRoomRepository(db),
+        questions:    repository.NewQuestionRepository(db),
+        rounds:       repository.NewGameRoundRepository(db),
+        history:      repository.NewHistoryRepository(db),
+        leaderboards: repository.NewLeaderboardRepository(db),
+    }, capitals...)
+    room := g.createRoom(t, nil)
+    g.join(t, room.Code, "alice", false)
+    g.join(t, room.Code, "bob", false)
+    question := g.start(t, room.Code)
+    defer g.games.stopRoundTimer(room.Code)
+
+    // Stands in for the WebSocket message's span
+    ctx, root := tracing.Tracer().Start(context.Background(), "ws submit_answer")
+    if _, err := g.games.ProcessAnswer(ctx, room.Code, "alice", question.Answer); err != nil {
+        t.Fatal(err)
+    }
+    root.End()
+
+    // Queries end before the call that made them, so find the call first
+    spans := recorder.Ended()
+    var answer sdktrace.ReadOnlySpan
+    for _, span := range spans {
+        if span.Name() == "GameService.ProcessAnswer" && span.SpanContext().TraceID() == root.SpanContext().TraceID() {
+            answer = span
+        }
+    }
+    if answer == nil {
+        t.Fatal("no GameService.ProcessAnswer span in the trace")
+    }
+    if answer.Parent().SpanID() != root.SpanContext().SpanID() {
+        t.Error("the ProcessAnswer span isn't a child of the message's span")
+    }
+
+    attrs := map[string]string{}
+    for _, attr := range answer.Attributes() {
+        attrs[string(attr.Key)] = attr.Value.Emit()
+    }
+    if attrs[string(tracing.RoomKey)] != room.Code || attrs[string(tracing.PlayerKey)] != "alice" {
+        t.Errorf("got attributes %v, want the room and player", attrs)
+    }
+    queries := 0
+    for _, span := range spans {
+        if span.Parent().SpanID() == answer.SpanContext().SpanID() {
+            queries++
+        }
+    }
+    if queries == 0 {
+        t.Error("no database spans under ProcessAnswer")
+    }
+}
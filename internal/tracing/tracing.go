@@ -0,0 +1,93 @@
+// internal/tracing/tracing.go
+
+// Package tracing sets up OpenTelemetry tracing. Each game action is one
+// trace: the WebSocket message, its dispatch, the service calls and the
+// database queries they make. Spans about a game carry the room code and
+// player ID under the same names as the logs, prefixed with "quiz.".
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporters
+const (
+    ExporterNone   = "none"   // No tracing, the default
+    ExporterStdout = "stdout" // Spans as JSON on stdout, for development
+    ExporterOTLP   = "otlp"   // OTLP over HTTP to a collector
+)
+
+// ServiceName is the service.name of the server's spans
+const ServiceName = "quiz-api"
+
+// Span attributes shared across the server
+const (
+    RoomKey    = attribute.Key("quiz.room")
+    PlayerKey  = attribute.Key("quiz.player")
+    EventKey   = attribute.Key("quiz.event")
+    ErrCodeKey = attribute.Key("quiz.error_code")
+)
+
+// Tracer returns the server's tracer. Until Setup installs a provider it
+// records nothing.
+func Tracer() trace.Tracer {
+    return otel.Tracer("github.com/rohan03122001/quizzing")
+}
+
+// Options configures Setup
+type Options struct {
+    Exporter    string  // none, stdout or otlp
+    Endpoint    string  // OTLP endpoint URL; empty uses OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318
+    SampleRatio float64 // Share of traces kept, 0 to 1
+    Stdout      io.Writer
+}
+
+// Setup installs the global tracer provider and W3C trace context
+// propagation. The returned function flushes buffered spans; call it on
+// shutdown. With the none exporter nothing is installed and tracing stays
+// a no-op.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+    var exporter sdktrace.SpanExporter
+    var err error
+    switch opts.Exporter {
+    case ExporterNone, "":
+        return func(context.Context) error { return nil }, nil
+    case ExporterStdout:
+        exporter, err = stdouttrace.New(stdouttrace.WithWriter(opts.Stdout))
+    case ExporterOTLP:
+        var options []otlptracehttp.Option
+        if opts.Endpoint != "" {
+            options = append(options, otlptracehttp.WithEndpointURL(opts.Endpoint))
+        }
+        exporter, err = otlptracehttp.New(ctx, options...)
+    default:
+        return nil, fmt.Errorf("unknown trace exporter %q, expected none, stdout or otlp", opts.Exporter)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("creating %s trace exporter: %w", opts.Exporter, err)
+    }
+
+    provider := sdktrace.NewTracerProvider(
+        sdktrace.WithBatcher(exporter),
+        sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+        sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(ServiceName))),
+    )
+    otel.SetTracerProvider(provider)
+    otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+        propagation.TraceContext{},
+        propagation.Baggage{},
+    ))
+    return provider.Shutdown, nil
+}
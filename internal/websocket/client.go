@@ -3,10 +3,12 @@
 package websocket
 
 import (
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/rohan03122001/quizzing/internal/protocol"
+	"github.com/rohan03122001/quizzing/internal/ratelimit"
 )
 
 const (
@@ -23,9 +25,11 @@ const (
     maxMessageSize = 512
 )
 
-// Client represents a connected WebSocket client
+// Client represents a connected client. Most clients have a WebSocket
+// connection; event stream clients (SSE) have none and their events are
+// read from Events() instead.
 type Client struct {
-    // The websocket connection
+    // The websocket connection, nil for event stream clients
     conn *websocket.Conn
 
     // The hub instance
@@ -43,6 +47,26 @@ type Client struct {
     // Client's username
     Username string
 
+    // Account the client signed in with, empty for guests. When set, ID is
+    // the account ID.
+    AccountID string
+
+    // Protocol version negotiated on connect
+    ProtocolVersion int
+
+    // Wire encoding negotiated on connect
+    encoding Encoding
+
+    // Limits on the client's messages, nil when unlimited
+    Limits *ratelimit.Conn
+
+    // Where the hub has this client registered, and whether it has closed
+    // send, guarded by the hub's mutex
+    registered bool
+    closed     bool
+    hubRoom    string
+    hubID      string
+
     // Message handler function
     messageHandler func(*Client, []byte) error
 }
@@ -55,9 +79,49 @@ func NewClient(hub *Hub, conn *websocket.Conn, roomID, clientID string) *Client
         send:   make(chan *GameEvent, 256),
         RoomID: roomID,
         ID:     clientID,
+        ProtocolVersion: protocol.Version,
+        encoding: JSONEncoding,
     }
 }
 
+// NewEventStreamClient creates a client without a WebSocket connection.
+// The transport serving it must drain Events() and call Disconnect when done.
+func NewEventStreamClient(hub *Hub, clientID string) *Client {
+    return NewClient(hub, nil, "", clientID)
+}
+
+// Events returns the channel of outbound events. It is closed when the hub
+// drops the client.
+func (c *Client) Events() <-chan *GameEvent {
+    return c.send
+}
+
+// Log returns a logger tagged with the client's room and player ID
+func (c *Client) Log() *slog.Logger {
+    return slog.With("room", c.RoomID, "player", c.ID)
+}
+
+// Disconnect notifies the client's room and removes it from the hub
+func (c *Client) Disconnect() {
+    if c.RoomID != "" {
+        // Only broadcast disconnect event if the client was in a room
+        c.hub.BroadcastToRoom(c.RoomID, GameEvent{
+            Type: protocol.EventPlayerDisconnected,
+            Data: protocol.PlayerData{
+                PlayerID: c.ID,
+                Username: c.Username,
+            },
+        })
+    }
+
+    c.hub.Unregister <- c
+}
+
+// SetEncoding sets the wire encoding used for this client's messages
+func (c *Client) SetEncoding(encoding Encoding) {
+    c.encoding = encoding
+}
+
 // SetMessageHandler sets the function to handle incoming messages
 func (c *Client) SetMessageHandler(handler func(*Client, []byte) error) {
     c.messageHandler = handler
@@ -67,21 +131,9 @@ func (c *Client) SetMessageHandler(handler func(*Client, []byte) error) {
 func (c *Client) ReadPump() {
     defer func() {
         // Notify other clients in the room that this client disconnected
-        if c.RoomID != "" {
-            log.Printf("Client %s disconnected from room %s", c.ID, c.RoomID)
-            // Only broadcast disconnect event if the client was in a room
-            c.hub.BroadcastToRoom(c.RoomID, GameEvent{
-                Type: "player_disconnected",
-                Data: map[string]interface{}{
-                    "player_id": c.ID,
-                    "username": c.Username,
-                },
-            })
-        }
-        
-        c.hub.Unregister <- c
+        c.Disconnect()
         c.conn.Close()
-        log.Printf("Client %s disconnected", c.ID)
+        c.Log().Info("Client disconnected")
     }()
 
     c.conn.SetReadLimit(maxMessageSize)
@@ -93,22 +145,41 @@ func (c *Client) ReadPump() {
 
     for {
         // Read message
-        _, message, err := c.conn.ReadMessage()
+        messageType, message, err := c.conn.ReadMessage()
         if err != nil {
             if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-                log.Printf("Unexpected close error: %v", err)
+                c.Log().Warn("Unexpected close", "err", err)
             }
             break
         }
 
+        // Binary frames use the negotiated encoding; text frames are always JSON
+        if messageType == websocket.BinaryMessage {
+            message, err = c.encoding.ToJSON(message)
+            if err != nil {
+                c.Log().Warn("Error decoding message", "encoding", c.encoding.Name(), "err", err)
+                c.hub.SendToClient(c, GameEvent{
+                    Type: protocol.EventError,
+                    Data: protocol.ErrorData{
+                        Code:    protocol.CodeInvalidMessage,
+                        Message: "Invalid message format",
+                    },
+                })
+                continue
+            }
+        }
+
         // Handle message using custom handler if set
         if c.messageHandler != nil {
             if err := c.messageHandler(c, message); err != nil {
-                log.Printf("Error handling message from client %s: %v", c.ID, err)
+                c.Log().Error("Error handling message", "err", err)
                 // Send error back to client
                 c.hub.SendToClient(c, GameEvent{
-                    Type:  "error",
-                    Error: err.Error(),
+                    Type: protocol.EventError,
+                    Data: protocol.ErrorData{
+                        Code:    protocol.CodeInternal,
+                        Message: err.Error(),
+                    },
                 })
             }
         }
@@ -133,17 +204,21 @@ func (c *Client) WritePump() {
                 return
             }
 
-            // Write the event as JSON
-            err := c.conn.WriteJSON(event)
+            // Write the event in the client's encoding
+            message, err := c.encoding.Marshal(event)
             if err != nil {
-                log.Printf("Error writing message to client %s: %v", c.ID, err)
+                c.Log().Error("Error encoding event", "event", event.Type, "err", err)
+                continue
+            }
+            if err := c.conn.WriteMessage(c.encoding.MessageType(), message); err != nil {
+                c.Log().Debug("Error writing message", "event", event.Type, "err", err)
                 return
             }
 
         case <-ticker.C:
             c.conn.SetWriteDeadline(time.Now().Add(writeWait))
             if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
-                log.Printf("Error sending ping to client %s: %v", c.ID, err)
+                c.Log().Debug("Error sending ping", "err", err)
                 return
             }
         }
@@ -153,5 +228,31 @@ func (c *Client) WritePump() {
 // Close closes the client connection
 func (c *Client) Close() {
     c.hub.Unregister <- c
+    if c.conn != nil {
+        c.conn.Close()
+    }
+}
+
+// Kick drops a misbehaving client. A WebSocket connection is closed with a
+// policy violation close frame giving the reason, and an event stream ends.
+func (c *Client) Kick(reason string) {
+    if c.conn == nil {
+        c.hub.Unregister <- c
+        return
+    }
+    c.closeWith(websocket.ClosePolicyViolation, reason)
+}
+
+// closeGoingAway tells the peer the server is going away and closes the
+// connection
+func (c *Client) closeGoingAway() {
+    c.closeWith(websocket.CloseGoingAway, "server shutting down")
+}
+
+// closeWith sends a close frame and closes the connection. The read pump
+// then unregisters the client.
+func (c *Client) closeWith(code int, reason string) {
+    message := websocket.FormatCloseMessage(code, reason)
+    c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(writeWait))
     c.conn.Close()
-}
\ No newline at end of file
+}
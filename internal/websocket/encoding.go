@@ -0,0 +1,125 @@
+// internal/websocket/encoding.go
+
+package websocket
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/gorilla/websocket"
+	"github.com/ugorji/go/codec"
+)
+
+// WebSocket subprotocols clients can request to pick a wire encoding
+const (
+    SubprotocolJSON    = "quiz.json"
+    SubprotocolMsgpack = "quiz.msgpack"
+    SubprotocolCBOR    = "quiz.cbor"
+)
+
+// Encoding serializes events for one client's connection
+type Encoding interface {
+    // Name identifies the encoding in logs
+    Name() string
+
+    // MessageType is the WebSocket frame type used for outbound events
+    MessageType() int
+
+    // Marshal encodes an outbound event
+    Marshal(event *GameEvent) ([]byte, error)
+
+    // ToJSON converts an inbound binary frame into JSON for the message handler
+    ToJSON(data []byte) ([]byte, error)
+}
+
+// JSONEncoding is the default encoding
+var JSONEncoding Encoding = jsonEncoding{}
+
+var encodings = map[string]Encoding{
+    SubprotocolJSON:    JSONEncoding,
+    SubprotocolMsgpack: newCodecEncoding("msgpack", msgpackHandle()),
+    SubprotocolCBOR:    newCodecEncoding("cbor", cborHandle()),
+}
+
+// Subprotocols lists the subprotocols the server accepts. The client's
+// order of preference wins when it offers more than one.
+func Subprotocols() []string {
+    return []string{SubprotocolMsgpack, SubprotocolCBOR, SubprotocolJSON}
+}
+
+// EncodingFor returns the encoding for a negotiated subprotocol, falling
+// back to JSON when none was negotiated.
+func EncodingFor(subprotocol string) Encoding {
+    if encoding, ok := encodings[subprotocol]; ok {
+        return encoding
+    }
+    return JSONEncoding
+}
+
+type jsonEncoding struct{}
+
+func (jsonEncoding) Name() string {
+    return "json"
+}
+
+func (jsonEncoding) MessageType() int {
+    return websocket.TextMessage
+}
+
+func (jsonEncoding) Marshal(event *GameEvent) ([]byte, error) {
+    return json.Marshal(event)
+}
+
+func (jsonEncoding) ToJSON(data []byte) ([]byte, error) {
+    return data, nil
+}
+
+// codecEncoding handles the binary encodings. Struct fields keep their
+// json tag names so payloads have the same shape as in JSON.
+type codecEncoding struct {
+    name   string
+    handle codec.Handle
+}
+
+func newCodecEncoding(name string, handle codec.Handle) *codecEncoding {
+    return &codecEncoding{name: name, handle: handle}
+}
+
+func (e *codecEncoding) Name() string {
+    return e.name
+}
+
+func (e *codecEncoding) MessageType() int {
+    return websocket.BinaryMessage
+}
+
+func (e *codecEncoding) Marshal(event *GameEvent) ([]byte, error) {
+    var out []byte
+    err := codec.NewEncoderBytes(&out, e.handle).Encode(event)
+    return out, err
+}
+
+func (e *codecEncoding) ToJSON(data []byte) ([]byte, error) {
+    var message map[string]interface{}
+    if err := codec.NewDecoderBytes(data, e.handle).Decode(&message); err != nil {
+        return nil, err
+    }
+    return json.Marshal(message)
+}
+
+var stringMapType = reflect.TypeOf(map[string]interface{}(nil))
+
+func msgpackHandle() *codec.MsgpackHandle {
+    h := &codec.MsgpackHandle{}
+    h.WriteExt = true // Use the current spec (str8, bin and timestamp types)
+    h.RawToString = true
+    h.MapType = stringMapType
+    return h
+}
+
+func cborHandle() *codec.CborHandle {
+    h := &codec.CborHandle{}
+    h.TimeRFC3339 = true
+    h.MapType = stringMapType
+    return h
+}
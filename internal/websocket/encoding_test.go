@@ -0,0 +1,65 @@
+// internal/websocket/encoding_test.go
+
+package websocket
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/rohan03122001/quizzing/internal/protocol"
+)
+
+func TestBinaryEncodingsKeepJSONShape(t *testing.T) {
+    event := &GameEvent{
+        Type:   protocol.EventTimerUpdate,
+        RoomID: "ABC123",
+        Data: protocol.TimerUpdateData{
+            Remaining: 12,
+            Warning:   false,
+        },
+    }
+
+    want := decodeJSON(t, mustMarshal(t, JSONEncoding, event))
+
+    for _, subprotocol := range []string{SubprotocolMsgpack, SubprotocolCBOR} {
+        encoding := EncodingFor(subprotocol)
+        encoded := mustMarshal(t, encoding, event)
+
+        asJSON, err := encoding.ToJSON(encoded)
+        if err != nil {
+            t.Fatalf("%s: converting to JSON: %v", encoding.Name(), err)
+        }
+
+        if got := decodeJSON(t, asJSON); !reflect.DeepEqual(got, want) {
+            t.Errorf("%s: got %v, want %v", encoding.Name(), got, want)
+        }
+    }
+}
+
+func TestEncodingForDefaultsToJSON(t *testing.T) {
+    if got := EncodingFor(""); got != JSONEncoding {
+        t.Errorf("EncodingFor(\"\") = %s, want json", got.Name())
+    }
+    if got := EncodingFor("unknown"); got != JSONEncoding {
+        t.Errorf("EncodingFor(\"unknown\") = %s, want json", got.Name())
+    }
+}
+
+func mustMarshal(t *testing.T, encoding Encoding, event *GameEvent) []byte {
+    t.Helper()
+    data, err := encoding.Marshal(event)
+    if err != nil {
+        t.Fatalf("%s: marshal: %v", encoding.Name(), err)
+    }
+    return data
+}
+
+func decodeJSON(t *testing.T, data []byte) map[string]interface{} {
+    t.Helper()
+    var out map[string]interface{}
+    if err := json.Unmarshal(data, &out); err != nil {
+        t.Fatalf("decoding %s: %v", data, err)
+    }
+    return out
+}
@@ -3,9 +3,12 @@
 package websocket
 
 import (
-	"log"
+	"log/slog"
 	"sync"
 	"time"
+
+	"github.com/rohan03122001/quizzing/internal/metrics"
+	"github.com/rohan03122001/quizzing/internal/protocol"
 )
 
 // RoomService interface represents minimal room service methods needed by hub
@@ -40,22 +43,36 @@ type Hub struct {
     // Broadcast messages
     Broadcast chan *GameEvent
 
+    // Events for every client, from BroadcastToAll
+    broadcastAll chan broadcastAllRequest
+
     // Room service for updating room activity
     roomService RoomService
+
+    // Connection and broadcast metrics, nil when not collected
+    metrics *metrics.Metrics
+}
+
+// broadcastAllRequest is an event for every client, and a channel closed
+// once it's queued for them
+type broadcastAllRequest struct {
+    event *GameEvent
+    done  chan struct{}
 }
 
 // GameEvent represents a game-related message
 type GameEvent struct {
-    Type    string      `json:"type"`
-    RoomID  string      `json:"room_id,omitempty"`
-    Data    interface{} `json:"data,omitempty"`
-    Error   string      `json:"error,omitempty"`
+    Type      string      `json:"type"`
+    RoomID    string      `json:"room_id,omitempty"`
+    RequestID string      `json:"request_id,omitempty"` // Echoed from the client message this replies to
+    Data      interface{} `json:"data,omitempty"`        // One of the protocol payload types
 }
 
 // Update NewHub to initialize the new fields
 func NewHub() *Hub {
     return &Hub{
         Broadcast:             make(chan *GameEvent, 256),
+        broadcastAll:          make(chan broadcastAllRequest),
         Register:              make(chan *Client),
         Unregister:            make(chan *Client),
         rooms:                 make(map[string]map[string]*Client),
@@ -71,6 +88,18 @@ func (h *Hub) SetRoomService(service RoomService) {
 	h.roomService = service
 }
 
+// SetReconnectWindow sets how long disconnected players are remembered so
+// they can reconnect. Call it before Run.
+func (h *Hub) SetReconnectWindow(window time.Duration) {
+    h.disconnectMemoryDuration = window
+}
+
+// SetMetrics sets where connection and broadcast metrics are recorded.
+// Call it before Run.
+func (h *Hub) SetMetrics(m *metrics.Metrics) {
+    h.metrics = m
+}
+
 // Update Run method to start the cleanup routine
 func (h *Hub) Run() {
     // Start cleanup routine for disconnected clients
@@ -86,6 +115,10 @@ func (h *Hub) Run() {
 
         case event := <-h.Broadcast:
             h.handleBroadcast(event)
+
+        case req := <-h.broadcastAll:
+            h.handleBroadcastAll(req.event)
+            close(req.done)
         }
     }
 }
@@ -96,6 +129,18 @@ func (h *Hub) handleRegister(client *Client) {
     h.mu.Lock()
     defer h.mu.Unlock()
 
+    // A client the hub dropped is done, even if its transport hasn't
+    // noticed yet
+    if client.closed {
+        return
+    }
+
+    // Drop any previous registration, e.g. the lobby entry a client gets on
+    // connect before it joins a room, or its ID before a reconnect
+    if client.registered && (client.hubRoom != client.RoomID || client.hubID != client.ID) {
+        h.removeClient(client.hubRoom, client.hubID, client)
+    }
+
     // Initialize room if it doesn't exist
     if _, exists := h.rooms[client.RoomID]; !exists {
         h.rooms[client.RoomID] = make(map[string]*Client)
@@ -103,27 +148,29 @@ func (h *Hub) handleRegister(client *Client) {
 
     // Add client to room
     h.rooms[client.RoomID][client.ID] = client
+    if !client.registered {
+        h.metrics.ClientConnected()
+    }
+    client.registered = true
+    client.hubRoom = client.RoomID
+    client.hubID = client.ID
     
-    playerCount := len(h.rooms[client.RoomID])
-    log.Printf("Client %s registered in room %s (total players: %d)", 
-        client.ID, client.RoomID, playerCount)
+    slog.Info("Client registered", "room", client.RoomID, "player", client.ID, "players", len(h.rooms[client.RoomID]))
 }
 
-func (h *Hub) GetPlayersInRoom(roomCode string) []map[string]string {
+func (h *Hub) GetPlayersInRoom(roomCode string) []protocol.Player {
     h.mu.RLock()
     defer h.mu.RUnlock()
 
-    var players []map[string]string
+    players := []protocol.Player{}
     if room, exists := h.rooms[roomCode]; exists {
         for _, client := range room {
-            players = append(players, map[string]string{
-                "id": client.ID,
-                "username": client.Username,
+            players = append(players, protocol.Player{
+                ID:         client.ID,
+                Username:   client.Username,
+                Registered: client.AccountID != "",
             })
         }
-        log.Printf("Found %d players in room %s", len(players), roomCode)
-    } else {
-        log.Printf("Room %s not found in hub", roomCode)
     }
 
     return players
@@ -134,20 +181,27 @@ func (h *Hub) GetPlayerCount(roomCode string) int {
     defer h.mu.RUnlock()
 
     if room, exists := h.rooms[roomCode]; exists {
-        count := len(room)
-        log.Printf("Room %s has %d players", roomCode, count)
-        return count
+        return len(room)
     }
-    
-    log.Printf("Room %s not found in hub or empty", roomCode)
     return 0
 }
 
+// IsPlayerConnected checks if a player is currently connected to a room
+func (h *Hub) IsPlayerConnected(roomCode string, playerID string) bool {
+    h.mu.RLock()
+    defer h.mu.RUnlock()
+
+    if room, exists := h.rooms[roomCode]; exists {
+        _, ok := room[playerID]
+        return ok
+    }
+    return false
+}
+
 // BroadcastToRoom updated for better logging
 func (h *Hub) BroadcastToRoom(roomCode string, event GameEvent) {
     event.RoomID = roomCode
-    log.Printf("Broadcasting %s event to room %s", event.Type, roomCode)
-    
+
     // Update room last activity if needed
     if h.roomService != nil {
         h.roomService.UpdateRoomActivity(roomCode)
@@ -161,8 +215,14 @@ func (h *Hub) handleUnregister(client *Client) {
     h.mu.Lock()
     defer h.mu.Unlock()
 
-    if room, exists := h.rooms[client.RoomID]; exists {
-        if _, ok := room[client.ID]; ok {
+    if !client.registered {
+        return
+    }
+
+    if room, exists := h.rooms[client.hubRoom]; exists {
+        // Only remove this exact client; a reconnect may already have
+        // replaced it under the same ID
+        if existing, ok := room[client.hubID]; ok && existing == client {
             // Store in disconnected clients before removing
             if client.Username != "" {
                 // Initialize map for this room if it doesn't exist
@@ -176,26 +236,40 @@ func (h *Hub) handleUnregister(client *Client) {
                 // Store disconnect time
                 h.disconnectTimes[client.ID] = time.Now()
                 
-                log.Printf("Added client %s (%s) to disconnected clients for room %s", 
-                    client.ID, client.Username, client.RoomID)
+                slog.Debug("Remembering disconnected client", "room", client.RoomID, "player", client.ID, "username", client.Username)
             }
             
-            delete(room, client.ID)
+            delete(room, client.hubID)
             close(client.send)
+            client.registered = false
+            client.closed = true
+            h.metrics.ClientDisconnected()
             playerCount := len(room)
             
-            log.Printf("Client %s left room %s (remaining players: %d)", 
-                client.ID, client.RoomID, playerCount)
+            slog.Info("Client left", "room", client.hubRoom, "player", client.ID, "players", playerCount)
 
             // Remove room if empty
             if playerCount == 0 {
-                delete(h.rooms, client.RoomID)
-                log.Printf("Room %s removed (empty)", client.RoomID)
+                delete(h.rooms, client.hubRoom)
+                slog.Debug("Removed empty hub room", "room", client.hubRoom)
             }
         }
     }
 }
 
+// removeClient drops a stale registration without closing the client
+func (h *Hub) removeClient(roomCode string, clientID string, client *Client) {
+    room, exists := h.rooms[roomCode]
+    if !exists || room[clientID] != client {
+        return
+    }
+
+    delete(room, clientID)
+    if len(room) == 0 {
+        delete(h.rooms, roomCode)
+    }
+}
+
 // Add cleanup routine for disconnected clients
 func (h *Hub) cleanupDisconnectedClients() {
     ticker := time.NewTicker(1 * time.Minute)
@@ -220,7 +294,7 @@ func (h *Hub) cleanupDisconnectedClients() {
                     // Remove from disconnect times
                     delete(h.disconnectTimes, playerID)
                     
-                    log.Printf("Removed expired disconnected client: %s", playerID)
+                    slog.Debug("Forgot disconnected client", "player", playerID)
                 }
             }
             
@@ -253,29 +327,105 @@ func (h *Hub) WasPlayerInRoom(roomCode string, playerID string) (bool, string) {
 
 
 func (h *Hub) handleBroadcast(event *GameEvent) {
-    h.mu.RLock()
-    defer h.mu.RUnlock()
+    // Clients that can't keep up are dropped, so this writes to the map
+    h.mu.Lock()
+    defer h.mu.Unlock()
 
+    h.metrics.Broadcast(event.Type)
     if room, exists := h.rooms[event.RoomID]; exists {
-        log.Printf("Broadcasting %s event to room %s (%d players)", 
-            event.Type, event.RoomID, len(room))
+        slog.Debug("Broadcasting event", "room", event.RoomID, "event", event.Type, "players", len(room))
 
         for _, client := range room {
-            select {
-            case client.send <- event:
-                // Message sent successfully
-            default:
-                // Client's buffer is full, remove them
-                close(client.send)
-                delete(room, client.ID)
-                log.Printf("Removed client %s (buffer full)", client.ID)
-            }
+            h.deliver(room, client, event)
+        }
+    }
+}
+
+// BroadcastToAll sends an event to every connected client, whether or not
+// it has joined a room. It returns once the event is queued for them.
+func (h *Hub) BroadcastToAll(event GameEvent) {
+    req := broadcastAllRequest{event: &event, done: make(chan struct{})}
+    h.broadcastAll <- req
+    <-req.done
+}
+
+func (h *Hub) handleBroadcastAll(event *GameEvent) {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+
+    h.metrics.Broadcast(event.Type)
+    for roomCode, room := range h.rooms {
+        for _, client := range room {
+            sent := *event
+            sent.RoomID = roomCode
+            h.deliver(room, client, &sent)
+        }
+    }
+}
+
+// deliver queues an event for a client in room. A client whose buffer is
+// full is removed. Call it with the lock held.
+func (h *Hub) deliver(room map[string]*Client, client *Client, event *GameEvent) {
+    select {
+    case client.send <- event:
+        // Message sent successfully
+    default:
+        // Client's buffer is full, remove them
+        close(client.send)
+        delete(room, client.hubID)
+        client.registered = false
+        client.closed = true
+        h.metrics.MessageDropped(event.Type)
+        h.metrics.ClientDisconnected()
+        slog.Warn("Removed client with a full buffer", "room", client.hubRoom, "player", client.ID, "event", event.Type)
+    }
+}
+
+// DisconnectAll drops every client. WebSocket connections are closed with
+// a going-away close frame and event streams end.
+func (h *Hub) DisconnectAll() {
+    h.mu.RLock()
+    var clients []*Client
+    for _, room := range h.rooms {
+        for _, client := range room {
+            clients = append(clients, client)
+        }
+    }
+    h.mu.RUnlock()
+
+    // Give the write pumps a moment to send what's queued, such as
+    // server_shutdown
+    for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); {
+        queued := 0
+        for _, client := range clients {
+            queued += len(client.send)
+        }
+        if queued == 0 {
+            break
         }
+        time.Sleep(10 * time.Millisecond)
     }
+
+    // The read pumps see the closed connections and unregister their clients
+    for _, client := range clients {
+        if client.conn != nil {
+            client.closeGoingAway()
+        } else {
+            h.Unregister <- client
+        }
+    }
+    slog.Info("Disconnected all clients", "clients", len(clients))
 }
 
-// SendToClient sends a message to a specific client
+// SendToClient sends a message to a specific client. Clients the hub has
+// dropped are skipped.
 func (h *Hub) SendToClient(client *Client, event GameEvent) error {
+    h.mu.RLock()
+    defer h.mu.RUnlock()
+
+    if client.closed {
+        return nil
+    }
     select {
     case client.send <- &event:
         return nil
@@ -0,0 +1,78 @@
+// internal/websocket/hub_test.go
+
+package websocket
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Run with -race: broadcasts to a room and to everyone overflow clients
+// that never read, so the hub drops them while both kinds are going out
+func TestBroadcastToAllWhileClientsOverflow(t *testing.T) {
+    hub := NewHub()
+    go hub.Run()
+
+    for i := 0; i < 4; i++ {
+        client := NewEventStreamClient(hub, fmt.Sprintf("player%d", i))
+        client.RoomID = "ROOM01"
+        hub.Register <- client
+    }
+    lobby := NewEventStreamClient(hub, "lobby")
+    hub.Register <- lobby
+
+    var wg sync.WaitGroup
+    wg.Add(2)
+    go func() {
+        defer wg.Done()
+        for i := 0; i < 600; i++ {
+            hub.BroadcastToRoom("ROOM01", GameEvent{Type: "tick"})
+        }
+    }()
+    go func() {
+        defer wg.Done()
+        for i := 0; i < 600; i++ {
+            hub.BroadcastToAll(GameEvent{Type: "server_shutdown"})
+        }
+    }()
+    wg.Wait()
+
+    // Every client overflowed and was dropped, its channel closed
+    deadline := time.Now().Add(2 * time.Second)
+    for hub.GetPlayerCount("ROOM01") > 0 || hub.GetPlayerCount("") > 0 {
+        if time.Now().After(deadline) {
+            t.Fatalf("clients left after overflowing: %d in the room, %d in the lobby",
+                hub.GetPlayerCount("ROOM01"), hub.GetPlayerCount(""))
+        }
+        hub.BroadcastToAll(GameEvent{Type: "server_shutdown"})
+    }
+    for range lobby.Events() {
+    }
+}
+
+// A client's transport can still be handling a message after the hub has
+// dropped it and closed its channel
+func TestSendToDroppedClient(t *testing.T) {
+    hub := NewHub()
+    go hub.Run()
+
+    client := NewEventStreamClient(hub, "player")
+    hub.Register <- client
+    hub.Unregister <- client
+    for range client.Events() {
+    }
+
+    if err := hub.SendToClient(client, GameEvent{Type: "ack"}); err != nil {
+        t.Errorf("got %v sending to a dropped client", err)
+    }
+
+    // Joining a room afterwards doesn't bring it back
+    client.RoomID = "ROOM01"
+    hub.Register <- client
+    hub.BroadcastToRoom("ROOM01", GameEvent{Type: "tick"})
+    if count := hub.GetPlayerCount("ROOM01"); count != 0 {
+        t.Errorf("got %d players after re-registering a dropped client, want 0", count)
+    }
+}
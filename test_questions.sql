@@ -1,13 +1,13 @@
 -- test_questions.sql
 
 INSERT INTO questions (id, content, answer, created_at) VALUES
-    (gen_random_uuid(), 'What is the capital of France?', 'Paris', NOW()),
-    (gen_random_uuid(), 'What is 2 + 2?', '4', NOW()),
-    (gen_random_uuid(), 'Who painted the Mona Lisa?', 'Leonardo da Vinci', NOW()),
-    (gen_random_uuid(), 'What planet is known as the Red Planet?', 'Mars', NOW()),
-    (gen_random_uuid(), 'What is the largest mammal in the world?', 'Blue Whale', NOW()),
-    (gen_random_uuid(), 'What is the chemical symbol for Gold?', 'Au', NOW()),
-    (gen_random_uuid(), 'Which programming language has a snake as its logo?', 'Python', NOW()),
-    (gen_random_uuid(), 'What year did World War II end?', '1945', NOW()),
-    (gen_random_uuid(), 'What is the square root of 64?', '8', NOW()),
-    (gen_random_uuid(), 'Who wrote Romeo and Juliet?', 'William Shakespeare', NOW());
\ No newline at end of file
+    ('e7daab35-d015-4899-8c5e-e3122eb38f2b', 'What is the capital of France?', 'Paris', CURRENT_TIMESTAMP),
+    ('10e87022-d489-436b-951a-a04b0ec82ac3', 'What is 2 + 2?', '4', CURRENT_TIMESTAMP),
+    ('ec947914-0ccb-4e1b-a9e3-76905da0b13d', 'Who painted the Mona Lisa?', 'Leonardo da Vinci', CURRENT_TIMESTAMP),
+    ('fead5123-e2f8-4b08-9889-4d2f97b01acd', 'What planet is known as the Red Planet?', 'Mars', CURRENT_TIMESTAMP),
+    ('abe03eb1-49ad-4c12-a277-f0b847e38817', 'What is the largest mammal in the world?', 'Blue Whale', CURRENT_TIMESTAMP),
+    ('c39d0876-866d-435d-b963-d61a862e4d53', 'What is the chemical symbol for Gold?', 'Au', CURRENT_TIMESTAMP),
+    ('a0092e1a-329b-4934-b7fe-735d001b1d2f', 'Which programming language has a snake as its logo?', 'Python', CURRENT_TIMESTAMP),
+    ('47cc4411-0001-4b1c-8ce8-aaf2fbac0423', 'What year did World War II end?', '1945', CURRENT_TIMESTAMP),
+    ('c41e6bd0-ef92-444c-93ac-9219ba3c34d6', 'What is the square root of 64?', '8', CURRENT_TIMESTAMP),
+    ('d4b67bdb-d2dd-4d44-b51c-70118519b94b', 'Who wrote Romeo and Juliet?', 'William Shakespeare', CURRENT_TIMESTAMP);
\ No newline at end of file